@@ -10,13 +10,22 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	customerDBModels "kredit-plus/app/db/dto/customer"
 	customerTokenDBModels "kredit-plus/app/db/dto/customer_token"
+	customerDBClient "kredit-plus/app/db/repository/customer"
 	customerTokenDBClient "kredit-plus/app/db/repository/customer_token"
 
 	"kredit-plus/app/api/middleware/jwt"
+	"kredit-plus/app/service/correlation"
+	"kredit-plus/app/service/logger"
+	"kredit-plus/app/service/verification"
 )
 
-func Authenticated(JWT jwt.IJWTService, customerTokenDBClient customerTokenDBClient.ICustomerTokenRepository) gin.HandlerFunc {
+// LAST_SEEN_INTERVAL is how stale the last seen time of a session may get before a
+// request updates it, so that not every request writes to the database.
+const LAST_SEEN_INTERVAL = time.Minute
+
+func Authenticated(JWT jwt.IJWTService, customerDBClient customerDBClient.ICustomerRepository, customerTokenDBClient customerTokenDBClient.ICustomerTokenRepository) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		token, err := getHeaderToken(ctx)
 		if err != nil {
@@ -31,7 +40,7 @@ func Authenticated(JWT jwt.IJWTService, customerTokenDBClient customerTokenDBCli
 		}
 
 		filter := map[string]interface{}{
-			customerTokenDBModels.COLUMN_ACCESS_TOKEN: token,
+			customerTokenDBModels.COLUMN_ACCESS_TOKEN_HASH: verification.HashToken(token),
 		}
 
 		customerToken, err := customerTokenDBClient.Get(ctx, filter)
@@ -50,7 +59,43 @@ func Authenticated(JWT jwt.IJWTService, customerTokenDBClient customerTokenDBCli
 			return
 		}
 
+		customer, err := customerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_ID: customerToken.CustomerID})
+		if err != nil {
+			controller.RespondWithError(ctx, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, err)
+			return
+		}
+
+		if !customer.IsActive() {
+			controller.RespondWithError(ctx, http.StatusForbidden, constants.FORBIDDEN, errors.New(constants.ACCOUNT_NOT_ACTIVE))
+			return
+		}
+
+		// Tokens issued before roles were added carry none and belong to customers
+		role := claims.Role
+		if role == "" {
+			role = constants.USER.String()
+		}
+
+		// A token issued for another role must not keep its permissions after the role changed
+		if role != customer.Role {
+			controller.RespondWithError(ctx, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.ROLE_CHANGED))
+			return
+		}
+
+		if customerToken.LastSeenAt == nil || time.Since(*customerToken.LastSeenAt) > LAST_SEEN_INTERVAL {
+			patcher := map[string]interface{}{
+				customerTokenDBModels.COLUMN_LAST_SEEN_AT: time.Now(),
+			}
+
+			// The request is authenticated either way, a stale last seen time is harmless
+			if err := customerTokenDBClient.Update(ctx, map[string]interface{}{customerTokenDBModels.COLUMN_ID: customerToken.ID}, patcher); err != nil {
+				logger.Logger(correlation.WithReqContext(ctx)).Errorf("failed to update the last seen time of session %d: %v", customerToken.ID, err)
+			}
+		}
+
 		ctx.Set(constants.CTK_CLAIM_KEY.String(), claims.UserUUID)
+		ctx.Set(constants.CTK_TOKEN_ID_KEY.String(), customerToken.ID)
+		ctx.Set(constants.CTK_ROLE_KEY.String(), customer.Role)
 		ctx.Next()
 	}
 }
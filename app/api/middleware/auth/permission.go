@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"errors"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/controller"
+	"kredit-plus/app/service/rbac"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission only lets requests through whose role has been granted the permission.
+// It runs after Authenticated, which puts the role of the caller on the context.
+func RequirePermission(RBAC rbac.IRBACService, permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		allowed, err := RBAC.HasPermission(ctx, ctx.GetString(constants.CTK_ROLE_KEY.String()), permission)
+		if err != nil {
+			controller.RespondWithError(ctx, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+			return
+		}
+
+		if !allowed {
+			controller.RespondWithError(ctx, http.StatusForbidden, constants.FORBIDDEN, errors.New(constants.PERMISSION_DENIED))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// RequireSelfOrPermission lets customers act on their own account, identified by the
+// given path parameter, and callers holding the permission act on any account.
+func RequireSelfOrPermission(RBAC rbac.IRBACService, param string, permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if userUUID, ok := ctx.Get(constants.CTK_CLAIM_KEY.String()); ok && userUUID == ctx.Param(param) {
+			ctx.Next()
+			return
+		}
+
+		RequirePermission(RBAC, permission)(ctx)
+	}
+}
@@ -14,13 +14,22 @@ import (
 type IJWTService interface {
 	GenerateToken(ctx context.Context, user customerDBModels.Customer) (TokenDetails, error)
 	ParseToken(ctx context.Context, tokenString string) (*JWTToken, error)
-	RefreshToken(ctx context.Context, refreshToken string) (TokenDetails, error)
+	ParseRefreshToken(ctx context.Context, refreshToken string) (*JWTToken, error)
+	RefreshToken(ctx context.Context, refreshToken string, user customerDBModels.Customer, familyID uuid.UUID) (TokenDetails, error)
+	JWKS() JWKS
 }
 
-type JWTService struct{}
+// JWTService signs access tokens with the key set, so other services can verify them with
+// the published public keys. Refresh tokens are only read by this service and stay signed
+// with JWT_REFRESH_SECRET.
+type JWTService struct {
+	Keys *KeySet
+}
 
-func NewJWTService() *JWTService {
-	return &JWTService{}
+func NewJWTService(Keys *KeySet) *JWTService {
+	return &JWTService{
+		Keys: Keys,
+	}
 }
 
 type TokenDetails struct {
@@ -28,40 +37,33 @@ type TokenDetails struct {
 	RefreshToken string `json:"refresh_token"`
 	AtExpires    int64  `json:"at_expires"`
 	RtExpires    int64  `json:"rt_expires"`
+
+	// FamilyID is shared by every refresh token rotated from the same sign in,
+	// RefreshTokenID is the jti of this refresh token
+	FamilyID       uuid.UUID `json:"-"`
+	RefreshTokenID uuid.UUID `json:"-"`
 }
 
 type JWTToken struct {
 	UserUUID string `json:"user_uuid"`
+	Role     string `json:"role,omitempty"`
+	// Family is the refresh token family, only set in refresh tokens
+	Family string `json:"fid,omitempty"`
 	jwt.StandardClaims
 }
 
+// GenerateToken issues the tokens of a new sign in, which starts a refresh token family.
 func (s *JWTService) GenerateToken(ctx context.Context, user customerDBModels.Customer) (TokenDetails, error) {
-	tokenDetails := TokenDetails{}
-
-	// Generate access token
-	accessToken, atExpires, err := generateAccessToken(user)
+	familyID, err := uuid.NewRandom()
 	if err != nil {
-		return tokenDetails, err
-	}
-
-	// Generate refresh token
-	refreshToken, rtExpires, err := generateRefreshToken(user)
-	if err != nil {
-		return tokenDetails, err
+		return TokenDetails{}, err
 	}
 
-	tokenDetails.AccessToken = accessToken
-	tokenDetails.AtExpires = atExpires
-	tokenDetails.RefreshToken = refreshToken
-	tokenDetails.RtExpires = rtExpires
-
-	return tokenDetails, nil
+	return s.generateTokens(user, familyID)
 }
 
 func (s *JWTService) ParseToken(ctx context.Context, tokenString string) (*JWTToken, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTToken{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(constants.Config.JwtConfig.JWT_ACCESS_SECRET), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &JWTToken{}, s.Keys.Keyfunc)
 
 	if err != nil {
 		return nil, err
@@ -74,7 +76,15 @@ func (s *JWTService) ParseToken(ctx context.Context, tokenString string) (*JWTTo
 	return nil, jwt.ErrSignatureInvalid
 }
 
-func (s *JWTService) RefreshToken(ctx context.Context, refreshToken string) (TokenDetails, error) {
+// ParseRefreshToken checks the signature and expiry of a refresh token and returns its claims.
+func (s *JWTService) ParseRefreshToken(ctx context.Context, refreshToken string) (*JWTToken, error) {
+	return parseRefreshToken(refreshToken)
+}
+
+// RefreshToken issues new tokens for the user the refresh token was issued to. The user is
+// loaded by the caller so that the new access token carries the current role. The new
+// refresh token stays in the family of the session but gets a jti of its own.
+func (s *JWTService) RefreshToken(ctx context.Context, refreshToken string, user customerDBModels.Customer, familyID uuid.UUID) (TokenDetails, error) {
 	// Parse the refresh token to extract user information
 	claims, err := parseRefreshToken(refreshToken)
 	if err != nil {
@@ -86,49 +96,81 @@ func (s *JWTService) RefreshToken(ctx context.Context, refreshToken string) (Tok
 		return TokenDetails{}, jwt.ErrInvalidKey
 	}
 
-	// Generate a new access token
-	userUUIDString := claims.UserUUID
-
-	userUUID, err := uuid.Parse(userUUIDString)
+	userUUID, err := uuid.Parse(claims.UserUUID)
 	if err != nil {
 		return TokenDetails{}, err
 	}
 
-	user := customerDBModels.Customer{UUID: userUUID} // Create a user object with the user UUID
-	newAccessToken, atExpires, err := generateAccessToken(user)
+	if userUUID != user.UUID {
+		return TokenDetails{}, jwt.ErrInvalidKey
+	}
+
+	// Refresh tokens issued before families were added carry none
+	if claims.Family != "" && claims.Family != familyID.String() {
+		return TokenDetails{}, jwt.ErrInvalidKey
+	}
+
+	return s.generateTokens(user, familyID)
+}
+
+// JWKS returns the public keys access tokens can be verified with.
+func (s *JWTService) JWKS() JWKS {
+	return s.Keys.JWKS()
+}
+
+func (s *JWTService) generateTokens(user customerDBModels.Customer, familyID uuid.UUID) (TokenDetails, error) {
+	tokenDetails := TokenDetails{
+		FamilyID: familyID,
+	}
+
+	// Generate access token
+	accessToken, atExpires, err := s.generateAccessToken(user)
 	if err != nil {
-		return TokenDetails{}, err
+		return tokenDetails, err
 	}
 
-	// Generate a new refresh token (optional, depending on your use case)
-	newRefreshToken, rtExpires, err := generateRefreshToken(user)
+	// Generate refresh token
+	refreshTokenID, err := uuid.NewRandom()
 	if err != nil {
-		return TokenDetails{}, err
+		return tokenDetails, err
 	}
 
-	tokenDetails := TokenDetails{
-		AccessToken:  newAccessToken,
-		RefreshToken: newRefreshToken,
-		AtExpires:    atExpires,
-		RtExpires:    rtExpires,
+	refreshToken, rtExpires, err := generateRefreshToken(user, familyID, refreshTokenID)
+	if err != nil {
+		return tokenDetails, err
 	}
 
+	tokenDetails.AccessToken = accessToken
+	tokenDetails.AtExpires = atExpires
+	tokenDetails.RefreshToken = refreshToken
+	tokenDetails.RtExpires = rtExpires
+	tokenDetails.RefreshTokenID = refreshTokenID
+
 	return tokenDetails, nil
 }
 
-func generateAccessToken(user customerDBModels.Customer) (string, int64, error) {
+func (s *JWTService) generateAccessToken(user customerDBModels.Customer) (string, int64, error) {
+	// The jti keeps access tokens of sign ins within the same second apart
 	claims := jwt.MapClaims{
 		"user_uuid": user.UUID.String(),
+		"role":      user.Role,
+		"jti":       uuid.New().String(),
 		"exp":       time.Now().Add(time.Minute * time.Duration(constants.Config.JwtConfig.JWT_ACCESS_EXP)).Unix(),
 		"iat":       time.Now().Unix(),
 	}
 
-	return generateToken(claims, constants.Config.JwtConfig.JWT_ACCESS_SECRET)
+	tokenString, err := s.Keys.Sign(claims)
+	if err != nil {
+		return "", 0, err
+	}
+	return tokenString, claims["exp"].(int64), nil
 }
 
-func generateRefreshToken(user customerDBModels.Customer) (string, int64, error) {
+func generateRefreshToken(user customerDBModels.Customer, familyID uuid.UUID, refreshTokenID uuid.UUID) (string, int64, error) {
 	claims := jwt.MapClaims{
 		"user_uuid": user.UUID.String(),
+		"jti":       refreshTokenID.String(),
+		"fid":       familyID.String(),
 		"exp":       time.Now().Add(time.Minute * time.Duration(constants.Config.JwtConfig.JWT_REFRESH_EXP)).Unix(),
 		"iat":       time.Now().Unix(),
 	}
@@ -0,0 +1,218 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"kredit-plus/config"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	METHOD_HS256 = "HS256"
+	METHOD_RS256 = "RS256"
+	METHOD_EDDSA = "EdDSA"
+)
+
+// KeySet holds the keys access tokens are signed and verified with. New tokens are signed
+// with the current key and carry its ID in the kid header. The other keys are retiring keys,
+// they only verify the tokens signed before the rotation until those have expired.
+type KeySet struct {
+	method       jwt.SigningMethod
+	currentKeyID string
+	signingKey   interface{}
+	verifyKeys   map[string]interface{}
+}
+
+// JWK is the public half of a signing key, as served in the JWKS document.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewKeySetFromConfig returns the key set named by JWT_SIGNING_METHOD. HS256 signs with
+// JWT_ACCESS_SECRET, RS256 and EdDSA sign with the PEM keys listed in JWT_KEYS.
+func NewKeySetFromConfig(cfg config.JwtConfig) (*KeySet, error) {
+	switch cfg.JWT_SIGNING_METHOD {
+	case "", METHOD_HS256:
+		return NewHMACKeySet(cfg.JWT_ACCESS_SECRET), nil
+	case METHOD_RS256:
+		return NewKeySet(jwt.SigningMethodRS256, cfg.JWT_CURRENT_KEY_ID, cfg.JWT_KEYS, parseRSAKey)
+	case METHOD_EDDSA:
+		return NewKeySet(jwt.SigningMethodEdDSA, cfg.JWT_CURRENT_KEY_ID, cfg.JWT_KEYS, parseEdKey)
+	default:
+		return nil, fmt.Errorf("unknown signing method %q", cfg.JWT_SIGNING_METHOD)
+	}
+}
+
+// NewHMACKeySet signs and verifies with a shared secret. Its tokens carry no kid and
+// nothing is published in the JWKS document, the secret cannot be handed to other services.
+func NewHMACKeySet(secret string) *KeySet {
+	return &KeySet{
+		method:     jwt.SigningMethodHS256,
+		signingKey: []byte(secret),
+		verifyKeys: map[string]interface{}{"": []byte(secret)},
+	}
+}
+
+// NewKeySet loads "kid:path" entries, each naming a PEM file. The current key must be a
+// private key, retiring keys may be public keys.
+func NewKeySet(method jwt.SigningMethod, currentKeyID string, entries []string, parse func(pem []byte) (crypto.PrivateKey, crypto.PublicKey, error)) (*KeySet, error) {
+	ks := &KeySet{
+		method:       method,
+		currentKeyID: currentKeyID,
+		verifyKeys:   map[string]interface{}{},
+	}
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, path, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("key entry must have the form kid:path")
+		}
+
+		if _, exist := ks.verifyKeys[id]; exist {
+			return nil, fmt.Errorf("key %q is listed twice", id)
+		}
+
+		data, err := os.ReadFile(strings.TrimSpace(path))
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+
+		privateKey, publicKey, err := parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+
+		ks.verifyKeys[id] = publicKey
+
+		if id == currentKeyID {
+			if privateKey == nil {
+				return nil, fmt.Errorf("current key %q must be a private key", id)
+			}
+			ks.signingKey = privateKey
+		}
+	}
+
+	if _, exist := ks.verifyKeys[currentKeyID]; !exist {
+		return nil, fmt.Errorf("current key %q is not in the key set", currentKeyID)
+	}
+
+	return ks, nil
+}
+
+// Sign signs the claims with the current key.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.method, claims)
+	if ks.currentKeyID != "" {
+		token.Header["kid"] = ks.currentKeyID
+	}
+
+	return token.SignedString(ks.signingKey)
+}
+
+// Keyfunc returns the key a token was signed with. Only the configured algorithm is
+// accepted, so a token cannot pick a weaker one, e.g. HS256 with the public key as secret.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if token.Method.Alg() != ks.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if ks.currentKeyID == "" {
+		kid = ""
+	}
+
+	key, exist := ks.verifyKeys[kid]
+	if !exist {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	return key, nil
+}
+
+// JWKS returns the public keys of the set, ordered by kid.
+func (ks *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+
+	for kid, key := range ks.verifyKeys {
+		jwk := JWK{
+			Use: "sig",
+			Alg: ks.method.Alg(),
+			Kid: kid,
+		}
+
+		switch key := key.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(key)
+		default:
+			// Shared secrets are never published
+			continue
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].Kid < jwks.Keys[j].Kid
+	})
+
+	return jwks
+}
+
+func parseRSAKey(data []byte) (crypto.PrivateKey, crypto.PublicKey, error) {
+	if privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return privateKey, &privateKey.PublicKey, nil
+	}
+
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("not an RSA key in PEM format")
+	}
+
+	return nil, publicKey, nil
+}
+
+func parseEdKey(data []byte) (crypto.PrivateKey, crypto.PublicKey, error) {
+	if privateKey, err := jwt.ParseEdPrivateKeyFromPEM(data); err == nil {
+		if privateKey, ok := privateKey.(ed25519.PrivateKey); ok {
+			return privateKey, privateKey.Public(), nil
+		}
+	}
+
+	publicKey, err := jwt.ParseEdPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("not an Ed25519 key in PEM format")
+	}
+
+	return nil, publicKey, nil
+}
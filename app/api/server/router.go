@@ -4,6 +4,7 @@ import (
 	"context"
 	"kredit-plus/app/constants"
 	"kredit-plus/app/controller/healthcheck"
+	"kredit-plus/app/controller/wellknown"
 	"kredit-plus/app/db"
 	"kredit-plus/app/service/logger"
 	"strings"
@@ -13,17 +14,55 @@ import (
 	"kredit-plus/app/api/middleware/jwt"
 	loggerMiddleware "kredit-plus/app/api/middleware/log"
 
+	adminController "kredit-plus/app/controller/admin"
 	customerController "kredit-plus/app/controller/customer"
+	creditScoreDBClient "kredit-plus/app/db/repository/credit_score"
 	customerDBClient "kredit-plus/app/db/repository/customer"
+	customerAddressDBClient "kredit-plus/app/db/repository/customer_address"
+	customerEmergencyContactDBClient "kredit-plus/app/db/repository/customer_emergency_contact"
+	customerEmploymentDBClient "kredit-plus/app/db/repository/customer_employment"
 	customerLimitDBClient "kredit-plus/app/db/repository/customer_limit"
+	customerLimitHistoryDBClient "kredit-plus/app/db/repository/customer_limit_history"
+	customerLimitReviewDBClient "kredit-plus/app/db/repository/customer_limit_review"
+	customerOtpDBClient "kredit-plus/app/db/repository/customer_otp"
 	customerProfileDBClient "kredit-plus/app/db/repository/customer_profile"
+	customerProfileVersionDBClient "kredit-plus/app/db/repository/customer_profile_version"
+	customerStatusHistoryDBClient "kredit-plus/app/db/repository/customer_status_history"
 	customerTokenDBClient "kredit-plus/app/db/repository/customer_token"
+	customerVerificationTokenDBClient "kredit-plus/app/db/repository/customer_verification_token"
+	duplicateMatchDBClient "kredit-plus/app/db/repository/duplicate_match"
+	kycVerificationDBClient "kredit-plus/app/db/repository/kyc_verification"
+	limitIncreaseRequestDBClient "kredit-plus/app/db/repository/limit_increase_request"
+	roleDBClient "kredit-plus/app/db/repository/role"
+	rolePermissionDBClient "kredit-plus/app/db/repository/role_permission"
+	securityEventDBClient "kredit-plus/app/db/repository/security_event"
 
 	transactionController "kredit-plus/app/controller/transaction"
 	transactionDBClient "kredit-plus/app/db/repository/transaction"
 
 	assetDBClient "kredit-plus/app/db/repository/asset"
 
+	"kredit-plus/app/service/account"
+	"kredit-plus/app/service/credit"
+	"kredit-plus/app/service/duplicate"
+	"kredit-plus/app/service/eligibility"
+	"kredit-plus/app/service/encryption"
+	"kredit-plus/app/service/kyc"
+	limitService "kredit-plus/app/service/limit"
+	"kredit-plus/app/service/mailer"
+	"kredit-plus/app/service/nik"
+	"kredit-plus/app/service/otp"
+	"kredit-plus/app/service/password"
+	"kredit-plus/app/service/privacy"
+	"kredit-plus/app/service/profile"
+	"kredit-plus/app/service/rbac"
+	"kredit-plus/app/service/review"
+	"kredit-plus/app/service/scope"
+	"kredit-plus/app/service/scoring"
+	"kredit-plus/app/service/sms"
+	"kredit-plus/app/service/storage"
+	"kredit-plus/app/service/verification"
+
 	helmet "github.com/danielkov/gin-helmet"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -101,30 +140,142 @@ func NewRouter(ctx context.Context, dbConnection *db.DBService) *gin.Engine {
 	router.Use(uuidInjectionMiddleware())
 	router.Use(loggerMiddleware.LoggerMiddleware())
 
+	cipher, err := encryption.NewCipherFromConfig(constants.Config.EncryptionConfig)
+	if err != nil {
+		log.Fatalf("Encryption could not be configured: %v", err)
+	}
+
+	if err := nik.CheckRegions(); err != nil {
+		log.Fatalf("NIK region table could not be loaded: %v", err)
+	}
+
 	// DB Clients
 	var (
 		customerDBClient        = customerDBClient.NewCustomerRepository(dbConnection)
-		customerProfileDBClient = customerProfileDBClient.NewCustomerProfileRepository(dbConnection)
+		customerProfileDBClient = customerProfileDBClient.NewCustomerProfileRepository(dbConnection, cipher)
 		customerTokenDBClient   = customerTokenDBClient.NewCustomerTokenRepository(dbConnection)
 		customerLimitDBClient   = customerLimitDBClient.NewCustomerLimitRepository(dbConnection)
+		creditScoreDBClient     = creditScoreDBClient.NewCreditScoreRepository(dbConnection)
+
+		customerLimitHistoryDBClient  = customerLimitHistoryDBClient.NewCustomerLimitHistoryRepository(dbConnection)
+		customerLimitReviewDBClient   = customerLimitReviewDBClient.NewCustomerLimitReviewRepository(dbConnection)
+		customerStatusHistoryDBClient = customerStatusHistoryDBClient.NewCustomerStatusHistoryRepository(dbConnection)
+		limitIncreaseRequestDBClient  = limitIncreaseRequestDBClient.NewLimitIncreaseRequestRepository(dbConnection)
+		kycVerificationDBClient       = kycVerificationDBClient.NewKycVerificationRepository(dbConnection, cipher)
+
+		customerProfileVersionDBClient = customerProfileVersionDBClient.NewCustomerProfileVersionRepository(dbConnection, cipher)
+		duplicateMatchDBClient         = duplicateMatchDBClient.NewDuplicateMatchRepository(dbConnection)
+
+		customerAddressDBClient          = customerAddressDBClient.NewCustomerAddressRepository(dbConnection)
+		customerEmploymentDBClient       = customerEmploymentDBClient.NewCustomerEmploymentRepository(dbConnection)
+		customerEmergencyContactDBClient = customerEmergencyContactDBClient.NewCustomerEmergencyContactRepository(dbConnection)
+
+		customerVerificationTokenDBClient = customerVerificationTokenDBClient.NewCustomerVerificationTokenRepository(dbConnection)
+		customerOtpDBClient               = customerOtpDBClient.NewCustomerOtpRepository(dbConnection)
+
+		roleDBClient           = roleDBClient.NewRoleRepository(dbConnection)
+		rolePermissionDBClient = rolePermissionDBClient.NewRolePermissionRepository(dbConnection)
+		securityEventDBClient  = securityEventDBClient.NewSecurityEventRepository(dbConnection)
 
 		transactionDBClient = transactionDBClient.NewTransactionRepository(dbConnection)
 		assetDBClient       = assetDBClient.NewAssetRepository(dbConnection)
 	)
 
+	jwtKeys, err := jwt.NewKeySetFromConfig(constants.Config.JwtConfig)
+	if err != nil {
+		log.Fatalf("JWT signing keys could not be loaded: %v", err)
+	}
+
 	// SERVICES
 	var (
-		JWT = jwt.NewJWTService()
+		JWT            = jwt.NewJWTService(jwtKeys)
+		LimitService   = limitService.NewLimitService(customerLimitDBClient)
+		AccountService = account.NewAccountService(customerDBClient, customerTokenDBClient, customerStatusHistoryDBClient)
+		ProfileService = profile.NewProfileService(customerProfileDBClient, customerProfileVersionDBClient)
+		RBACService    = rbac.NewRBACService(customerDBClient, roleDBClient, rolePermissionDBClient)
+		ScopeService   = scope.NewScopeService(customerDBClient, RBACService)
 	)
 
+	scoringEngine, err := scoring.NewScoringEngineFromConfig(constants.Config.ScoringConfig)
+	if err != nil {
+		log.Fatalf("Scoring engine could not be configured: %v", err)
+	}
+
+	blobStore, err := storage.NewLocalBlobStore(constants.Config.StorageConfig.STORAGE_LOCAL_ROOT)
+	if err != nil {
+		log.Fatalf("Blob store could not be configured: %v", err)
+	}
+
+	URLSigner, err := storage.NewURLSigner(constants.Config.StorageConfig.STORAGE_SIGNING_SECRET, constants.Config.StorageConfig.STORAGE_DOWNLOAD_URL, time.Duration(constants.Config.StorageConfig.STORAGE_URL_TTL_SECONDS)*time.Second)
+	if err != nil {
+		log.Fatalf("Download URLs could not be configured: %v", err)
+	}
+
+	var (
+		ImageRules = kyc.NewImageRules(constants.Config.KycConfig)
+
+		PasswordPolicy    = password.NewPolicy(constants.Config.PasswordPolicyConfig)
+		EligibilityPolicy = eligibility.NewPolicy(constants.Config.EligibilityConfig)
+	)
+
+	Mailer, err := mailer.NewMailerFromConfig(constants.Config.MailConfig)
+	if err != nil {
+		log.Fatalf("Mailer could not be configured: %v", err)
+	}
+
+	SMSSender, err := sms.NewSenderFromConfig(constants.Config.SmsConfig)
+	if err != nil {
+		log.Fatalf("SMS sender could not be configured: %v", err)
+	}
+
+	OTPService, err := otp.NewOTPService(customerOtpDBClient, constants.Config.OtpConfig)
+	if err != nil {
+		log.Fatalf("OTP service could not be configured: %v", err)
+	}
+
+	var (
+		EmailVerificationService = verification.NewEmailVerificationService(Mailer, customerDBClient, customerVerificationTokenDBClient, constants.Config.VerificationConfig)
+		PhoneVerificationService = verification.NewPhoneVerificationService(SMSSender, OTPService, customerDBClient)
+	)
+
+	PasswordResetService, err := verification.NewPasswordResetService(Mailer, SMSSender, customerDBClient, customerTokenDBClient, customerVerificationTokenDBClient, constants.Config.PasswordResetConfig)
+	if err != nil {
+		log.Fatalf("Password reset could not be configured: %v", err)
+	}
+
+	kycProvider, err := kyc.NewProviderFromConfig(constants.Config.KycConfig)
+	if err != nil {
+		log.Fatalf("KYC provider could not be configured: %v", err)
+	}
+
+	var (
+		CreditService    = credit.NewCreditService(scoringEngine, creditScoreDBClient, customerDBClient, customerLimitDBClient, LimitService, duplicateMatchDBClient)
+		KycService       = kyc.NewKycService(kycProvider, blobStore, kycVerificationDBClient, customerDBClient, customerProfileDBClient, CreditService, constants.Config.KycConfig.KYC_FACE_MATCH_THRESHOLD)
+		DuplicateService = duplicate.NewDuplicateService(customerDBClient, customerProfileDBClient, duplicateMatchDBClient, CreditService)
+	)
+
+	PrivacyService := privacy.NewPrivacyService(customerDBClient, customerProfileDBClient, customerLimitDBClient, customerTokenDBClient, transactionDBClient, kycVerificationDBClient, customerVerificationTokenDBClient, customerOtpDBClient, blobStore, AccountService, ProfileService, customerAddressDBClient, customerEmploymentDBClient, customerEmergencyContactDBClient)
+
+	// JOBS
+	limitReviewJob := review.NewLimitReviewJob(customerDBClient, customerLimitDBClient, customerProfileDBClient, customerLimitReviewDBClient, CreditService, LimitService, dbConnection)
+	go limitReviewJob.Start(ctx)
+
+	duplicateScanJob := duplicate.NewDuplicateScanJob(DuplicateService, dbConnection)
+	go duplicateScanJob.Start(ctx)
+
 	// Controller
 	var (
 		healthCheckController = healthcheck.NewHealthCheckController()
+		wellKnownController   = wellknown.NewWellKnownController(JWT)
 
-		customerController    = customerController.NewCustomerController(customerDBClient, customerProfileDBClient, customerTokenDBClient, customerLimitDBClient, JWT)
-		transactionController = transactionController.NewTransactionController(transactionDBClient, customerDBClient, customerLimitDBClient, assetDBClient)
+		customerController    = customerController.NewCustomerController(customerDBClient, customerProfileDBClient, customerTokenDBClient, customerLimitDBClient, customerLimitHistoryDBClient, limitIncreaseRequestDBClient, kycVerificationDBClient, JWT, CreditService, LimitService, blobStore, URLSigner, ImageRules, KycService, EmailVerificationService, PhoneVerificationService, PasswordResetService, PasswordPolicy, PrivacyService, ProfileService, DuplicateService, EligibilityPolicy, customerAddressDBClient, customerEmploymentDBClient, customerEmergencyContactDBClient, securityEventDBClient)
+		transactionController = transactionController.NewTransactionController(transactionDBClient, customerDBClient, customerLimitDBClient, assetDBClient, LimitService, customerProfileDBClient, EligibilityPolicy)
+		adminController       = adminController.NewAdminController(customerDBClient, customerStatusHistoryDBClient, limitIncreaseRequestDBClient, kycVerificationDBClient, CreditService, KycService, AccountService, PrivacyService, ProfileService, duplicateMatchDBClient, DuplicateService, RBACService)
 	)
 
+	// Served at the root, where other services look for it
+	router.GET(JWKS, wellKnownController.JWKS)
+
 	v1 := router.Group("/kredit-plus/v1")
 	{
 		v1.GET(HEALTH_CHECK, healthCheckController.HealthCheck)
@@ -134,38 +285,74 @@ func NewRouter(ctx context.Context, dbConnection *db.DBService) *gin.Engine {
 		{
 			v1.POST(CUSTOMER+SIGNUP, customerController.Signup)
 			v1.POST(CUSTOMER+SIGNIN, customerController.Signin)
-			v1.POST(CUSTOMER+SIGNOUT, auth.Authenticated(JWT, customerTokenDBClient), customerController.Signout)
+			v1.POST(CUSTOMER+SIGNOUT, auth.Authenticated(JWT, customerDBClient, customerTokenDBClient), customerController.Signout)
 			v1.POST(CUSTOMER+REFRESH_TOKEN, customerController.RefreshToken)
+			v1.GET(CUSTOMER+PROFILE+KYC+FILE, customerController.DownloadKycImage)
+			v1.POST(CUSTOMER+VERIFY_EMAIL, customerController.VerifyEmail)
+			v1.POST(CUSTOMER+PASSWORD+FORGOT, customerController.ForgotPassword)
+			v1.POST(CUSTOMER+PASSWORD+RESET, customerController.ResetPassword)
 
-			customer.Use(auth.Authenticated(JWT, customerTokenDBClient))
+			customer.Use(auth.Authenticated(JWT, customerDBClient, customerTokenDBClient))
+
+			customer.POST(VERIFY_EMAIL+RESEND, customerController.ResendVerificationEmail)
+			customer.POST(VERIFY_PHONE+SEND, customerController.SendPhoneVerification)
+			customer.POST(VERIFY_PHONE, customerController.VerifyPhone)
+			customer.POST(PASSWORD+CHANGE, customerController.ChangePassword)
+
+			customer.GET(DATA_EXPORT, customerController.ExportData)
+			customer.POST(DATA_ERASURE, customerController.EraseData)
 
 			customer.GET(PROFILE+DETAIL, customerController.Profile)
 			customer.POST(PROFILE, customerController.CreateCustomerProfile)
 			customer.GET(PROFILE, customerController.GetCustomerProfile)
 			customer.PATCH(PROFILE, customerController.UpdateCustomerProfile)
 			customer.DELETE(PROFILE, customerController.DeleteCustomerProfile)
+			customer.GET(PROFILE+VERSIONS, customerController.GetProfileVersions)
+			customer.GET(PROFILE+VERSIONS+DIFF, customerController.GetProfileVersionDiff)
+			customer.POST(PROFILE+KYC+TYPE, customerController.UploadKycImage)
+			customer.GET(PROFILE+KYC+TYPE+URL, customerController.GetKycImageURL)
+			customer.POST(PROFILE+ADDRESS, customerController.CreateCustomerAddress)
+			customer.GET(PROFILE+ADDRESS, customerController.GetCustomerAddresses)
+			customer.GET(PROFILE+ADDRESS+TYPE, customerController.GetCustomerAddress)
+			customer.PATCH(PROFILE+ADDRESS+TYPE, customerController.UpdateCustomerAddress)
+			customer.DELETE(PROFILE+ADDRESS+TYPE, customerController.DeleteCustomerAddress)
+			customer.POST(PROFILE+EMPLOYMENT, customerController.CreateCustomerEmployment)
+			customer.GET(PROFILE+EMPLOYMENT, customerController.GetCustomerEmployment)
+			customer.PATCH(PROFILE+EMPLOYMENT, customerController.UpdateCustomerEmployment)
+			customer.DELETE(PROFILE+EMPLOYMENT, customerController.DeleteCustomerEmployment)
+			customer.POST(PROFILE+EMPLOYMENT+INCOME_PROOF, customerController.UploadIncomeProof)
+			customer.GET(PROFILE+EMPLOYMENT+INCOME_PROOF+URL, customerController.GetIncomeProofURL)
+			customer.POST(PROFILE+EMERGENCY_CONTACT, customerController.CreateCustomerEmergencyContact)
+			customer.GET(PROFILE+EMERGENCY_CONTACT, customerController.GetCustomerEmergencyContacts)
+			customer.GET(PROFILE+EMERGENCY_CONTACT+ID, customerController.GetCustomerEmergencyContact)
+			customer.PATCH(PROFILE+EMERGENCY_CONTACT+ID, customerController.UpdateCustomerEmergencyContact)
+			customer.DELETE(PROFILE+EMERGENCY_CONTACT+ID, customerController.DeleteCustomerEmergencyContact)
+			customer.POST(KYC+VERIFICATION, customerController.SubmitKycVerification)
+			customer.GET(KYC+VERIFICATION, customerController.GetKycVerification)
 
-			customer.POST(LIMIT, customerController.CreateCustomerLimit)
 			customer.GET(LIMIT, customerController.GetCustomerLimits)
+			customer.GET(LIMIT+SUMMARY, customerController.GetCustomerLimitSummary)
+			customer.POST(LIMIT+INCREASE_REQUEST, customerController.CreateLimitIncreaseRequest)
+			customer.GET(LIMIT+INCREASE_REQUEST, customerController.GetLimitIncreaseRequests)
+			customer.GET(LIMIT+INCREASE_REQUEST+ID, customerController.GetLimitIncreaseRequest)
 			customer.GET(LIMIT+ID, customerController.GetCustomerLimit)
-			customer.PATCH(LIMIT+ID, customerController.UpdateCustomerLimit)
-			customer.DELETE(LIMIT+ID, customerController.DeleteCustomerLimit)
+			customer.GET(LIMIT+ID+HISTORY, customerController.GetCustomerLimitHistory)
 
 			customer.GET(TOKEN, customerController.GetCustomerTokens)
 			customer.GET(TOKEN+ID, customerController.GetCustomerToken)
-			customer.DELETE(TOKEN, customerController.DeleteCustomerToken)
+			customer.DELETE(TOKEN+ID, customerController.DeleteCustomerToken)
 
-			customer.POST("", customerController.CreateCustomer)
-			customer.GET("", customerController.GetCustomers)
-			customer.GET(UUID, customerController.GetCustomer)
-			customer.PATCH(UUID, customerController.UpdateCustomer)
-			customer.DELETE(UUID, customerController.DeleteCustomer)
+			customer.GET(UUID, auth.RequireSelfOrPermission(RBACService, "uuid", rbac.PERMISSION_CUSTOMERS_READ), customerController.GetCustomer)
+			customer.PATCH(UUID, auth.RequireSelfOrPermission(RBACService, "uuid", rbac.PERMISSION_CUSTOMERS_WRITE), customerController.UpdateCustomer)
+			// Customers close their own account through POST /customer/data-erasure
+			customer.DELETE(UUID, auth.RequirePermission(RBACService, rbac.PERMISSION_CUSTOMERS_DELETE), customerController.DeleteCustomer)
 		}
 
 		// Transaction
 		transaction := v1.Group(TRANSACTION)
 		{
-			transaction.Use(auth.Authenticated(JWT, customerTokenDBClient))
+			transaction.Use(auth.Authenticated(JWT, customerDBClient, customerTokenDBClient))
+			transaction.Use(auth.Scoped(ScopeService, scope.TRANSACTIONS))
 
 			transaction.POST("", transactionController.CreateTransaction)
 			transaction.GET("", transactionController.GetTransactions)
@@ -176,6 +363,38 @@ func NewRouter(ctx context.Context, dbConnection *db.DBService) *gin.Engine {
 
 			transaction.POST(CHECKOUT, transactionController.Checkout)
 		}
+
+		// Admin
+		admin := v1.Group(ADMIN)
+		{
+			admin.Use(auth.Authenticated(JWT, customerDBClient, customerTokenDBClient))
+
+			admin.GET(LIMIT+INCREASE_REQUEST, auth.RequirePermission(RBACService, rbac.PERMISSION_LIMIT_INCREASES_REVIEW), adminController.GetLimitIncreaseRequests)
+			admin.GET(LIMIT+INCREASE_REQUEST+ID, auth.RequirePermission(RBACService, rbac.PERMISSION_LIMIT_INCREASES_REVIEW), adminController.GetLimitIncreaseRequest)
+			admin.PATCH(LIMIT+INCREASE_REQUEST+ID, auth.RequirePermission(RBACService, rbac.PERMISSION_LIMIT_INCREASES_REVIEW), adminController.UpdateLimitIncreaseRequest)
+
+			admin.GET(KYC+VERIFICATION, auth.RequirePermission(RBACService, rbac.PERMISSION_KYC_REVIEW), adminController.GetKycVerifications)
+			admin.GET(KYC+VERIFICATION+ID, auth.RequirePermission(RBACService, rbac.PERMISSION_KYC_REVIEW), adminController.GetKycVerification)
+			admin.PATCH(KYC+VERIFICATION+ID, auth.RequirePermission(RBACService, rbac.PERMISSION_KYC_REVIEW), adminController.UpdateKycVerification)
+
+			admin.POST(CUSTOMER, auth.RequirePermission(RBACService, rbac.PERMISSION_CUSTOMERS_WRITE), customerController.CreateCustomer)
+			admin.GET(CUSTOMER, auth.RequirePermission(RBACService, rbac.PERMISSION_CUSTOMERS_READ), customerController.GetCustomers)
+			admin.GET(CUSTOMER+UUID, auth.RequirePermission(RBACService, rbac.PERMISSION_CUSTOMERS_READ), customerController.GetCustomer)
+			admin.PATCH(CUSTOMER+UUID, auth.RequirePermission(RBACService, rbac.PERMISSION_CUSTOMERS_WRITE), customerController.UpdateCustomer)
+			admin.DELETE(CUSTOMER+UUID, auth.RequirePermission(RBACService, rbac.PERMISSION_CUSTOMERS_DELETE), customerController.DeleteCustomer)
+			admin.PATCH(CUSTOMER+UUID+STATUS, auth.RequirePermission(RBACService, rbac.PERMISSION_CUSTOMERS_STATUS), adminController.UpdateCustomerStatus)
+			admin.GET(CUSTOMER+UUID+STATUS+HISTORY, auth.RequirePermission(RBACService, rbac.PERMISSION_CUSTOMERS_STATUS), adminController.GetCustomerStatusHistory)
+			admin.PATCH(CUSTOMER+UUID+ROLE, auth.RequirePermission(RBACService, rbac.PERMISSION_CUSTOMERS_ROLE), adminController.UpdateCustomerRole)
+			admin.POST(CUSTOMER+UUID+ERASURE, auth.RequirePermission(RBACService, rbac.PERMISSION_CUSTOMERS_ERASE), adminController.EraseCustomer)
+			admin.GET(CUSTOMER+UUID+PROFILE+VERSIONS, auth.RequirePermission(RBACService, rbac.PERMISSION_PROFILES_READ), adminController.GetCustomerProfileVersions)
+			admin.GET(CUSTOMER+UUID+PROFILE+VERSIONS+DIFF, auth.RequirePermission(RBACService, rbac.PERMISSION_PROFILES_READ), adminController.GetCustomerProfileVersionDiff)
+
+			admin.GET(DUPLICATE, auth.RequirePermission(RBACService, rbac.PERMISSION_DUPLICATES_REVIEW), adminController.GetDuplicateMatches)
+			admin.GET(DUPLICATE+ID, auth.RequirePermission(RBACService, rbac.PERMISSION_DUPLICATES_REVIEW), adminController.GetDuplicateMatch)
+			admin.PATCH(DUPLICATE+ID, auth.RequirePermission(RBACService, rbac.PERMISSION_DUPLICATES_REVIEW), adminController.UpdateDuplicateMatch)
+
+			admin.GET(ROLES, auth.RequirePermission(RBACService, rbac.PERMISSION_CUSTOMERS_ROLE), adminController.GetRoles)
+		}
 	}
 
 	return router
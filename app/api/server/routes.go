@@ -14,16 +14,56 @@ const (
 	SIGNIN        = "/signin"
 	SIGNOUT       = "/signout"
 	REFRESH_TOKEN = "/refresh-token"
+	VERIFY_EMAIL  = "/verify-email"
+	VERIFY_PHONE  = "/verify-phone"
+	SEND          = "/send"
+	PASSWORD      = "/password"
+	FORGOT        = "/forgot"
+	RESET         = "/reset"
+	CHANGE        = "/change"
+	RESEND        = "/resend"
+
+	// Admin
+	ADMIN = "/admin"
+	ROLE  = "/role"
+	ROLES = "/roles"
 
 	// Profile
 	PROFILE = "/profile"
+	KYC     = "/kyc"
+	TYPE    = "/:type"
+	URL     = "/url"
+	FILE    = "/file"
+
+	VERSIONS = "/versions"
+	DIFF     = "/diff"
+
+	ADDRESS           = "/addresses"
+	EMPLOYMENT        = "/employment"
+	INCOME_PROOF      = "/income-proof"
+	EMERGENCY_CONTACT = "/emergency-contacts"
+
+	VERIFICATION = "/verification"
+	DUPLICATE    = "/duplicates"
 
 	// Health Check
 	HEALTH_CHECK = "/health-check"
 
+	// Well known
+	JWKS = "/.well-known/jwks.json"
+
 	// Customer
 	CUSTOMER = "/customer"
 	LIMIT    = "/limit"
+	HISTORY  = "/history"
+	SUMMARY  = "/summary"
+	STATUS   = "/status"
+	ERASURE  = "/erasure"
+
+	DATA_EXPORT  = "/data-export"
+	DATA_ERASURE = "/data-erasure"
+
+	INCREASE_REQUEST = "/increase-requests"
 
 	// Transaction
 	TRANSACTION = "/transaction"
@@ -9,6 +9,9 @@ const (
 	AUTHORIZATION      = "Authorization"
 	BEARER             = "Bearer "
 	CTK_CLAIM_KEY      = CONTEXT_KEY("claims")
+	CTK_TOKEN_ID_KEY   = CONTEXT_KEY("token_id")
+	CTK_ROLE_KEY       = CONTEXT_KEY("role")
+	CTK_SCOPE_KEY      = CONTEXT_KEY("scope")
 	CORRELATION_KEY_ID = CORRELATION_KEY("X-Correlation-ID")
 	STATUS_CODE        = "status_code"
 	TIME_NOW           = "time_now"
@@ -45,8 +48,9 @@ func (c ENVIRONMENT) String() string {
 }
 
 var (
-	USER  ROLE = "user"
-	ADMIN ROLE = "admin"
+	USER    ROLE = "user"
+	ADMIN   ROLE = "admin"
+	PARTNER ROLE = "partner"
 )
 
 func (c ROLE) String() string {
@@ -18,7 +18,79 @@ const (
 	DUPLICATE_ENTRY         = "The data you're trying to add already exists in our records"
 	CONFLICT                = "There is a conflict with the current state of the resource."
 	FORBIDDEN               = "You don't have permission to access this resource"
+	TOO_MANY_REQUESTS       = "Too many requests Please try again later"
 	INSUFFICIENT_LIMIT      = "Insufficient limit, please try again later"
+	LIMIT_EXPIRED           = "Your limit has expired and is waiting for review"
+	LIMIT_SUSPENDED         = "Your limit has been suspended"
+
+	LIMIT_INCREASE_REQUEST_OPEN        = "There is already an open limit increase request for this tenor"
+	LIMIT_INCREASE_NOT_HIGHER          = "The requested amount must be higher than the current limit"
+	LIMIT_INCREASE_INVALID_TRANSITION  = "The limit increase request cannot move to this status"
+	LIMIT_INCREASE_APPROVED_NOT_HIGHER = "The approved amount must be higher than the current limit"
+
+	INVALID_NIK                = "NIK is not a valid 16 digit number"
+	INVALID_NIK_REGION         = "NIK contains an unknown province, regency or district code"
+	INVALID_NIK_DATE_OF_BIRTH  = "NIK contains an invalid date of birth"
+	NIK_DATE_OF_BIRTH_MISMATCH = "NIK does not match the date of birth"
+
+	INVALID_IMAGE_TYPE       = "Image must be a JPEG or PNG file"
+	IMAGE_TOO_LARGE          = "Image exceeds the maximum file size"
+	INVALID_IMAGE_DIMENSIONS = "Image dimensions are outside the allowed range"
+	INVALID_KYC_TYPE         = "KYC image type must be ktp or selfie"
+	INVALID_SIGNED_URL       = "The download link is invalid or has expired"
+
+	KYC_NOT_VERIFIED     = "Your identity has not been verified yet"
+	KYC_IMAGES_REQUIRED  = "Upload the KTP and selfie images before requesting verification"
+	KYC_ALREADY_PENDING  = "Your identity verification is already waiting for review"
+	KYC_ALREADY_VERIFIED = "Your identity has already been verified"
+	KYC_NOT_PENDING      = "Only pending verifications can be reviewed"
+
+	ACCOUNT_NOT_ACTIVE                = "Your account is not active"
+	ACCOUNT_INVALID_STATUS_TRANSITION = "The account cannot move to this status"
+
+	EMAIL_NOT_VERIFIED               = "Your email address has not been verified yet"
+	EMAIL_ALREADY_VERIFIED           = "Your email address has already been verified"
+	EMAIL_VERIFICATION_TOO_SOON      = "Please wait before requesting another verification email"
+	EMAIL_VERIFICATION_LIMIT_REACHED = "Too many verification emails requested, please try again tomorrow"
+	INVALID_VERIFICATION_TOKEN       = "The verification token is invalid, used or expired"
+
+	PHONE_NOT_VERIFIED     = "Your phone number has not been verified yet"
+	PHONE_ALREADY_VERIFIED = "Your phone number has already been verified"
+	OTP_RESEND_TOO_SOON    = "Please wait before requesting another code"
+	INVALID_OTP            = "The code is invalid, used or expired"
+	OTP_TOO_MANY_ATTEMPTS  = "Too many wrong codes, please request a new one"
+
+	INVALID_RESET_TOKEN  = "The password reset token is invalid, used or expired"
+	PASSWORD_NOT_CHANGED = "The new password must be different from the current one"
+	PASSWORD_BREACHED    = "This password is too common, please choose another one"
+
+	CUSTOMER_ALREADY_ERASED   = "The personal data of this customer has already been erased"
+	ERASURE_OUTSTANDING_LOANS = "Personal data cannot be erased while loans are outstanding"
+	ERASURE_NOT_ALLOWED       = "Personal data of this account must be retained"
+
+	PROFILE_VERSION_NOT_FOUND = "Profile version not found"
+
+	NIK_ALREADY_REGISTERED   = "This NIK is already registered to another account"
+	EMAIL_ALREADY_REGISTERED = "This email address is already registered to another account"
+	PHONE_ALREADY_REGISTERED = "This phone number is already registered to another account"
+	DUPLICATE_REVIEW_PENDING = "New limits are on hold while possible duplicate accounts are reviewed"
+	DUPLICATE_NOT_PENDING    = "This duplicate match has already been reviewed"
+
+	AGE_NOT_ELIGIBLE       = "Customer age is outside the eligible range"
+	DATE_OF_BIRTH_REQUIRED = "Date of birth is required"
+
+	INVALID_ADDRESS_TYPE            = "Invalid address type, use ktp or domicile"
+	EMERGENCY_CONTACT_LIMIT_REACHED = "The maximum number of emergency contacts has been reached"
+	EMERGENCY_CONTACT_IS_CUSTOMER   = "An emergency contact cannot have the phone number of the customer"
+
+	ROLE_CHANGED = "The role of this account has changed, please sign in again"
+	INVALID_ROLE = "Unknown role"
+
+	SALES_CHANNEL_REQUIRED = "Partner accounts need a sales channel"
+
+	CHECKOUT_REQUIRED = "Customers create transactions through checkout and cannot change them"
+
+	REFRESH_TOKEN_REUSED = "This refresh token has already been used, the session has been signed out"
 
 	FOREIGN_KEY_CONSTRAINT_VIOLATION = "Foreign key constraint violation"
 )
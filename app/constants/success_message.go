@@ -11,4 +11,10 @@ const (
 	UPLOAD_SUCCESSFULLY       = "File uploaded successfully."
 	DOWNLOAD_SUCCESSFULLY     = "File downloaded successfully."
 	PROCESS_COMPLETED_SUCCESS = "Process completed successfully."
+	VERIFICATION_EMAIL_SENT   = "Verification email sent."
+	VERIFICATION_CODE_SENT    = "Verification code sent."
+	PASSWORD_RESET_REQUESTED  = "If the account exists, password reset instructions have been sent."
+	PASSWORD_RESET_SUCCESS    = "Password reset successfully."
+	PASSWORD_CHANGED          = "Password changed successfully."
+	DATA_ERASED               = "Personal data erased successfully."
 )
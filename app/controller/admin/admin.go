@@ -0,0 +1,76 @@
+package admin
+
+import (
+	customerDB "kredit-plus/app/db/repository/customer"
+	customerStatusHistoryDB "kredit-plus/app/db/repository/customer_status_history"
+	duplicateMatchDB "kredit-plus/app/db/repository/duplicate_match"
+	kycVerificationDB "kredit-plus/app/db/repository/kyc_verification"
+	limitIncreaseRequestDB "kredit-plus/app/db/repository/limit_increase_request"
+	"kredit-plus/app/service/account"
+	"kredit-plus/app/service/credit"
+	"kredit-plus/app/service/duplicate"
+	"kredit-plus/app/service/kyc"
+	"kredit-plus/app/service/privacy"
+	"kredit-plus/app/service/profile"
+	"kredit-plus/app/service/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IAdminController interface {
+	GetLimitIncreaseRequests(c *gin.Context)
+	GetLimitIncreaseRequest(c *gin.Context)
+	UpdateLimitIncreaseRequest(c *gin.Context)
+
+	GetKycVerifications(c *gin.Context)
+	GetKycVerification(c *gin.Context)
+	UpdateKycVerification(c *gin.Context)
+
+	UpdateCustomerStatus(c *gin.Context)
+	GetCustomerStatusHistory(c *gin.Context)
+	EraseCustomer(c *gin.Context)
+	GetCustomerProfileVersions(c *gin.Context)
+	GetCustomerProfileVersionDiff(c *gin.Context)
+
+	GetDuplicateMatches(c *gin.Context)
+	GetDuplicateMatch(c *gin.Context)
+	UpdateDuplicateMatch(c *gin.Context)
+
+	GetRoles(c *gin.Context)
+	UpdateCustomerRole(c *gin.Context)
+}
+
+type AdminController struct {
+	CustomerDBClient              customerDB.ICustomerRepository
+	CustomerStatusHistoryDBClient customerStatusHistoryDB.ICustomerStatusHistoryRepository
+
+	LimitIncreaseRequestDBClient limitIncreaseRequestDB.ILimitIncreaseRequestRepository
+	KycVerificationDBClient      kycVerificationDB.IKycVerificationRepository
+	DuplicateMatchDBClient       duplicateMatchDB.IDuplicateMatchRepository
+
+	CreditService  credit.ICreditService
+	KycService     kyc.IKycService
+	AccountService account.IAccountService
+	PrivacyService privacy.IPrivacyService
+	ProfileService profile.IProfileService
+
+	DuplicateService duplicate.IDuplicateService
+	RBACService      rbac.IRBACService
+}
+
+func NewAdminController(CustomerClient customerDB.ICustomerRepository, CustomerStatusHistoryClient customerStatusHistoryDB.ICustomerStatusHistoryRepository, LimitIncreaseRequestClient limitIncreaseRequestDB.ILimitIncreaseRequestRepository, KycVerificationClient kycVerificationDB.IKycVerificationRepository, CreditService credit.ICreditService, KycService kyc.IKycService, AccountService account.IAccountService, PrivacyService privacy.IPrivacyService, ProfileService profile.IProfileService, DuplicateMatchClient duplicateMatchDB.IDuplicateMatchRepository, DuplicateService duplicate.IDuplicateService, RBACService rbac.IRBACService) IAdminController {
+	return &AdminController{
+		CustomerDBClient:              CustomerClient,
+		CustomerStatusHistoryDBClient: CustomerStatusHistoryClient,
+		LimitIncreaseRequestDBClient:  LimitIncreaseRequestClient,
+		KycVerificationDBClient:       KycVerificationClient,
+		CreditService:                 CreditService,
+		KycService:                    KycService,
+		AccountService:                AccountService,
+		PrivacyService:                PrivacyService,
+		ProfileService:                ProfileService,
+		DuplicateMatchDBClient:        DuplicateMatchClient,
+		DuplicateService:              DuplicateService,
+		RBACService:                   RBACService,
+	}
+}
@@ -0,0 +1,281 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/controller"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerStatusHistoryDBModels "kredit-plus/app/db/dto/customer_status_history"
+	"kredit-plus/app/service/correlation"
+	"kredit-plus/app/service/dto/request"
+	adminRequest "kredit-plus/app/service/dto/request/admin"
+	limitService "kredit-plus/app/service/limit"
+	"kredit-plus/app/service/logger"
+	"kredit-plus/app/service/privacy"
+	"kredit-plus/app/service/profile"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UpdateCustomerStatus suspends, closes, blacklists or reactivates a customer account.
+func (u AdminController) UpdateCustomerStatus(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	adminUUID, _ := c.Get(constants.CTK_CLAIM_KEY.String())
+
+	id := c.Param("uuid")
+	if _, err := uuid.Parse(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	var dataFromBody adminRequest.CustomerStatusChange
+	if err := c.BindJSON(&dataFromBody); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	if err := dataFromBody.Validate(); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	customer, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: id})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if customer.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	if !customer.CanTransition(dataFromBody.Status) {
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, errors.New(constants.ACCOUNT_INVALID_STATUS_TRANSITION))
+		return
+	}
+
+	customer, err = u.AccountService.ChangeStatus(ctx, customer, dataFromBody.Status, dataFromBody.Reason, limitService.AdminActor(adminUUID))
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	customer.Password = ""
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.UPDATED_SUCCESSFULLY, customer, nil)
+}
+
+func (u AdminController) GetCustomerStatusHistory(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	id := c.Param("uuid")
+	if _, err := uuid.Parse(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	var pagination request.Pagination
+
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	pagination.Validate()
+
+	customer, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: id})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if customer.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	history, paginationResponse, err := u.CustomerStatusHistoryDBClient.List(ctx, pagination, map[string]interface{}{customerStatusHistoryDBModels.COLUMN_CUSTOMER_ID: customer.ID})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, history, &paginationResponse)
+}
+
+// EraseCustomer anonymizes the personal data of a customer, e.g. for an erasure request received by support.
+func (u AdminController) EraseCustomer(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	adminUUID, _ := c.Get(constants.CTK_CLAIM_KEY.String())
+
+	id := c.Param("uuid")
+	if _, err := uuid.Parse(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	var dataFromBody adminRequest.CustomerErasure
+	if err := c.BindJSON(&dataFromBody); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	if err := dataFromBody.Validate(); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	customer, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: id})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if customer.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	customer, err = u.PrivacyService.Erase(ctx, customer, dataFromBody.Reason, limitService.AdminActor(adminUUID))
+	switch {
+	case errors.Is(err, privacy.ErrOutstandingLoans), errors.Is(err, privacy.ErrErasureNotAllowed), errors.Is(err, privacy.ErrAlreadyErased):
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, err)
+		return
+	case err != nil:
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	customer.Password = ""
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.DATA_ERASED, customer, nil)
+}
+
+// GetCustomerProfileVersions lists the recorded versions of the profile of a customer.
+func (u AdminController) GetCustomerProfileVersions(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	id := c.Param("uuid")
+	if _, err := uuid.Parse(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	var pagination request.Pagination
+
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	pagination.Validate()
+
+	customer, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: id})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if customer.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	versions, paginationResponse, err := u.ProfileService.Versions(ctx, customer.ID, pagination)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, versions, &paginationResponse)
+}
+
+// GetCustomerProfileVersionDiff compares two versions of the profile of a customer.
+func (u AdminController) GetCustomerProfileVersionDiff(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	id := c.Param("uuid")
+	if _, err := uuid.Parse(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	var query request.ProfileVersionDiff
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	customer, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: id})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if customer.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	diff, err := u.ProfileService.Diff(ctx, customer.ID, query.From, query.To)
+	switch {
+	case errors.Is(err, profile.ErrVersionNotFound):
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, err)
+		return
+	case err != nil:
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, diff, nil)
+}
@@ -0,0 +1,145 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/controller"
+	duplicateMatchDBModels "kredit-plus/app/db/dto/duplicate_match"
+	"kredit-plus/app/service/correlation"
+	"kredit-plus/app/service/dto/request"
+	adminRequest "kredit-plus/app/service/dto/request/admin"
+	"kredit-plus/app/service/duplicate"
+	limitService "kredit-plus/app/service/limit"
+	"kredit-plus/app/service/logger"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDuplicateMatches lists possible duplicate accounts; filter on status=pending for the review queue.
+func (u AdminController) GetDuplicateMatches(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	var pagination request.Pagination
+
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	pagination.Validate()
+
+	f := map[string]interface{}{}
+
+	if c.Query(duplicateMatchDBModels.COLUMN_CUSTOMER_ID) != "" {
+		f[duplicateMatchDBModels.COLUMN_CUSTOMER_ID] = c.Query(duplicateMatchDBModels.COLUMN_CUSTOMER_ID)
+	}
+
+	if c.Query(duplicateMatchDBModels.COLUMN_MATCHED_CUSTOMER_ID) != "" {
+		f[duplicateMatchDBModels.COLUMN_MATCHED_CUSTOMER_ID] = c.Query(duplicateMatchDBModels.COLUMN_MATCHED_CUSTOMER_ID)
+	}
+
+	if c.Query(duplicateMatchDBModels.COLUMN_REASON) != "" {
+		f[duplicateMatchDBModels.COLUMN_REASON] = c.Query(duplicateMatchDBModels.COLUMN_REASON)
+	}
+
+	if c.Query(duplicateMatchDBModels.COLUMN_STATUS) != "" {
+		f[duplicateMatchDBModels.COLUMN_STATUS] = c.Query(duplicateMatchDBModels.COLUMN_STATUS)
+	}
+
+	duplicateMatches, paginationResponse, err := u.DuplicateMatchDBClient.List(ctx, pagination, f)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, duplicateMatches, &paginationResponse)
+}
+
+func (u AdminController) GetDuplicateMatch(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	id := c.Param("id")
+	if _, err := strconv.Atoi(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	r, err := u.DuplicateMatchDBClient.Get(ctx, map[string]interface{}{duplicateMatchDBModels.COLUMN_ID: id})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if r.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, r, nil)
+}
+
+// UpdateDuplicateMatch confirms or dismisses a match waiting in the review queue.
+func (u AdminController) UpdateDuplicateMatch(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	adminUUID, _ := c.Get(constants.CTK_CLAIM_KEY.String())
+
+	id := c.Param("id")
+	if _, err := strconv.Atoi(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	var dataFromBody adminRequest.DuplicateDecision
+	if err := c.BindJSON(&dataFromBody); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	if err := dataFromBody.Validate(); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	match, err := u.DuplicateMatchDBClient.Get(ctx, map[string]interface{}{duplicateMatchDBModels.COLUMN_ID: id})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if match.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	match, err = u.DuplicateService.Review(ctx, match, dataFromBody.Status, dataFromBody.Notes, limitService.AdminActor(adminUUID))
+	switch {
+	case errors.Is(err, duplicate.ErrNotPending):
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, err)
+		return
+	case err != nil:
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.UPDATED_SUCCESSFULLY, match, nil)
+}
@@ -0,0 +1,142 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/controller"
+	kycVerificationDBModels "kredit-plus/app/db/dto/kyc_verification"
+	"kredit-plus/app/service/correlation"
+	"kredit-plus/app/service/dto/request"
+	adminRequest "kredit-plus/app/service/dto/request/admin"
+	"kredit-plus/app/service/kyc"
+	limitService "kredit-plus/app/service/limit"
+	"kredit-plus/app/service/logger"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetKycVerifications lists KYC verifications; filter on status=pending for the manual review queue.
+func (u AdminController) GetKycVerifications(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	var pagination request.Pagination
+
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	pagination.Validate()
+
+	f := map[string]interface{}{}
+
+	if c.Query(kycVerificationDBModels.COLUMN_CUSTOMER_ID) != "" {
+		f[kycVerificationDBModels.COLUMN_CUSTOMER_ID] = c.Query(kycVerificationDBModels.COLUMN_CUSTOMER_ID)
+	}
+
+	if c.Query(kycVerificationDBModels.COLUMN_STATUS) != "" {
+		f[kycVerificationDBModels.COLUMN_STATUS] = c.Query(kycVerificationDBModels.COLUMN_STATUS)
+	}
+
+	kycVerifications, paginationResponse, err := u.KycVerificationDBClient.List(ctx, pagination, f)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, kycVerifications, &paginationResponse)
+}
+
+func (u AdminController) GetKycVerification(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	id := c.Param("id")
+	if _, err := strconv.Atoi(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	r, err := u.KycVerificationDBClient.Get(ctx, map[string]interface{}{kycVerificationDBModels.COLUMN_ID: id})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if r.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, r, nil)
+}
+
+// UpdateKycVerification verifies or rejects a verification waiting in the manual review queue.
+func (u AdminController) UpdateKycVerification(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	adminUUID, _ := c.Get(constants.CTK_CLAIM_KEY.String())
+
+	id := c.Param("id")
+	if _, err := strconv.Atoi(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	var dataFromBody adminRequest.KycDecision
+	if err := c.BindJSON(&dataFromBody); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	if err := dataFromBody.Validate(); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	verification, err := u.KycVerificationDBClient.Get(ctx, map[string]interface{}{kycVerificationDBModels.COLUMN_ID: id})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if verification.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	if verification.Status != kycVerificationDBModels.STATUS_PENDING {
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, errors.New(constants.KYC_NOT_PENDING))
+		return
+	}
+
+	verification, err = u.KycService.Review(ctx, verification, dataFromBody.Status, dataFromBody.Notes, limitService.AdminActor(adminUUID))
+	switch {
+	case errors.Is(err, kyc.ErrNotPending):
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, err)
+		return
+	case err != nil:
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.UPDATED_SUCCESSFULLY, verification, nil)
+}
@@ -0,0 +1,241 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/controller"
+	limitIncreaseRequestDBModels "kredit-plus/app/db/dto/limit_increase_request"
+	"kredit-plus/app/service/correlation"
+	"kredit-plus/app/service/credit"
+	"kredit-plus/app/service/dto/request"
+	adminRequest "kredit-plus/app/service/dto/request/admin"
+	limitService "kredit-plus/app/service/limit"
+	"kredit-plus/app/service/logger"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (u AdminController) GetLimitIncreaseRequests(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	var pagination request.Pagination
+
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	pagination.Validate()
+
+	f := map[string]interface{}{}
+
+	if c.Query(limitIncreaseRequestDBModels.COLUMN_CUSTOMER_ID) != "" {
+		f[limitIncreaseRequestDBModels.COLUMN_CUSTOMER_ID] = c.Query(limitIncreaseRequestDBModels.COLUMN_CUSTOMER_ID)
+	}
+
+	if c.Query(limitIncreaseRequestDBModels.COLUMN_TENOR) != "" {
+		f[limitIncreaseRequestDBModels.COLUMN_TENOR] = c.Query(limitIncreaseRequestDBModels.COLUMN_TENOR)
+	}
+
+	if c.Query(limitIncreaseRequestDBModels.COLUMN_STATUS) != "" {
+		f[limitIncreaseRequestDBModels.COLUMN_STATUS] = c.Query(limitIncreaseRequestDBModels.COLUMN_STATUS)
+	}
+
+	limitIncreaseRequests, paginationResponse, err := u.LimitIncreaseRequestDBClient.List(ctx, pagination, f)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, limitIncreaseRequests, &paginationResponse)
+}
+
+func (u AdminController) GetLimitIncreaseRequest(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	id := c.Param("id")
+	if _, err := strconv.Atoi(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	r, err := u.LimitIncreaseRequestDBClient.Get(ctx, map[string]interface{}{limitIncreaseRequestDBModels.COLUMN_ID: id})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if r.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, r, nil)
+}
+
+// UpdateLimitIncreaseRequest moves a request to under_review, approved or rejected.
+// Approving a request sets the limit of its tenor to the approved amount, capped at the
+// maximum limit, which has to be higher than the limit granted so far.
+func (u AdminController) UpdateLimitIncreaseRequest(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	adminUUID, _ := c.Get(constants.CTK_CLAIM_KEY.String())
+
+	id := c.Param("id")
+	requestID, err := strconv.Atoi(id)
+	if err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	var dataFromBody adminRequest.LimitIncreaseDecision
+	if err := c.BindJSON(&dataFromBody); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	if err := dataFromBody.Validate(); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	filter := map[string]interface{}{
+		limitIncreaseRequestDBModels.COLUMN_ID: requestID,
+	}
+
+	limitIncreaseRequest, err := u.LimitIncreaseRequestDBClient.Get(ctx, filter)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if limitIncreaseRequest.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	if !limitIncreaseRequest.CanTransition(dataFromBody.Status) {
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, errors.New(constants.LIMIT_INCREASE_INVALID_TRANSITION))
+		return
+	}
+
+	now := time.Now()
+
+	patcher := map[string]interface{}{
+		limitIncreaseRequestDBModels.COLUMN_STATUS:     dataFromBody.Status,
+		limitIncreaseRequestDBModels.COLUMN_REVIEWER:   limitService.AdminActor(adminUUID),
+		limitIncreaseRequestDBModels.COLUMN_UPDATED_AT: now,
+	}
+
+	if dataFromBody.Notes != "" {
+		patcher[limitIncreaseRequestDBModels.COLUMN_REVIEW_NOTES] = dataFromBody.Notes
+	}
+
+	if dataFromBody.Status != limitIncreaseRequestDBModels.STATUS_UNDER_REVIEW {
+		patcher[limitIncreaseRequestDBModels.COLUMN_REVIEWED_AT] = now
+	}
+
+	approved := dataFromBody.Status == limitIncreaseRequestDBModels.STATUS_APPROVED
+
+	var approvedAmount float32
+
+	if approved {
+		approvedAmount = limitIncreaseRequest.RequestedAmount
+		if dataFromBody.ApprovedAmount != nil {
+			approvedAmount = *dataFromBody.ApprovedAmount
+		}
+
+		// No approval grants more than the scoring would grant anyone
+		approvedAmount = u.CreditService.CapLimit(approvedAmount)
+
+		// Applying a lower amount would lower the limit of the customer
+		grantedAmount, err := u.CreditService.GrantedLimit(ctx, limitIncreaseRequest.CustomerID, limitIncreaseRequest.Tenor)
+		if err != nil {
+			errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+			log.Error(errorMsg)
+			controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+			return
+		}
+
+		if approvedAmount <= grantedAmount {
+			controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.LIMIT_INCREASE_APPROVED_NOT_HIGHER))
+			return
+		}
+
+		patcher[limitIncreaseRequestDBModels.COLUMN_APPROVED_AMOUNT] = approvedAmount
+	}
+
+	// The request is claimed before the limit is applied, a concurrent review of the
+	// same request finds it moved on and cannot apply the limit a second time
+	claimed, err := u.LimitIncreaseRequestDBClient.Transition(ctx, requestID, limitIncreaseRequestDBModels.StatusesBefore(dataFromBody.Status), patcher)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if !claimed {
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, errors.New(constants.LIMIT_INCREASE_INVALID_TRANSITION))
+		return
+	}
+
+	if approved {
+		limits := map[int]float32{limitIncreaseRequest.Tenor: approvedAmount}
+		reason := fmt.Sprintf("%s: request %d", limitService.REASON_LIMIT_INCREASE, limitIncreaseRequest.ID)
+
+		if err := u.CreditService.ApplyLimits(ctx, limitIncreaseRequest.CustomerID, limits, limitService.AdminActor(adminUUID), reason); err != nil {
+			// Release the claim, so the approval can be retried once the limit can be applied
+			release := map[string]interface{}{
+				limitIncreaseRequestDBModels.COLUMN_STATUS:          limitIncreaseRequest.Status,
+				limitIncreaseRequestDBModels.COLUMN_REVIEWER:        limitIncreaseRequest.Reviewer,
+				limitIncreaseRequestDBModels.COLUMN_REVIEW_NOTES:    limitIncreaseRequest.ReviewNotes,
+				limitIncreaseRequestDBModels.COLUMN_REVIEWED_AT:     limitIncreaseRequest.ReviewedAt,
+				limitIncreaseRequestDBModels.COLUMN_APPROVED_AMOUNT: limitIncreaseRequest.ApprovedAmount,
+				limitIncreaseRequestDBModels.COLUMN_UPDATED_AT:      limitIncreaseRequest.UpdatedAt,
+			}
+
+			if _, releaseErr := u.LimitIncreaseRequestDBClient.Transition(ctx, requestID, []string{limitIncreaseRequestDBModels.STATUS_APPROVED}, release); releaseErr != nil {
+				log.Error(constants.INTERNAL_SERVER_ERROR, releaseErr)
+			}
+
+			if errors.Is(err, credit.ErrNotVerified) || errors.Is(err, credit.ErrDuplicateReview) {
+				controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, err)
+				return
+			}
+
+			errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+			log.Error(errorMsg)
+			controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+			return
+		}
+	}
+
+	limitIncreaseRequest, err = u.LimitIncreaseRequestDBClient.Get(ctx, filter)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.UPDATED_SUCCESSFULLY, limitIncreaseRequest, nil)
+}
@@ -0,0 +1,103 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/controller"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	"kredit-plus/app/service/correlation"
+	adminRequest "kredit-plus/app/service/dto/request/admin"
+	adminResponse "kredit-plus/app/service/dto/response/admin"
+	"kredit-plus/app/service/logger"
+	"kredit-plus/app/service/rbac"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetRoles lists the roles together with the permissions granted to each.
+func (u AdminController) GetRoles(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	roles, err := u.RBACService.Roles(ctx)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	response := make([]adminResponse.Role, 0, len(roles))
+	for _, role := range roles {
+		permissions, err := u.RBACService.Permissions(ctx, role.Name)
+		if err != nil {
+			errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+			log.Error(errorMsg)
+			controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+			return
+		}
+
+		response = append(response, adminResponse.Role{Role: role, Permissions: permissions})
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, response, nil)
+}
+
+// UpdateCustomerRole gives a customer account another role, e.g. to make it an admin
+// or a partner of a sales channel.
+func (u AdminController) UpdateCustomerRole(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	id := c.Param("uuid")
+	if _, err := uuid.Parse(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	var dataFromBody adminRequest.CustomerRoleChange
+	if err := c.BindJSON(&dataFromBody); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	if err := dataFromBody.Validate(); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	customer, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: id})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if customer.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	customer, err = u.RBACService.AssignRole(ctx, customer, dataFromBody.Role, dataFromBody.SalesChannel)
+	switch {
+	case errors.Is(err, rbac.ErrInvalidRole), errors.Is(err, rbac.ErrSalesChannelRequired):
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	case err != nil:
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	customer.Password = ""
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.UPDATED_SUCCESSFULLY, customer, nil)
+}
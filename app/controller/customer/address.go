@@ -0,0 +1,311 @@
+package customer
+
+import (
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/controller"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerAddressDBModels "kredit-plus/app/db/dto/customer_address"
+	"kredit-plus/app/service/correlation"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/logger"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (u CustomerController) CreateCustomerAddress(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	var dataFromBody customerAddressDBModels.CustomerAddress
+	if err := c.BindJSON(&dataFromBody); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	now := time.Now()
+
+	customerAddress := customerAddressDBModels.CustomerAddress{
+		CustomerID: user.ID,
+		Type:       dataFromBody.Type,
+		Street:     dataFromBody.Street,
+		Province:   dataFromBody.Province,
+		City:       dataFromBody.City,
+		District:   dataFromBody.District,
+		PostalCode: dataFromBody.PostalCode,
+		CreatedAt:  now,
+		UpdatedAt:  &now,
+	}
+
+	if err := customerAddress.Validate(); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	// A customer has one address of each type, which is changed with a PATCH
+	existing, err := u.CustomerAddressDBClient.Get(ctx, map[string]interface{}{
+		customerAddressDBModels.COLUMN_CUSTOMER_ID: user.ID,
+		customerAddressDBModels.COLUMN_TYPE:        customerAddress.Type,
+	})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if existing.ID != 0 {
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, errors.New(constants.RESOURCE_CONFLICT))
+		return
+	}
+
+	if err := u.CustomerAddressDBClient.Create(ctx, &customerAddress); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.CREATED_SUCCESSFULLY, customerAddress, nil)
+}
+
+func (u CustomerController) GetCustomerAddresses(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	p := request.Pagination{
+		GetAllData: true,
+	}
+
+	p.Validate()
+
+	customerAddresses, _, err := u.CustomerAddressDBClient.List(ctx, p, map[string]interface{}{customerAddressDBModels.COLUMN_CUSTOMER_ID: user.ID})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, customerAddresses, nil)
+}
+
+func (u CustomerController) GetCustomerAddress(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	addressType := c.Param("type")
+	if !customerAddressDBModels.IsValidType(addressType) {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_ADDRESS_TYPE))
+		return
+	}
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	r, err := u.CustomerAddressDBClient.Get(ctx, map[string]interface{}{
+		customerAddressDBModels.COLUMN_CUSTOMER_ID: user.ID,
+		customerAddressDBModels.COLUMN_TYPE:        addressType,
+	})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if r.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, r, nil)
+}
+
+func (u CustomerController) UpdateCustomerAddress(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	addressType := c.Param("type")
+	if !customerAddressDBModels.IsValidType(addressType) {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_ADDRESS_TYPE))
+		return
+	}
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	var dataFromBody customerAddressDBModels.CustomerAddress
+	if err := c.ShouldBindJSON(&dataFromBody); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	filter := map[string]interface{}{
+		customerAddressDBModels.COLUMN_CUSTOMER_ID: user.ID,
+		customerAddressDBModels.COLUMN_TYPE:        addressType,
+	}
+
+	current, err := u.CustomerAddressDBClient.Get(ctx, filter)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if current.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	patcher := make(map[string]interface{})
+
+	if dataFromBody.Street != "" {
+		patcher[customerAddressDBModels.COLUMN_STREET] = dataFromBody.Street
+		current.Street = dataFromBody.Street
+	}
+
+	if dataFromBody.Province != "" {
+		patcher[customerAddressDBModels.COLUMN_PROVINCE] = dataFromBody.Province
+		current.Province = dataFromBody.Province
+	}
+
+	if dataFromBody.City != "" {
+		patcher[customerAddressDBModels.COLUMN_CITY] = dataFromBody.City
+		current.City = dataFromBody.City
+	}
+
+	if dataFromBody.District != "" {
+		patcher[customerAddressDBModels.COLUMN_DISTRICT] = dataFromBody.District
+		current.District = dataFromBody.District
+	}
+
+	if dataFromBody.PostalCode != "" {
+		patcher[customerAddressDBModels.COLUMN_POSTAL_CODE] = dataFromBody.PostalCode
+		current.PostalCode = dataFromBody.PostalCode
+	}
+
+	if err := current.Validate(); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	patcher[customerAddressDBModels.COLUMN_UPDATED_AT] = time.Now()
+
+	if err := u.CustomerAddressDBClient.Update(ctx, filter, patcher); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	customerAddress, err := u.CustomerAddressDBClient.Get(ctx, filter)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusAccepted, constants.UPDATED_SUCCESSFULLY, customerAddress, nil)
+}
+
+func (u CustomerController) DeleteCustomerAddress(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	addressType := c.Param("type")
+	if !customerAddressDBModels.IsValidType(addressType) {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_ADDRESS_TYPE))
+		return
+	}
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	filter := map[string]interface{}{
+		customerAddressDBModels.COLUMN_CUSTOMER_ID: user.ID,
+		customerAddressDBModels.COLUMN_TYPE:        addressType,
+	}
+
+	if err := u.CustomerAddressDBClient.Delete(ctx, filter); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.DELETED_SUCCESSFULLY, nil, nil)
+}
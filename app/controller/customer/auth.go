@@ -5,12 +5,14 @@ import (
 	"kredit-plus/app/constants"
 	"kredit-plus/app/controller"
 	customerDBModels "kredit-plus/app/db/dto/customer"
-	customerLimitDBModels "kredit-plus/app/db/dto/customer_limit"
 	customerTokenDBModels "kredit-plus/app/db/dto/customer_token"
+	securityEventDBModels "kredit-plus/app/db/dto/security_event"
 	"kredit-plus/app/service/correlation"
 	customerRequest "kredit-plus/app/service/dto/request/customer"
+	"kredit-plus/app/service/duplicate"
 	"kredit-plus/app/service/logger"
 	"kredit-plus/app/service/util"
+	"kredit-plus/app/service/verification"
 	"net/http"
 	"time"
 
@@ -28,57 +30,75 @@ func (u CustomerController) Signup(c *gin.Context) {
 		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
 		return
 	}
+
+	// Phone numbers are stored in E.164 form, invalid ones are rejected by Validate
+	if phone, err := util.NormalizePhone(dataFromBody.Phone); err == nil {
+		dataFromBody.Phone = phone
+	}
+
 	if err := dataFromBody.Validate(); err != nil {
 		log.Error(constants.BAD_REQUEST, err)
 		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
 		return
 	}
 
+	if err := u.PasswordPolicy.Validate(dataFromBody.Password); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
 	hashedPassword, err := util.GenerateHash(dataFromBody.Password)
 	if err != nil {
 		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
 		return
 	}
-	dataFromBody.Password = hashedPassword
+
+	// Tell the customer which contact detail is taken instead of failing on the unique constraint
+	err = u.DuplicateService.CheckContact(ctx, 0, dataFromBody.Email, dataFromBody.Phone)
+	switch {
+	case errors.Is(err, duplicate.ErrEmailRegistered), errors.Is(err, duplicate.ErrPhoneRegistered):
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, err)
+		return
+	case err != nil:
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+	// Only the fields a customer may choose are copied from the request; everything
+	// else, e.g. the account and verification status, starts from its default.
+	// Limits are only assigned once the identity of the customer has been verified.
+	customer := customerDBModels.Customer{
+		Email:     dataFromBody.Email,
+		Phone:     dataFromBody.Phone,
+		Password:  hashedPassword,
+		KycStatus: customerDBModels.KYC_STATUS_UNVERIFIED,
+		Status:    customerDBModels.STATUS_ACTIVE,
+		Role:      constants.USER.String(),
+	}
 
 	if uuid, err := uuid.NewRandom(); err != nil {
 		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
 		return
 	} else {
-		dataFromBody.UUID = uuid
+		customer.UUID = uuid
 	}
 
-	if err := u.CustomerDBClient.Create(ctx, &dataFromBody); err != nil {
+	if err := u.CustomerDBClient.Create(ctx, &customer); err != nil {
 		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
 		return
 	}
 
-	dataFromBody.Password = ""
-
-	limits := map[int]float32{
-		1: 100000.00,
-		2: 200000.00,
-		3: 500000.00,
-		6: 700000.00,
+	// The customer can ask for a new email, so a failed delivery does not fail the signup
+	if err := u.EmailVerificationService.Send(ctx, customer); err != nil {
+		log.Errorf("failed to send verification email: %v", err)
 	}
 
-	for tenor, limitAmount := range limits {
-		customerLimit := &customerLimitDBModels.CustomerLimit{
-			CustomerID:  dataFromBody.ID,
-			Tenor:       tenor,
-			LimitAmount: limitAmount,
-		}
-		if err := u.CustomerLimitDBClient.Create(ctx, customerLimit); err != nil {
-			log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
-			controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
-			return
-		}
-	}
+	customer.Password = ""
 
-	controller.RespondWithSuccess(c, http.StatusCreated, constants.CREATED_SUCCESSFULLY, dataFromBody, nil)
+	controller.RespondWithSuccess(c, http.StatusCreated, constants.CREATED_SUCCESSFULLY, customer, nil)
 }
 
 func (u CustomerController) Signin(c *gin.Context) {
@@ -104,6 +124,9 @@ func (u CustomerController) Signin(c *gin.Context) {
 	}
 	if dataFromBody.Phone != "" {
 		filter[customerDBModels.COLUMN_PHONE] = dataFromBody.Phone
+		if phone, err := util.NormalizePhone(dataFromBody.Phone); err == nil {
+			filter[customerDBModels.COLUMN_PHONE] = phone
+		}
 	}
 
 	user, err := u.CustomerDBClient.Get(ctx, filter)
@@ -118,6 +141,11 @@ func (u CustomerController) Signin(c *gin.Context) {
 		return
 	}
 
+	if !user.IsActive() {
+		controller.RespondWithError(c, http.StatusForbidden, constants.FORBIDDEN, errors.New(constants.ACCOUNT_NOT_ACTIVE))
+		return
+	}
+
 	token, err := u.JWT.GenerateToken(c, user)
 	if err != nil {
 		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
@@ -134,20 +162,26 @@ func (u CustomerController) Signin(c *gin.Context) {
 		return
 	}
 
-	if err := u.CustomerTokenDBClient.Delete(ctx, map[string]interface{}{customerTokenDBModels.COLUMN_CUSTOMER_ID: user.ID}); err != nil {
-		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
-		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
-		return
+	// Every sign in starts a session of its own, the other devices stay signed in
+	name := dataFromBody.Name
+	if name == "" {
+		name = dataFromBody.Device
 	}
 
+	now := time.Now()
 	tokenRecord := customerTokenDBModels.CustomerToken{
 		CustomerID:            user.ID,
-		AccessToken:           token.AccessToken,
-		RefreshToken:          token.RefreshToken,
+		AccessTokenHash:       verification.HashToken(token.AccessToken),
+		RefreshTokenHash:      verification.HashToken(token.RefreshToken),
+		FamilyID:              token.FamilyID,
+		RefreshTokenJTI:       &token.RefreshTokenID,
+		Name:                  name,
+		Device:                dataFromBody.Device,
 		UserAgent:             c.Request.UserAgent(),
 		IPAddress:             c.ClientIP(),
 		AccessTokenExpiredAt:  time.Unix(token.AtExpires, 0),
 		RefreshTokenExpiredAt: time.Unix(token.RtExpires, 0),
+		LastSeenAt:            &now,
 	}
 	if err := u.CustomerTokenDBClient.Create(ctx, &tokenRecord); err != nil {
 		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
@@ -155,6 +189,12 @@ func (u CustomerController) Signin(c *gin.Context) {
 		return
 	}
 
+	// Past the cap the oldest sessions are signed out. The new session already exists,
+	// so a failure only postpones that to the next sign in.
+	if err := u.CustomerTokenDBClient.Prune(ctx, user.ID, constants.Config.SessionConfig.SESSION_MAX_PER_CUSTOMER); err != nil {
+		log.Errorf("failed to prune the sessions of customer %d: %v", user.ID, err)
+	}
+
 	controller.RespondWithSuccess(c, http.StatusOK, constants.LOGIN_SUCCESSFULLY, token, nil)
 }
 
@@ -176,7 +216,13 @@ func (u CustomerController) Signout(c *gin.Context) {
 		return
 	}
 
-	if err := u.CustomerTokenDBClient.Delete(ctx, map[string]interface{}{customerTokenDBModels.COLUMN_CUSTOMER_ID: user.ID}); err != nil {
+	// Only the session of this request ends, see DeleteCustomerToken for the others
+	filter := map[string]interface{}{
+		customerTokenDBModels.COLUMN_ID:          c.GetInt(constants.CTK_TOKEN_ID_KEY.String()),
+		customerTokenDBModels.COLUMN_CUSTOMER_ID: user.ID,
+	}
+
+	if err := u.CustomerTokenDBClient.Delete(ctx, filter); err != nil {
 		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
 		return
@@ -202,7 +248,24 @@ func (u CustomerController) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	token, err := u.CustomerTokenDBClient.Get(ctx, map[string]interface{}{customerTokenDBModels.COLUMN_REFRESH_TOKEN: refreshTokenRequest.RefreshToken})
+	claims, err := u.JWT.ParseRefreshToken(ctx, refreshTokenRequest.RefreshToken)
+	if err != nil {
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	// The session is the refresh token family. Refresh tokens issued before families
+	// were added carry none and are looked up by their hash.
+	filter := map[string]interface{}{
+		customerTokenDBModels.COLUMN_REFRESH_TOKEN_HASH: verification.HashToken(refreshTokenRequest.RefreshToken),
+	}
+	if claims.Family != "" {
+		filter = map[string]interface{}{
+			customerTokenDBModels.COLUMN_FAMILY_ID: claims.Family,
+		}
+	}
+
+	token, err := u.CustomerTokenDBClient.Get(ctx, filter)
 	if err != nil {
 		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
@@ -214,6 +277,13 @@ func (u CustomerController) RefreshToken(c *gin.Context) {
 		return
 	}
 
+	// Every refresh token is used once. An older token of the family showing up again
+	// means someone else has a copy, so the whole session is signed out.
+	if claims.Family != "" && (token.RefreshTokenJTI == nil || token.RefreshTokenJTI.String() != claims.Id) {
+		u.revokeRefreshTokenFamily(c, token, "a rotated refresh token was used again")
+		return
+	}
+
 	if token.RefreshTokenExpiredAt.Before(time.Now()) {
 		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
 		return
@@ -221,34 +291,82 @@ func (u CustomerController) RefreshToken(c *gin.Context) {
 
 	refreshToken := refreshTokenRequest.RefreshToken
 
-	tokenDetails, err := u.JWT.RefreshToken(ctx, refreshToken)
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_ID: token.CustomerID})
 	if err != nil {
 		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
 		return
 	}
 
-	if err := u.CustomerTokenDBClient.Delete(ctx, map[string]interface{}{customerTokenDBModels.COLUMN_CUSTOMER_ID: token.CustomerID}); err != nil {
+	if user.ID == 0 {
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	tokenDetails, err := u.JWT.RefreshToken(ctx, refreshToken, user, token.FamilyID)
+	if err != nil {
 		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
 		return
 	}
 
-	tokenRecord := customerTokenDBModels.CustomerToken{
-		CustomerID:            token.CustomerID,
-		AccessToken:           tokenDetails.AccessToken,
-		RefreshToken:          tokenDetails.RefreshToken,
-		UserAgent:             c.Request.UserAgent(),
-		IPAddress:             c.ClientIP(),
-		AccessTokenExpiredAt:  time.Unix(tokenDetails.AtExpires, 0),
-		RefreshTokenExpiredAt: time.Unix(tokenDetails.RtExpires, 0),
+	// The session keeps its id and name, only its tokens are replaced
+	now := time.Now()
+	patcher := map[string]interface{}{
+		customerTokenDBModels.COLUMN_ACCESS_TOKEN_HASH:        verification.HashToken(tokenDetails.AccessToken),
+		customerTokenDBModels.COLUMN_REFRESH_TOKEN_HASH:       verification.HashToken(tokenDetails.RefreshToken),
+		customerTokenDBModels.COLUMN_REFRESH_TOKEN_JTI:        tokenDetails.RefreshTokenID,
+		customerTokenDBModels.COLUMN_USER_AGENT:               c.Request.UserAgent(),
+		customerTokenDBModels.COLUMN_IP_ADDRESS:               c.ClientIP(),
+		customerTokenDBModels.COLUMN_ACCESS_TOKEN_EXPIRED_AT:  time.Unix(tokenDetails.AtExpires, 0),
+		customerTokenDBModels.COLUMN_REFRESH_TOKEN_EXPIRED_AT: time.Unix(tokenDetails.RtExpires, 0),
+		customerTokenDBModels.COLUMN_LAST_SEEN_AT:             now,
+		customerTokenDBModels.COLUMN_UPDATED_AT:               now,
 	}
 
-	if err := u.CustomerTokenDBClient.Create(ctx, &tokenRecord); err != nil {
+	// Only one of two requests using the same refresh token at once can rotate it
+	rotated, err := u.CustomerTokenDBClient.Rotate(ctx, token.ID, token.RefreshTokenJTI, patcher)
+	if err != nil {
 		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
 		return
 	}
 
+	if !rotated {
+		u.revokeRefreshTokenFamily(c, token, "a refresh token was used by two requests at once")
+		return
+	}
+
 	controller.RespondWithSuccess(c, http.StatusOK, constants.UPDATED_SUCCESSFULLY, tokenDetails, nil)
 }
+
+// revokeRefreshTokenFamily signs out the session of a reused refresh token and records a
+// security event, then responds with 401.
+func (u CustomerController) revokeRefreshTokenFamily(c *gin.Context, token customerTokenDBModels.CustomerToken, details string) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	log.Warnf("refresh token reuse in session %d of customer %d, revoking it", token.ID, token.CustomerID)
+
+	if err := u.CustomerTokenDBClient.Delete(ctx, map[string]interface{}{customerTokenDBModels.COLUMN_FAMILY_ID: token.FamilyID}); err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	event := securityEventDBModels.SecurityEvent{
+		CustomerID: token.CustomerID,
+		Type:       securityEventDBModels.TYPE_REFRESH_TOKEN_REUSE,
+		FamilyID:   &token.FamilyID,
+		UserAgent:  c.Request.UserAgent(),
+		IPAddress:  c.ClientIP(),
+		Details:    details,
+	}
+
+	// The session is gone either way, a missing event must not keep the caller signed in
+	if err := u.SecurityEventDBClient.Create(ctx, &event); err != nil {
+		log.Errorf("failed to record the security event: %v", err)
+	}
+
+	controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.REFRESH_TOKEN_REUSED))
+}
@@ -10,15 +10,32 @@ import (
 	customerDBModels "kredit-plus/app/db/dto/customer"
 	customerDB "kredit-plus/app/db/repository/customer"
 
+	customerAddressDB "kredit-plus/app/db/repository/customer_address"
+	customerEmergencyContactDB "kredit-plus/app/db/repository/customer_emergency_contact"
+	customerEmploymentDB "kredit-plus/app/db/repository/customer_employment"
 	customerLimitDB "kredit-plus/app/db/repository/customer_limit"
+	customerLimitHistoryDB "kredit-plus/app/db/repository/customer_limit_history"
 	customerProfileDB "kredit-plus/app/db/repository/customer_profile"
 	customerTokenDB "kredit-plus/app/db/repository/customer_token"
+	kycVerificationDB "kredit-plus/app/db/repository/kyc_verification"
+	limitIncreaseRequestDB "kredit-plus/app/db/repository/limit_increase_request"
+	securityEventDB "kredit-plus/app/db/repository/security_event"
 
 	"kredit-plus/app/api/middleware/jwt"
 	"kredit-plus/app/service/correlation"
+	"kredit-plus/app/service/credit"
 	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/duplicate"
+	"kredit-plus/app/service/eligibility"
+	"kredit-plus/app/service/kyc"
+	limitService "kredit-plus/app/service/limit"
 	"kredit-plus/app/service/logger"
+	"kredit-plus/app/service/password"
+	"kredit-plus/app/service/privacy"
+	"kredit-plus/app/service/profile"
+	"kredit-plus/app/service/storage"
 	"kredit-plus/app/service/util"
+	"kredit-plus/app/service/verification"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -36,16 +53,54 @@ type ICustomerController interface {
 	GetCustomerProfile(c *gin.Context)
 	UpdateCustomerProfile(c *gin.Context)
 	DeleteCustomerProfile(c *gin.Context)
+	GetProfileVersions(c *gin.Context)
+	GetProfileVersionDiff(c *gin.Context)
+	UploadKycImage(c *gin.Context)
+	GetKycImageURL(c *gin.Context)
+	DownloadKycImage(c *gin.Context)
+	SubmitKycVerification(c *gin.Context)
+	GetKycVerification(c *gin.Context)
+
+	CreateCustomerAddress(c *gin.Context)
+	GetCustomerAddresses(c *gin.Context)
+	GetCustomerAddress(c *gin.Context)
+	UpdateCustomerAddress(c *gin.Context)
+	DeleteCustomerAddress(c *gin.Context)
+	CreateCustomerEmployment(c *gin.Context)
+	GetCustomerEmployment(c *gin.Context)
+	UpdateCustomerEmployment(c *gin.Context)
+	DeleteCustomerEmployment(c *gin.Context)
+	UploadIncomeProof(c *gin.Context)
+	GetIncomeProofURL(c *gin.Context)
+	CreateCustomerEmergencyContact(c *gin.Context)
+	GetCustomerEmergencyContacts(c *gin.Context)
+	GetCustomerEmergencyContact(c *gin.Context)
+	UpdateCustomerEmergencyContact(c *gin.Context)
+	DeleteCustomerEmergencyContact(c *gin.Context)
+
+	VerifyEmail(c *gin.Context)
+	ResendVerificationEmail(c *gin.Context)
+	SendPhoneVerification(c *gin.Context)
+	VerifyPhone(c *gin.Context)
+	ForgotPassword(c *gin.Context)
+	ResetPassword(c *gin.Context)
+	ChangePassword(c *gin.Context)
+
+	ExportData(c *gin.Context)
+	EraseData(c *gin.Context)
 
 	GetCustomerTokens(c *gin.Context)
 	GetCustomerToken(c *gin.Context)
 	DeleteCustomerToken(c *gin.Context)
 
-	CreateCustomerLimit(c *gin.Context)
 	GetCustomerLimits(c *gin.Context)
 	GetCustomerLimit(c *gin.Context)
-	UpdateCustomerLimit(c *gin.Context)
-	DeleteCustomerLimit(c *gin.Context)
+	GetCustomerLimitHistory(c *gin.Context)
+	GetCustomerLimitSummary(c *gin.Context)
+
+	CreateLimitIncreaseRequest(c *gin.Context)
+	GetLimitIncreaseRequests(c *gin.Context)
+	GetLimitIncreaseRequest(c *gin.Context)
 
 	Signup(c *gin.Context)
 	Signin(c *gin.Context)
@@ -60,16 +115,64 @@ type CustomerController struct {
 	CustomerTokenDBClient   customerTokenDB.ICustomerTokenRepository
 	CustomerLimitDBClient   customerLimitDB.ICustomerLimitRepository
 
-	JWT jwt.IJWTService
+	CustomerLimitHistoryDBClient customerLimitHistoryDB.ICustomerLimitHistoryRepository
+	LimitIncreaseRequestDBClient limitIncreaseRequestDB.ILimitIncreaseRequestRepository
+	KycVerificationDBClient      kycVerificationDB.IKycVerificationRepository
+
+	CustomerAddressDBClient          customerAddressDB.ICustomerAddressRepository
+	CustomerEmploymentDBClient       customerEmploymentDB.ICustomerEmploymentRepository
+	CustomerEmergencyContactDBClient customerEmergencyContactDB.ICustomerEmergencyContactRepository
+	SecurityEventDBClient            securityEventDB.ISecurityEventRepository
+
+	JWT           jwt.IJWTService
+	CreditService credit.ICreditService
+	LimitService  limitService.ILimitService
+
+	BlobStore  storage.BlobStore
+	URLSigner  *storage.URLSigner
+	ImageRules kyc.ImageRules
+	KycService kyc.IKycService
+
+	EmailVerificationService verification.IEmailVerificationService
+	PhoneVerificationService verification.IPhoneVerificationService
+	PasswordResetService     verification.IPasswordResetService
+	PasswordPolicy           password.Policy
+	PrivacyService           privacy.IPrivacyService
+	ProfileService           profile.IProfileService
+	DuplicateService         duplicate.IDuplicateService
+	EligibilityPolicy        eligibility.Policy
 }
 
-func NewCustomerController(CustomerClient customerDB.ICustomerRepository, CustomerProfileClient customerProfileDB.ICustomerProfileRepository, CustomerTokenClient customerTokenDB.ICustomerTokenRepository, CustomerLimitClient customerLimitDB.ICustomerLimitRepository, JWT jwt.IJWTService) ICustomerController {
+func NewCustomerController(CustomerClient customerDB.ICustomerRepository, CustomerProfileClient customerProfileDB.ICustomerProfileRepository, CustomerTokenClient customerTokenDB.ICustomerTokenRepository, CustomerLimitClient customerLimitDB.ICustomerLimitRepository, CustomerLimitHistoryClient customerLimitHistoryDB.ICustomerLimitHistoryRepository, LimitIncreaseRequestClient limitIncreaseRequestDB.ILimitIncreaseRequestRepository, KycVerificationClient kycVerificationDB.IKycVerificationRepository, JWT jwt.IJWTService, CreditService credit.ICreditService, LimitService limitService.ILimitService, BlobStore storage.BlobStore, URLSigner *storage.URLSigner, ImageRules kyc.ImageRules, KycService kyc.IKycService, EmailVerificationService verification.IEmailVerificationService, PhoneVerificationService verification.IPhoneVerificationService, PasswordResetService verification.IPasswordResetService, PasswordPolicy password.Policy, PrivacyService privacy.IPrivacyService, ProfileService profile.IProfileService, DuplicateService duplicate.IDuplicateService, EligibilityPolicy eligibility.Policy, CustomerAddressClient customerAddressDB.ICustomerAddressRepository, CustomerEmploymentClient customerEmploymentDB.ICustomerEmploymentRepository, CustomerEmergencyContactClient customerEmergencyContactDB.ICustomerEmergencyContactRepository, SecurityEventClient securityEventDB.ISecurityEventRepository) ICustomerController {
 	return &CustomerController{
 		CustomerDBClient:        CustomerClient,
 		CustomerProfileDBClient: CustomerProfileClient,
 		CustomerTokenDBClient:   CustomerTokenClient,
 		CustomerLimitDBClient:   CustomerLimitClient,
-		JWT:                     JWT,
+
+		CustomerLimitHistoryDBClient: CustomerLimitHistoryClient,
+		LimitIncreaseRequestDBClient: LimitIncreaseRequestClient,
+		KycVerificationDBClient:      KycVerificationClient,
+		JWT:                          JWT,
+		CreditService:                CreditService,
+		LimitService:                 LimitService,
+		BlobStore:                    BlobStore,
+		URLSigner:                    URLSigner,
+		ImageRules:                   ImageRules,
+		KycService:                   KycService,
+		EmailVerificationService:     EmailVerificationService,
+		PhoneVerificationService:     PhoneVerificationService,
+		PasswordResetService:         PasswordResetService,
+		PasswordPolicy:               PasswordPolicy,
+		PrivacyService:               PrivacyService,
+		ProfileService:               ProfileService,
+		DuplicateService:             DuplicateService,
+		EligibilityPolicy:            EligibilityPolicy,
+
+		CustomerAddressDBClient:          CustomerAddressClient,
+		CustomerEmploymentDBClient:       CustomerEmploymentClient,
+		CustomerEmergencyContactDBClient: CustomerEmergencyContactClient,
+		SecurityEventDBClient:            SecurityEventClient,
 	}
 }
 
@@ -84,6 +187,16 @@ func (u CustomerController) CreateCustomer(c *gin.Context) {
 		return
 	}
 
+	// Phone numbers are stored in E.164 form, invalid ones are rejected by Validate
+	if phone, err := util.NormalizePhone(dataFromBody.Phone); err == nil {
+		dataFromBody.Phone = phone
+	}
+
+	if err := u.PasswordPolicy.Validate(dataFromBody.Password); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
 	hashedPassword, err := util.GenerateHash(dataFromBody.Password)
 	if err != nil {
 		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
@@ -105,6 +218,9 @@ func (u CustomerController) CreateCustomer(c *gin.Context) {
 		Email:     dataFromBody.Email,
 		Phone:     dataFromBody.Phone,
 		Password:  hashedPassword,
+		KycStatus: customerDBModels.KYC_STATUS_UNVERIFIED,
+		Status:    customerDBModels.STATUS_ACTIVE,
+		Role:      constants.USER.String(),
 		CreatedAt: now,
 		UpdatedAt: &now,
 	}
@@ -116,6 +232,19 @@ func (u CustomerController) CreateCustomer(c *gin.Context) {
 		return
 	}
 
+	// Tell the customer which contact detail is taken instead of failing on the unique constraint
+	err = u.DuplicateService.CheckContact(ctx, 0, customer.Email, customer.Phone)
+	switch {
+	case errors.Is(err, duplicate.ErrEmailRegistered), errors.Is(err, duplicate.ErrPhoneRegistered):
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, err)
+		return
+	case err != nil:
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
 	if err = u.CustomerDBClient.Create(ctx, &customer); err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
@@ -123,6 +252,10 @@ func (u CustomerController) CreateCustomer(c *gin.Context) {
 		return
 	}
 
+	if err := u.EmailVerificationService.Send(ctx, customer); err != nil {
+		log.Errorf("failed to send verification email: %v", err)
+	}
+
 	customer.Password = ""
 
 	controller.RespondWithSuccess(c, http.StatusOK, constants.CREATED_SUCCESSFULLY, customer, nil)
@@ -161,6 +294,10 @@ func (u CustomerController) GetCustomers(c *gin.Context) {
 		return
 	}
 
+	for i := range customers {
+		customers[i].Password = ""
+	}
+
 	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, customers, &paginationResponse)
 }
 
@@ -196,6 +333,8 @@ func (u CustomerController) GetCustomer(c *gin.Context) {
 		return
 	}
 
+	r.Password = ""
+
 	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, r, nil)
 }
 
@@ -224,20 +363,65 @@ func (u CustomerController) UpdateCustomer(c *gin.Context) {
 
 	patcher := make(map[string]interface{})
 
-	if dataFromBody.Email != "" {
+	filter := map[string]interface{}{
+		customerDBModels.COLUMN_UUID: id,
+	}
+
+	current, err := u.CustomerDBClient.Get(ctx, filter)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if current.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	// A new email address has to be verified again
+	emailChanged := dataFromBody.Email != "" && dataFromBody.Email != current.Email
+
+	if emailChanged {
 		patcher[customerDBModels.COLUMN_EMAIL] = dataFromBody.Email
+		patcher[customerDBModels.COLUMN_EMAIL_VERIFIED_AT] = nil
 	}
 
+	// A new phone number has to be verified again
 	if dataFromBody.Phone != "" {
-		patcher[customerDBModels.COLUMN_PHONE] = dataFromBody.Phone
+		phone, err := util.NormalizePhone(dataFromBody.Phone)
+		if err != nil {
+			errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+			log.Error(errorMsg)
+			controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+			return
+		}
+
+		if phone != current.Phone {
+			patcher[customerDBModels.COLUMN_PHONE] = phone
+			patcher[customerDBModels.COLUMN_PHONE_VERIFIED_AT] = nil
+		}
 	}
 
-	patcher[customerDBModels.COLUMN_UPDATED_AT] = time.Now()
+	email, _ := patcher[customerDBModels.COLUMN_EMAIL].(string)
+	phone, _ := patcher[customerDBModels.COLUMN_PHONE].(string)
 
-	filter := map[string]interface{}{
-		customerDBModels.COLUMN_UUID: id,
+	// Tell the customer which contact detail is taken instead of failing on the unique constraint
+	err = u.DuplicateService.CheckContact(ctx, current.ID, email, phone)
+	switch {
+	case errors.Is(err, duplicate.ErrEmailRegistered), errors.Is(err, duplicate.ErrPhoneRegistered):
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, err)
+		return
+	case err != nil:
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
 	}
 
+	patcher[customerDBModels.COLUMN_UPDATED_AT] = time.Now()
+
 	if err := u.CustomerDBClient.Update(ctx, filter, patcher); err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
@@ -258,6 +442,12 @@ func (u CustomerController) UpdateCustomer(c *gin.Context) {
 		return
 	}
 
+	if emailChanged {
+		if err := u.EmailVerificationService.Send(ctx, customer); err != nil {
+			log.Errorf("failed to send verification email: %v", err)
+		}
+	}
+
 	customer.Password = ""
 
 	controller.RespondWithSuccess(c, http.StatusAccepted, constants.UPDATED_SUCCESSFULLY, customer, nil)
@@ -0,0 +1,325 @@
+package customer
+
+import (
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/controller"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerEmergencyContactDBModels "kredit-plus/app/db/dto/customer_emergency_contact"
+	"kredit-plus/app/service/correlation"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/logger"
+	"kredit-plus/app/service/util"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (u CustomerController) CreateCustomerEmergencyContact(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	var dataFromBody customerEmergencyContactDBModels.CustomerEmergencyContact
+	if err := c.BindJSON(&dataFromBody); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	// Phone numbers are stored in E.164 form, invalid ones are rejected by Validate
+	if phone, err := util.NormalizePhone(dataFromBody.Phone); err == nil {
+		dataFromBody.Phone = phone
+	}
+
+	now := time.Now()
+
+	customerEmergencyContact := customerEmergencyContactDBModels.CustomerEmergencyContact{
+		CustomerID:   user.ID,
+		Name:         dataFromBody.Name,
+		Relationship: dataFromBody.Relationship,
+		Phone:        dataFromBody.Phone,
+		CreatedAt:    now,
+		UpdatedAt:    &now,
+	}
+
+	if err := customerEmergencyContact.Validate(); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	if customerEmergencyContact.Phone == user.Phone {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.EMERGENCY_CONTACT_IS_CUSTOMER))
+		return
+	}
+
+	p := request.Pagination{
+		GetAllData: true,
+	}
+
+	p.Validate()
+
+	_, paginationResponse, err := u.CustomerEmergencyContactDBClient.List(ctx, p, map[string]interface{}{customerEmergencyContactDBModels.COLUMN_CUSTOMER_ID: user.ID})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if paginationResponse.TotalCount >= customerEmergencyContactDBModels.MAX_CONTACTS {
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, errors.New(constants.EMERGENCY_CONTACT_LIMIT_REACHED))
+		return
+	}
+
+	if err := u.CustomerEmergencyContactDBClient.Create(ctx, &customerEmergencyContact); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.CREATED_SUCCESSFULLY, customerEmergencyContact, nil)
+}
+
+func (u CustomerController) GetCustomerEmergencyContacts(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	p := request.Pagination{
+		GetAllData: true,
+	}
+
+	p.Validate()
+
+	customerEmergencyContacts, _, err := u.CustomerEmergencyContactDBClient.List(ctx, p, map[string]interface{}{customerEmergencyContactDBModels.COLUMN_CUSTOMER_ID: user.ID})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, customerEmergencyContacts, nil)
+}
+
+func (u CustomerController) GetCustomerEmergencyContact(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	id := c.Param("id")
+	if _, err := strconv.Atoi(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	r, err := u.CustomerEmergencyContactDBClient.Get(ctx, map[string]interface{}{
+		customerEmergencyContactDBModels.COLUMN_ID:          id,
+		customerEmergencyContactDBModels.COLUMN_CUSTOMER_ID: user.ID,
+	})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if r.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, r, nil)
+}
+
+func (u CustomerController) UpdateCustomerEmergencyContact(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	id := c.Param("id")
+	if _, err := strconv.Atoi(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	var dataFromBody customerEmergencyContactDBModels.CustomerEmergencyContact
+	if err := c.ShouldBindJSON(&dataFromBody); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	filter := map[string]interface{}{
+		customerEmergencyContactDBModels.COLUMN_ID:          id,
+		customerEmergencyContactDBModels.COLUMN_CUSTOMER_ID: user.ID,
+	}
+
+	current, err := u.CustomerEmergencyContactDBClient.Get(ctx, filter)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if current.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	patcher := make(map[string]interface{})
+
+	if dataFromBody.Name != "" {
+		patcher[customerEmergencyContactDBModels.COLUMN_NAME] = dataFromBody.Name
+		current.Name = dataFromBody.Name
+	}
+
+	if dataFromBody.Relationship != "" {
+		patcher[customerEmergencyContactDBModels.COLUMN_RELATIONSHIP] = dataFromBody.Relationship
+		current.Relationship = dataFromBody.Relationship
+	}
+
+	if dataFromBody.Phone != "" {
+		phone, err := util.NormalizePhone(dataFromBody.Phone)
+		if err != nil {
+			errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+			log.Error(errorMsg)
+			controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+			return
+		}
+
+		patcher[customerEmergencyContactDBModels.COLUMN_PHONE] = phone
+		current.Phone = phone
+	}
+
+	if err := current.Validate(); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	if current.Phone == user.Phone {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.EMERGENCY_CONTACT_IS_CUSTOMER))
+		return
+	}
+
+	patcher[customerEmergencyContactDBModels.COLUMN_UPDATED_AT] = time.Now()
+
+	if err := u.CustomerEmergencyContactDBClient.Update(ctx, filter, patcher); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	customerEmergencyContact, err := u.CustomerEmergencyContactDBClient.Get(ctx, filter)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusAccepted, constants.UPDATED_SUCCESSFULLY, customerEmergencyContact, nil)
+}
+
+func (u CustomerController) DeleteCustomerEmergencyContact(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	id := c.Param("id")
+	if _, err := strconv.Atoi(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	filter := map[string]interface{}{
+		customerEmergencyContactDBModels.COLUMN_ID:          id,
+		customerEmergencyContactDBModels.COLUMN_CUSTOMER_ID: user.ID,
+	}
+
+	if err := u.CustomerEmergencyContactDBClient.Delete(ctx, filter); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.DELETED_SUCCESSFULLY, nil, nil)
+}
@@ -0,0 +1,350 @@
+package customer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/controller"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerProfileDBModels "kredit-plus/app/db/dto/customer_profile"
+	kycVerificationDBModels "kredit-plus/app/db/dto/kyc_verification"
+	"kredit-plus/app/service/correlation"
+	"kredit-plus/app/service/dto/request"
+	customerResponse "kredit-plus/app/service/dto/response/customer"
+	"kredit-plus/app/service/kyc"
+	limitService "kredit-plus/app/service/limit"
+	"kredit-plus/app/service/logger"
+	"kredit-plus/app/service/storage"
+	"kredit-plus/app/service/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const KYC_FORM_FILE = "file"
+
+// kycColumns maps the KYC image type in the path to its profile column.
+var kycColumns = map[string]string{
+	kyc.TYPE_KTP:    customerProfileDBModels.COLUMN_KTP_IMAGE,
+	kyc.TYPE_SELFIE: customerProfileDBModels.COLUMN_SELFIE_IMAGE,
+}
+
+func kycImage(profile customerProfileDBModels.CustomerProfile, kycType string) string {
+	if kycType == kyc.TYPE_KTP {
+		return profile.KtpImage
+	}
+	return profile.SelfieImage
+}
+
+// UploadKycImage stores a KTP or selfie image and keeps its storage key on the profile.
+func (u CustomerController) UploadKycImage(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	kycType := c.Param("type")
+	column, ok := kycColumns[kycType]
+	if !ok {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_KYC_TYPE))
+		return
+	}
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	filter := map[string]interface{}{
+		customerProfileDBModels.COLUMN_CUSTOMER_ID: user.ID,
+	}
+
+	profile, err := u.CustomerProfileDBClient.Get(ctx, filter)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if profile.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	fileHeader, err := c.FormFile(KYC_FORM_FILE)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	if fileHeader.Size > u.ImageRules.MaxSize {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.IMAGE_TOO_LARGE))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+	defer file.Close()
+
+	image, err := u.ImageRules.Process(file)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	key := kyc.Key(user.UUID.String(), kycType, id.String(), image.Extension)
+
+	if err := u.BlobStore.Put(ctx, key, bytes.NewReader(image.Data)); err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	patcher := map[string]interface{}{
+		column: key,
+		customerProfileDBModels.COLUMN_UPDATED_AT: time.Now(),
+	}
+
+	if kycType == kyc.TYPE_SELFIE {
+		patcher[customerProfileDBModels.COLUMN_SELFIE_HASH] = image.Hash
+	}
+
+	if _, err := u.ProfileService.Update(ctx, user.ID, patcher, limitService.CustomerActor(userUUID)); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	// The previous image is no longer referenced; a failed delete only leaves an orphaned blob
+	if previous := kycImage(profile, kycType); kyc.IsStorageKey(previous) {
+		if err := u.BlobStore.Delete(ctx, previous); err != nil {
+			log.Errorf("failed to delete previous %s image: %v", kycType, err)
+		}
+	}
+
+	previous := profile
+
+	profile, err = u.CustomerProfileDBClient.Get(ctx, filter)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if kycType == kyc.TYPE_SELFIE {
+		if _, err := u.DuplicateService.Flag(ctx, profile); err != nil {
+			log.Errorf("failed to check for duplicate accounts: %v", err)
+		}
+	}
+
+	// A new image has not been checked yet, so the customer has to be verified again
+	if err := u.KycService.Refresh(ctx, user, previous, profile); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.UPLOAD_SUCCESSFULLY, profile, nil)
+}
+
+// GetKycImageURL returns a short-lived signed URL for the KTP or selfie image of the customer.
+func (u CustomerController) GetKycImageURL(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	kycType := c.Param("type")
+	if _, ok := kycColumns[kycType]; !ok {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_KYC_TYPE))
+		return
+	}
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	profile, err := u.CustomerProfileDBClient.Get(ctx, map[string]interface{}{customerProfileDBModels.COLUMN_CUSTOMER_ID: user.ID})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	key := kycImage(profile, kycType)
+	if profile.ID == 0 || !kyc.IsStorageKey(key) {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	url, expiresAt := u.URLSigner.Sign(key, time.Now())
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, customerResponse.KycImageURL{URL: url, ExpiresAt: expiresAt}, nil)
+}
+
+// DownloadKycImage serves a KYC image to the holder of a valid signed URL.
+func (u CustomerController) DownloadKycImage(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	key := c.Query(storage.QUERY_KEY)
+	if !kyc.IsStorageKey(key) || !u.URLSigner.Verify(key, c.Query(storage.QUERY_EXPIRES), c.Query(storage.QUERY_SIGNATURE), time.Now()) {
+		controller.RespondWithError(c, http.StatusForbidden, constants.FORBIDDEN, errors.New(constants.INVALID_SIGNED_URL))
+		return
+	}
+
+	blob, err := u.BlobStore.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+	defer blob.Close()
+
+	c.Header("Cache-Control", "private, no-store")
+	c.DataFromReader(http.StatusOK, -1, kyc.ContentType(key), blob, nil)
+}
+
+// SubmitKycVerification verifies the identity of the customer against the uploaded KYC images.
+func (u CustomerController) SubmitKycVerification(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	switch user.KycStatus {
+	case customerDBModels.KYC_STATUS_PENDING:
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, errors.New(constants.KYC_ALREADY_PENDING))
+		return
+	case customerDBModels.KYC_STATUS_VERIFIED:
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, errors.New(constants.KYC_ALREADY_VERIFIED))
+		return
+	}
+
+	profile, err := u.CustomerProfileDBClient.Get(ctx, map[string]interface{}{customerProfileDBModels.COLUMN_CUSTOMER_ID: user.ID})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if profile.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	if !kyc.IsStorageKey(profile.KtpImage) || !kyc.IsStorageKey(profile.SelfieImage) {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.KYC_IMAGES_REQUIRED))
+		return
+	}
+
+	verification, err := u.KycService.Verify(ctx, user, profile)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusCreated, constants.CREATED_SUCCESSFULLY, verification, nil)
+}
+
+// GetKycVerification returns the KYC status of the customer with the latest verification.
+func (u CustomerController) GetKycVerification(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	p := request.Pagination{
+		Limit: util.Int(1),
+		Page:  util.Int(1),
+		Sort:  kycVerificationDBModels.COLUMN_CREATED_AT,
+		Order: "DESC",
+	}
+
+	p.Validate()
+
+	verifications, _, err := u.KycVerificationDBClient.List(ctx, p, map[string]interface{}{kycVerificationDBModels.COLUMN_CUSTOMER_ID: user.ID})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	status := customerResponse.KycStatus{Status: user.KycStatus}
+	if len(verifications) > 0 {
+		status.Verification = &verifications[0]
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, status, nil)
+}
@@ -7,67 +7,18 @@ import (
 	"kredit-plus/app/controller"
 	customerDBModels "kredit-plus/app/db/dto/customer"
 	customerLimitDBModels "kredit-plus/app/db/dto/customer_limit"
+	customerLimitHistoryDBModels "kredit-plus/app/db/dto/customer_limit_history"
 	"kredit-plus/app/service/correlation"
 	"kredit-plus/app/service/dto/request"
+	customerResponse "kredit-plus/app/service/dto/response/customer"
 	"kredit-plus/app/service/logger"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func (u CustomerController) CreateCustomerLimit(c *gin.Context) {
-	ctx := correlation.WithReqContext(c)
-	log := logger.Logger(ctx)
-
-	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
-	if !exist {
-		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
-		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
-		return
-	}
-
-	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
-	if err != nil {
-		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
-		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
-		return
-	}
-
-	var dataFromBody customerLimitDBModels.CustomerLimit
-	if err := c.BindJSON(&dataFromBody); err != nil {
-		log.Error(constants.BAD_REQUEST, err)
-		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
-		return
-	}
-
-	now := time.Now()
-
-	customerLimit := customerLimitDBModels.CustomerLimit{
-		CustomerID:  user.ID,
-		Tenor:       dataFromBody.Tenor,
-		LimitAmount: dataFromBody.LimitAmount,
-		CreatedAt:   now,
-		UpdatedAt:   &now,
-	}
-
-	if err := customerLimit.Validate(); err != nil {
-		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
-		log.Error(errorMsg)
-		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
-		return
-	}
-
-	if err := u.CustomerLimitDBClient.Create(ctx, &customerLimit); err != nil {
-		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
-		log.Error(errorMsg)
-		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
-		return
-	}
-
-	controller.RespondWithSuccess(c, http.StatusOK, constants.CREATED_SUCCESSFULLY, customerLimit, nil)
-}
-
 func (u CustomerController) GetCustomerLimits(c *gin.Context) {
 	ctx := correlation.WithReqContext(c)
 	log := logger.Logger(ctx)
@@ -109,6 +60,10 @@ func (u CustomerController) GetCustomerLimits(c *gin.Context) {
 		f[customerLimitDBModels.COLUMN_LIMIT_AMOUNT] = c.Query(customerLimitDBModels.COLUMN_LIMIT_AMOUNT)
 	}
 
+	if c.Query(customerLimitDBModels.COLUMN_STATUS) != "" {
+		f[customerLimitDBModels.COLUMN_STATUS] = c.Query(customerLimitDBModels.COLUMN_STATUS)
+	}
+
 	customerLimits, paginationResponse, err := u.CustomerLimitDBClient.List(ctx, pagination, f)
 	if err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
@@ -158,7 +113,7 @@ func (u CustomerController) GetCustomerLimit(c *gin.Context) {
 	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, r, nil)
 }
 
-func (u CustomerController) UpdateCustomerLimit(c *gin.Context) {
+func (u CustomerController) GetCustomerLimitHistory(c *gin.Context) {
 	ctx := correlation.WithReqContext(c)
 	log := logger.Logger(ctx)
 
@@ -168,6 +123,11 @@ func (u CustomerController) UpdateCustomerLimit(c *gin.Context) {
 		return
 	}
 
+	if _, err := strconv.Atoi(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
 	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
 	if !exist {
 		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
@@ -182,39 +142,27 @@ func (u CustomerController) UpdateCustomerLimit(c *gin.Context) {
 		return
 	}
 
-	var dataFromBody customerLimitDBModels.CustomerLimit
-	if err := c.ShouldBindJSON(&dataFromBody); err != nil {
+	var pagination request.Pagination
+
+	if err := c.ShouldBindQuery(&pagination); err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
 		log.Error(errorMsg)
 		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
 		return
 	}
 
-	patcher := make(map[string]interface{})
-
-	if dataFromBody.LimitAmount != 0 {
-		patcher[customerLimitDBModels.COLUMN_LIMIT_AMOUNT] = dataFromBody.LimitAmount
-	}
-
-	if dataFromBody.Tenor != 0 {
-		patcher[customerLimitDBModels.COLUMN_TENOR] = dataFromBody.Tenor
-	}
-
-	patcher[customerDBModels.COLUMN_UPDATED_AT] = time.Now()
+	pagination.Validate()
 
-	filter := map[string]interface{}{
-		customerLimitDBModels.COLUMN_ID:          id,
-		customerLimitDBModels.COLUMN_CUSTOMER_ID: user.ID,
+	f := map[string]interface{}{
+		customerLimitHistoryDBModels.COLUMN_CUSTOMER_LIMIT_ID: id,
+		customerLimitHistoryDBModels.COLUMN_CUSTOMER_ID:       user.ID,
 	}
 
-	if err := u.CustomerLimitDBClient.Update(ctx, filter, patcher); err != nil {
-		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
-		log.Error(errorMsg)
-		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
-		return
+	if c.Query(customerLimitHistoryDBModels.COLUMN_ACTION) != "" {
+		f[customerLimitHistoryDBModels.COLUMN_ACTION] = c.Query(customerLimitHistoryDBModels.COLUMN_ACTION)
 	}
 
-	customer, err := u.CustomerLimitDBClient.Get(ctx, filter)
+	history, paginationResponse, err := u.CustomerLimitHistoryDBClient.List(ctx, pagination, f)
 	if err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
@@ -222,24 +170,13 @@ func (u CustomerController) UpdateCustomerLimit(c *gin.Context) {
 		return
 	}
 
-	if customer.ID == 0 {
-		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
-		return
-	}
-
-	controller.RespondWithSuccess(c, http.StatusAccepted, constants.UPDATED_SUCCESSFULLY, customer, nil)
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, history, &paginationResponse)
 }
 
-func (u CustomerController) DeleteCustomerLimit(c *gin.Context) {
+func (u CustomerController) GetCustomerLimitSummary(c *gin.Context) {
 	ctx := correlation.WithReqContext(c)
 	log := logger.Logger(ctx)
 
-	id := c.Param("id")
-	if id == "" {
-		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
-		return
-	}
-
 	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
 	if !exist {
 		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
@@ -254,17 +191,13 @@ func (u CustomerController) DeleteCustomerLimit(c *gin.Context) {
 		return
 	}
 
-	filter := map[string]interface{}{
-		customerLimitDBModels.COLUMN_ID:          id,
-		customerLimitDBModels.COLUMN_CUSTOMER_ID: user.ID,
-	}
-
-	if err := u.CustomerLimitDBClient.Delete(ctx, filter); err != nil {
+	utilizations, err := u.CustomerLimitDBClient.Utilization(ctx, user.ID)
+	if err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
 		return
 	}
 
-	controller.RespondWithSuccess(c, http.StatusOK, constants.DELETED_SUCCESSFULLY, nil, nil)
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, customerResponse.NewLimitSummary(utilizations, time.Now()), nil)
 }
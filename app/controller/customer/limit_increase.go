@@ -0,0 +1,233 @@
+package customer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/controller"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	limitIncreaseRequestDBModels "kredit-plus/app/db/dto/limit_increase_request"
+	"kredit-plus/app/service/correlation"
+	"kredit-plus/app/service/dto/request"
+	customerRequest "kredit-plus/app/service/dto/request/customer"
+	"kredit-plus/app/service/logger"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm/dialects/postgres"
+)
+
+func (u CustomerController) CreateLimitIncreaseRequest(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	// Only customers whose identity has been verified may use credit
+	if !user.IsKycVerified() {
+		controller.RespondWithError(c, http.StatusForbidden, constants.FORBIDDEN, errors.New(constants.KYC_NOT_VERIFIED))
+		return
+	}
+
+	var dataFromBody customerRequest.LimitIncreaseRequest
+	if err := c.BindJSON(&dataFromBody); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	if err := dataFromBody.Validate(u.CreditService.Tenors()); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	// Only one open request per tenor
+	for _, status := range []string{limitIncreaseRequestDBModels.STATUS_SUBMITTED, limitIncreaseRequestDBModels.STATUS_UNDER_REVIEW} {
+		openRequest, err := u.LimitIncreaseRequestDBClient.Get(ctx, map[string]interface{}{
+			limitIncreaseRequestDBModels.COLUMN_CUSTOMER_ID: user.ID,
+			limitIncreaseRequestDBModels.COLUMN_TENOR:       dataFromBody.Tenor,
+			limitIncreaseRequestDBModels.COLUMN_STATUS:      status,
+		})
+		if err != nil {
+			log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+			controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+			return
+		}
+
+		if openRequest.ID != 0 {
+			controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, errors.New(constants.LIMIT_INCREASE_REQUEST_OPEN))
+			return
+		}
+	}
+
+	utilizations, err := u.CustomerLimitDBClient.Utilization(ctx, user.ID)
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	// The request is for a higher granted limit, not for what remains of it
+	var currentLimitAmount float32
+	for _, utilization := range utilizations {
+		if utilization.Tenor == dataFromBody.Tenor {
+			currentLimitAmount = utilization.Granted()
+		}
+	}
+
+	if dataFromBody.RequestedAmount <= currentLimitAmount {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.LIMIT_INCREASE_NOT_HIGHER))
+		return
+	}
+
+	documents, err := json.Marshal(dataFromBody.Documents)
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	now := time.Now()
+
+	limitIncreaseRequest := limitIncreaseRequestDBModels.LimitIncreaseRequest{
+		CustomerID:         user.ID,
+		Tenor:              dataFromBody.Tenor,
+		CurrentLimitAmount: currentLimitAmount,
+		RequestedAmount:    dataFromBody.RequestedAmount,
+		Documents:          postgres.Jsonb{RawMessage: documents},
+		Reason:             dataFromBody.Reason,
+		Status:             limitIncreaseRequestDBModels.STATUS_SUBMITTED,
+		CreatedAt:          now,
+		UpdatedAt:          &now,
+	}
+
+	if err := limitIncreaseRequest.Validate(); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	if err := u.LimitIncreaseRequestDBClient.Create(ctx, &limitIncreaseRequest); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.CREATED_SUCCESSFULLY, limitIncreaseRequest, nil)
+}
+
+func (u CustomerController) GetLimitIncreaseRequests(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	var pagination request.Pagination
+
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	pagination.Validate()
+
+	f := map[string]interface{}{}
+
+	f[limitIncreaseRequestDBModels.COLUMN_CUSTOMER_ID] = user.ID
+
+	if c.Query(limitIncreaseRequestDBModels.COLUMN_TENOR) != "" {
+		f[limitIncreaseRequestDBModels.COLUMN_TENOR] = c.Query(limitIncreaseRequestDBModels.COLUMN_TENOR)
+	}
+
+	if c.Query(limitIncreaseRequestDBModels.COLUMN_STATUS) != "" {
+		f[limitIncreaseRequestDBModels.COLUMN_STATUS] = c.Query(limitIncreaseRequestDBModels.COLUMN_STATUS)
+	}
+
+	limitIncreaseRequests, paginationResponse, err := u.LimitIncreaseRequestDBClient.List(ctx, pagination, f)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, limitIncreaseRequests, &paginationResponse)
+}
+
+func (u CustomerController) GetLimitIncreaseRequest(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	id := c.Param("id")
+	if _, err := strconv.Atoi(id); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+		return
+	}
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	filter := map[string]interface{}{
+		limitIncreaseRequestDBModels.COLUMN_ID:          id,
+		limitIncreaseRequestDBModels.COLUMN_CUSTOMER_ID: user.ID,
+	}
+
+	r, err := u.LimitIncreaseRequestDBClient.Get(ctx, filter)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if r.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, r, nil)
+}
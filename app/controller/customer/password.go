@@ -0,0 +1,188 @@
+package customer
+
+import (
+	"errors"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/controller"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	"kredit-plus/app/service/correlation"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/logger"
+	"kredit-plus/app/service/util"
+	"kredit-plus/app/service/verification"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ForgotPassword sends a password reset token to the email address or phone number in the request.
+// The response is the same whether or not an account exists, so it cannot be used to look up customers.
+func (u CustomerController) ForgotPassword(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	var dataFromBody request.ForgotPassword
+	if err := c.BindJSON(&dataFromBody); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	if err := dataFromBody.Validate(); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	filter := map[string]interface{}{
+		customerDBModels.COLUMN_EMAIL: dataFromBody.Email,
+	}
+	channel := verification.CHANNEL_EMAIL
+
+	if dataFromBody.Phone != "" {
+		filter = map[string]interface{}{
+			customerDBModels.COLUMN_PHONE: dataFromBody.Phone,
+		}
+		if phone, err := util.NormalizePhone(dataFromBody.Phone); err == nil {
+			filter[customerDBModels.COLUMN_PHONE] = phone
+		}
+		channel = verification.CHANNEL_SMS
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, filter)
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if user.ID != 0 {
+		if err := u.PasswordResetService.Request(ctx, user, channel); err != nil {
+			log.Errorf("failed to send password reset: %v", err)
+		}
+	}
+
+	controller.RespondWithSuccess(c, http.StatusAccepted, constants.PASSWORD_RESET_REQUESTED, nil, nil)
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword and signs the customer out everywhere.
+func (u CustomerController) ResetPassword(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	var dataFromBody request.ResetPassword
+	if err := c.BindJSON(&dataFromBody); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	if err := dataFromBody.Validate(); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	if err := u.PasswordPolicy.Validate(dataFromBody.Password); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	customer, err := u.PasswordResetService.Reset(ctx, dataFromBody.Token, dataFromBody.Password)
+	if errors.Is(err, verification.ErrInvalidResetToken) {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	customer.Password = ""
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.PASSWORD_RESET_SUCCESS, customer, nil)
+}
+
+// ChangePassword sets a new password after checking the current one. Every other session
+// of the customer is revoked; the session making the request stays signed in.
+func (u CustomerController) ChangePassword(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	tokenID := c.GetInt(constants.CTK_TOKEN_ID_KEY.String())
+
+	var dataFromBody request.ChangePassword
+	if err := c.BindJSON(&dataFromBody); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	if err := dataFromBody.Validate(); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	filter := map[string]interface{}{
+		customerDBModels.COLUMN_UUID: userUUID,
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, filter)
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if user.ID == 0 || !util.ValidatePassword(dataFromBody.CurrentPassword, user.Password) {
+		controller.RespondWithError(c, http.StatusUnauthorized, "Wrong credentials", errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	if dataFromBody.NewPassword == dataFromBody.CurrentPassword {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.PASSWORD_NOT_CHANGED))
+		return
+	}
+
+	if err := u.PasswordPolicy.Validate(dataFromBody.NewPassword); err != nil {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	hashedPassword, err := util.GenerateHash(dataFromBody.NewPassword)
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	patcher := map[string]interface{}{
+		customerDBModels.COLUMN_PASSWORD:   hashedPassword,
+		customerDBModels.COLUMN_UPDATED_AT: time.Now(),
+	}
+
+	if err := u.CustomerDBClient.Update(ctx, filter, patcher); err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if err := u.CustomerTokenDBClient.DeleteOthers(ctx, user.ID, tokenID); err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.PASSWORD_CHANGED, nil, nil)
+}
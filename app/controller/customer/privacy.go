@@ -0,0 +1,124 @@
+package customer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/controller"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	"kredit-plus/app/service/correlation"
+	customerRequest "kredit-plus/app/service/dto/request/customer"
+	limitService "kredit-plus/app/service/limit"
+	"kredit-plus/app/service/logger"
+	"kredit-plus/app/service/privacy"
+	"kredit-plus/app/service/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportData returns the personal data of the signed in customer as JSON, or as a ZIP
+// archive including the KYC images with ?format=zip.
+func (u CustomerController) ExportData(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	format := c.DefaultQuery("format", privacy.FORMAT_JSON)
+	if format != privacy.FORMAT_JSON && format != privacy.FORMAT_ZIP {
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New("format must be json or zip"))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	export, err := u.PrivacyService.Export(ctx, user)
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+
+	if format == privacy.FORMAT_JSON {
+		controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, export, nil)
+		return
+	}
+
+	var archive bytes.Buffer
+	if err := u.PrivacyService.Archive(ctx, export, &archive); err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"kredit-plus-data-%s.zip\"", user.UUID))
+	c.Data(http.StatusOK, "application/zip", archive.Bytes())
+}
+
+// EraseData anonymizes the personal data of the signed in customer and closes the account.
+// The current password is required to confirm the request.
+func (u CustomerController) EraseData(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	var dataFromBody customerRequest.DataErasureRequest
+	if err := c.BindJSON(&dataFromBody); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	if err := dataFromBody.Validate(); err != nil {
+		log.Error(constants.BAD_REQUEST, err)
+		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, err)
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if user.ID == 0 || !util.ValidatePassword(dataFromBody.Password, user.Password) {
+		controller.RespondWithError(c, http.StatusUnauthorized, "Wrong credentials", errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	customer, err := u.PrivacyService.Erase(ctx, user, privacy.REASON_CUSTOMER_REQUEST, limitService.CustomerActor(userUUID))
+	switch {
+	case errors.Is(err, privacy.ErrOutstandingLoans), errors.Is(err, privacy.ErrErasureNotAllowed), errors.Is(err, privacy.ErrAlreadyErased):
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, err)
+		return
+	case err != nil:
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	customer.Password = ""
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.DATA_ERASED, customer, nil)
+}
@@ -6,6 +6,9 @@ import (
 	"kredit-plus/app/constants"
 	"kredit-plus/app/controller"
 	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerAddressDBModels "kredit-plus/app/db/dto/customer_address"
+	customerEmergencyContactDBModels "kredit-plus/app/db/dto/customer_emergency_contact"
+	customerEmploymentDBModels "kredit-plus/app/db/dto/customer_employment"
 	customerLimitDBModels "kredit-plus/app/db/dto/customer_limit"
 	customerProfileDBModels "kredit-plus/app/db/dto/customer_profile"
 	customerTokenDBModels "kredit-plus/app/db/dto/customer_token"
@@ -13,7 +16,10 @@ import (
 	"kredit-plus/app/service/correlation"
 	"kredit-plus/app/service/dto/request"
 	customerResponse "kredit-plus/app/service/dto/response/customer"
+	"kredit-plus/app/service/duplicate"
+	limitService "kredit-plus/app/service/limit"
 	"kredit-plus/app/service/logger"
+	"kredit-plus/app/service/profile"
 	"net/http"
 	"time"
 
@@ -55,8 +61,6 @@ func (u CustomerController) CreateCustomerProfile(c *gin.Context) {
 		PlaceOfBirth: dataFromBody.PlaceOfBirth,
 		DateOfBirth:  dataFromBody.DateOfBirth,
 		Salary:       dataFromBody.Salary,
-		KtpImage:     dataFromBody.KtpImage,
-		SelfieImage:  dataFromBody.SelfieImage,
 		CreatedAt:    now,
 		UpdatedAt:    &now,
 	}
@@ -68,14 +72,57 @@ func (u CustomerController) CreateCustomerProfile(c *gin.Context) {
 		return
 	}
 
-	if err := u.CustomerProfileDBClient.Delete(ctx, map[string]interface{}{customerProfileDBModels.COLUMN_CUSTOMER_ID: user.ID}); err != nil {
+	if err := u.EligibilityPolicy.Check(customerProfile.DateOfBirth, now); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	err = u.DuplicateService.CheckNIK(ctx, user.ID, customerProfile.NIK)
+	switch {
+	case errors.Is(err, duplicate.ErrNIKRegistered):
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, err)
+		return
+	case err != nil:
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	// KYC images are uploaded separately, so keep the ones of the profile being replaced
+	existing, err := u.CustomerProfileDBClient.Get(ctx, map[string]interface{}{customerProfileDBModels.COLUMN_CUSTOMER_ID: user.ID})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	customerProfile.KtpImage = existing.KtpImage
+	customerProfile.SelfieImage = existing.SelfieImage
+	customerProfile.SelfieHash = existing.SelfieHash
+
+	// The replaced profile is kept as an earlier version
+	err = u.ProfileService.Create(ctx, &customerProfile, limitService.CustomerActor(userUUID))
+	switch {
+	case errors.Is(err, duplicate.ErrNIKRegistered):
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, err)
+		return
+	case err != nil:
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
 		return
 	}
 
-	if err := u.CustomerProfileDBClient.Create(ctx, &customerProfile); err != nil {
+	// Possible duplicates are queued before the limits are refreshed, so they are withheld until reviewed
+	if _, err := u.DuplicateService.Flag(ctx, customerProfile); err != nil {
+		log.Errorf("failed to check for duplicate accounts: %v", err)
+	}
+
+	if err := u.KycService.Refresh(ctx, user, existing, customerProfile); err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
@@ -164,7 +211,9 @@ func (u CustomerController) Profile(c *gin.Context) {
 
 	response.Profile = customerProfile
 
+	// A customer can be signed in on several devices, the token is the session of this request
 	filter = map[string]interface{}{
+		customerTokenDBModels.COLUMN_ID:          c.GetInt(constants.CTK_TOKEN_ID_KEY.String()),
 		customerTokenDBModels.COLUMN_CUSTOMER_ID: customer.ID,
 	}
 
@@ -176,6 +225,7 @@ func (u CustomerController) Profile(c *gin.Context) {
 		return
 	}
 
+	customerToken.Current = customerToken.ID != 0
 	response.Token = customerToken
 
 	filter = map[string]interface{}{
@@ -198,6 +248,38 @@ func (u CustomerController) Profile(c *gin.Context) {
 
 	response.Limit = customerLimit
 
+	customerAddresses, _, err := u.CustomerAddressDBClient.List(ctx, p, map[string]interface{}{customerAddressDBModels.COLUMN_CUSTOMER_ID: customer.ID})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	response.Addresses = customerAddresses
+
+	customerEmployment, err := u.CustomerEmploymentDBClient.Get(ctx, map[string]interface{}{customerEmploymentDBModels.COLUMN_CUSTOMER_ID: customer.ID})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if customerEmployment.ID != 0 {
+		response.Employment = &customerEmployment
+	}
+
+	customerEmergencyContacts, _, err := u.CustomerEmergencyContactDBClient.List(ctx, p, map[string]interface{}{customerEmergencyContactDBModels.COLUMN_CUSTOMER_ID: customer.ID})
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	response.EmergencyContacts = customerEmergencyContacts
+
 	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, response, nil)
 }
 
@@ -245,45 +327,95 @@ func (u CustomerController) UpdateCustomerProfile(c *gin.Context) {
 		patcher[customerProfileDBModels.COLUMN_PLACE_OF_BIRTH] = dataFromBody.PlaceOfBirth
 	}
 
-	if dataFromBody.DateOfBirth != "" {
-		patcher[customerProfileDBModels.COLUMN_DATE_OF_BIRTH] = dataFromBody.DateOfBirth
+	if !dataFromBody.DateOfBirth.IsZero() {
+		patcher[customerProfileDBModels.COLUMN_DATE_OF_BIRTH] = dataFromBody.DateOfBirth.String()
 	}
 
 	if dataFromBody.Salary != 0 {
 		patcher[customerProfileDBModels.COLUMN_SALARY] = dataFromBody.Salary
 	}
 
-	if dataFromBody.KtpImage != "" {
-		patcher[customerProfileDBModels.COLUMN_KTP_IMAGE] = dataFromBody.KtpImage
+	patcher[customerDBModels.COLUMN_UPDATED_AT] = time.Now()
+
+	filter := map[string]interface{}{
+		customerProfileDBModels.COLUMN_CUSTOMER_ID: user.ID,
+	}
+
+	previous, err := u.CustomerProfileDBClient.Get(ctx, filter)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
 	}
 
-	if dataFromBody.SelfieImage != "" {
-		patcher[customerProfileDBModels.COLUMN_SELFIE_IMAGE] = dataFromBody.SelfieImage
+	if previous.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
 	}
 
-	patcher[customerDBModels.COLUMN_UPDATED_AT] = time.Now()
+	// The NIK and date of birth are validated together, so check the patched profile
+	if dataFromBody.NIK != "" || !dataFromBody.DateOfBirth.IsZero() {
+		current := previous
 
-	filter := map[string]interface{}{
-		customerProfileDBModels.COLUMN_CUSTOMER_ID: user.ID,
+		if dataFromBody.NIK != "" {
+			current.NIK = dataFromBody.NIK
+		}
+
+		if !dataFromBody.DateOfBirth.IsZero() {
+			current.DateOfBirth = dataFromBody.DateOfBirth
+		}
+
+		if err := current.Validate(); err != nil {
+			errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+			log.Error(errorMsg)
+			controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+			return
+		}
 	}
 
-	if err := u.CustomerProfileDBClient.Update(ctx, filter, patcher); err != nil {
+	if !dataFromBody.DateOfBirth.IsZero() {
+		if err := u.EligibilityPolicy.Check(dataFromBody.DateOfBirth, time.Now()); err != nil {
+			errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+			log.Error(errorMsg)
+			controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+			return
+		}
+	}
+
+	err = u.DuplicateService.CheckNIK(ctx, user.ID, dataFromBody.NIK)
+	switch {
+	case errors.Is(err, duplicate.ErrNIKRegistered):
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, err)
+		return
+	case err != nil:
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
 		return
 	}
 
-	customer, err := u.CustomerProfileDBClient.Get(ctx, filter)
-	if err != nil {
+	customer, err := u.ProfileService.Update(ctx, user.ID, patcher, limitService.CustomerActor(userUUID))
+	switch {
+	case errors.Is(err, duplicate.ErrNIKRegistered):
+		controller.RespondWithError(c, http.StatusConflict, constants.CONFLICT, err)
+		return
+	case err != nil:
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
 		return
 	}
 
-	if customer.ID == 0 {
-		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+	// Possible duplicates are queued before the limits are refreshed, so they are withheld until reviewed
+	if _, err := u.DuplicateService.Flag(ctx, customer); err != nil {
+		log.Errorf("failed to check for duplicate accounts: %v", err)
+	}
+
+	if err := u.KycService.Refresh(ctx, user, previous, customer); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
 		return
 	}
 
@@ -308,16 +440,102 @@ func (u CustomerController) DeleteCustomerProfile(c *gin.Context) {
 		return
 	}
 
-	filter := map[string]interface{}{
-		customerProfileDBModels.COLUMN_CUSTOMER_ID: user.ID,
+	if err := u.ProfileService.Delete(ctx, user.ID, limitService.CustomerActor(userUUID)); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.DELETED_SUCCESSFULLY, nil, nil)
+}
+
+// GetProfileVersions lists the recorded versions of the profile of the customer.
+func (u CustomerController) GetProfileVersions(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
 	}
 
-	if err := u.CustomerProfileDBClient.Delete(ctx, filter); err != nil {
+	var pagination request.Pagination
+
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	pagination.Validate()
+
+	versions, paginationResponse, err := u.ProfileService.Versions(ctx, user.ID, pagination)
+	if err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
 		return
 	}
 
-	controller.RespondWithSuccess(c, http.StatusOK, constants.DELETED_SUCCESSFULLY, nil, nil)
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, versions, &paginationResponse)
+}
+
+// GetProfileVersionDiff compares two versions of the profile of the customer.
+func (u CustomerController) GetProfileVersionDiff(c *gin.Context) {
+	ctx := correlation.WithReqContext(c)
+	log := logger.Logger(ctx)
+
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	var query request.ProfileVersionDiff
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusBadRequest, errorMsg, err)
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	diff, err := u.ProfileService.Diff(ctx, user.ID, query.From, query.To)
+	switch {
+	case errors.Is(err, profile.ErrVersionNotFound):
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, err)
+		return
+	case err != nil:
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, diff, nil)
 }
@@ -5,16 +5,19 @@ import (
 	"fmt"
 	"kredit-plus/app/constants"
 	"kredit-plus/app/controller"
+	customerDBModels "kredit-plus/app/db/dto/customer"
 	customerTokenDBModels "kredit-plus/app/db/dto/customer_token"
 	"kredit-plus/app/service/correlation"
 	"kredit-plus/app/service/dto/request"
 	"kredit-plus/app/service/logger"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
+// GetCustomerTokens lists the active sessions of the customer, one per signed in device.
 func (u CustomerController) GetCustomerTokens(c *gin.Context) {
 	ctx := correlation.WithReqContext(c)
 	log := logger.Logger(ctx)
@@ -30,18 +33,32 @@ func (u CustomerController) GetCustomerTokens(c *gin.Context) {
 
 	pagination.Validate()
 
-	f := map[string]interface{}{}
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
 
-	if c.Query(customerTokenDBModels.COLUMN_CUSTOMER_ID) != "" {
-		f[customerTokenDBModels.COLUMN_CUSTOMER_ID] = c.Query(customerTokenDBModels.COLUMN_CUSTOMER_ID)
+	// Customers only see their own sessions, and only those that can still be refreshed
+	f := map[string]interface{}{
+		customerTokenDBModels.COLUMN_CUSTOMER_ID:                     user.ID,
+		customerTokenDBModels.COLUMN_REFRESH_TOKEN_EXPIRED_AT + " >": time.Now(),
 	}
 
-	if c.Query(customerTokenDBModels.COLUMN_ACCESS_TOKEN) != "" {
-		f[customerTokenDBModels.COLUMN_ACCESS_TOKEN] = c.Query(customerTokenDBModels.COLUMN_ACCESS_TOKEN)
+	if c.Query(customerTokenDBModels.COLUMN_NAME) != "" {
+		f[customerTokenDBModels.COLUMN_NAME] = c.Query(customerTokenDBModels.COLUMN_NAME)
 	}
 
-	if c.Query(customerTokenDBModels.COLUMN_REFRESH_TOKEN) != "" {
-		f[customerTokenDBModels.COLUMN_REFRESH_TOKEN] = c.Query(customerTokenDBModels.COLUMN_REFRESH_TOKEN)
+	if c.Query(customerTokenDBModels.COLUMN_DEVICE) != "" {
+		f[customerTokenDBModels.COLUMN_DEVICE] = c.Query(customerTokenDBModels.COLUMN_DEVICE)
 	}
 
 	if c.Query(customerTokenDBModels.COLUMN_USER_AGENT) != "" {
@@ -60,6 +77,11 @@ func (u CustomerController) GetCustomerTokens(c *gin.Context) {
 		return
 	}
 
+	tokenID := c.GetInt(constants.CTK_TOKEN_ID_KEY.String())
+	for i := range customerTokens {
+		customerTokens[i].Current = customerTokens[i].ID == tokenID
+	}
+
 	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, customerTokens, &paginationResponse)
 }
 
@@ -67,17 +89,32 @@ func (u CustomerController) GetCustomerToken(c *gin.Context) {
 	ctx := correlation.WithReqContext(c)
 	log := logger.Logger(ctx)
 
-	id := c.Param("uuid")
-	if id == "" {
+	id := c.Param("id")
+	if _, err := strconv.Atoi(id); err != nil {
 		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
 		return
 	}
 
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
 	filter := map[string]interface{}{
-		customerTokenDBModels.COLUMN_ID: id,
+		customerTokenDBModels.COLUMN_ID:          id,
+		customerTokenDBModels.COLUMN_CUSTOMER_ID: user.ID,
 	}
 
-	r, err := u.CustomerDBClient.Get(ctx, filter)
+	r, err := u.CustomerTokenDBClient.Get(ctx, filter)
 	if err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
@@ -85,29 +122,61 @@ func (u CustomerController) GetCustomerToken(c *gin.Context) {
 		return
 	}
 
-	if r.UUID == uuid.Nil {
+	if r.ID == 0 {
 		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
 		return
 	}
 
+	r.Current = r.ID == c.GetInt(constants.CTK_TOKEN_ID_KEY.String())
+
 	controller.RespondWithSuccess(c, http.StatusOK, constants.GET_SUCCESSFULLY, r, nil)
 }
 
+// DeleteCustomerToken revokes one session of the customer.
 func (u CustomerController) DeleteCustomerToken(c *gin.Context) {
 	ctx := correlation.WithReqContext(c)
 	log := logger.Logger(ctx)
 
 	id := c.Param(customerTokenDBModels.COLUMN_ID)
-	if id == "" {
+	if _, err := strconv.Atoi(id); err != nil {
 		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
 		return
 	}
 
+	userUUID, exist := c.Get(constants.CTK_CLAIM_KEY.String())
+	if !exist {
+		log.Error(constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		controller.RespondWithError(c, http.StatusUnauthorized, constants.UNAUTHORIZED_ACCESS, errors.New(constants.UNAUTHORIZED_ACCESS))
+		return
+	}
+
+	user, err := u.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: userUUID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
 	filter := map[string]interface{}{
-		customerTokenDBModels.COLUMN_ID: id,
+		customerTokenDBModels.COLUMN_ID:          id,
+		customerTokenDBModels.COLUMN_CUSTOMER_ID: user.ID,
+	}
+
+	r, err := u.CustomerTokenDBClient.Get(ctx, filter)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if r.ID == 0 {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
 	}
 
-	if err := u.CustomerDBClient.Delete(ctx, filter); err != nil {
+	// Revoking a session signs that device out, its tokens stop working right away
+	if err := u.CustomerTokenDBClient.Delete(ctx, filter); err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
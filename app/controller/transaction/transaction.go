@@ -17,6 +17,9 @@ import (
 	customerLimitDBModels "kredit-plus/app/db/dto/customer_limit"
 	customerLimitDB "kredit-plus/app/db/repository/customer_limit"
 
+	customerProfileDBModels "kredit-plus/app/db/dto/customer_profile"
+	customerProfileDB "kredit-plus/app/db/repository/customer_profile"
+
 	assetDBModels "kredit-plus/app/db/dto/asset"
 	assetDB "kredit-plus/app/db/repository/asset"
 
@@ -24,7 +27,10 @@ import (
 	"kredit-plus/app/service/dto/request"
 	transactionRequest "kredit-plus/app/service/dto/request/transaction"
 	transactionResponse "kredit-plus/app/service/dto/response/transaction"
+	"kredit-plus/app/service/eligibility"
+	limitService "kredit-plus/app/service/limit"
 	"kredit-plus/app/service/logger"
+	"kredit-plus/app/service/scope"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -47,15 +53,54 @@ type TransactionController struct {
 	CustomerDBClient      customerDB.ICustomerRepository
 	CustomerLimitDBClient customerLimitDB.ICustomerLimitRepository
 	AssetDBClient         assetDB.IAssetRepository
+
+	CustomerProfileDBClient customerProfileDB.ICustomerProfileRepository
+
+	LimitService      limitService.ILimitService
+	EligibilityPolicy eligibility.Policy
 }
 
-func NewTransactionController(TransactionClient transactionDB.ITransactionRepository, CustomerClient customerDB.ICustomerRepository, CustomerLimitClient customerLimitDB.ICustomerLimitRepository, AssetClient assetDB.IAssetRepository) ITransactionController {
+func NewTransactionController(TransactionClient transactionDB.ITransactionRepository, CustomerClient customerDB.ICustomerRepository, CustomerLimitClient customerLimitDB.ICustomerLimitRepository, AssetClient assetDB.IAssetRepository, LimitService limitService.ILimitService, CustomerProfileClient customerProfileDB.ICustomerProfileRepository, EligibilityPolicy eligibility.Policy) ITransactionController {
 	return &TransactionController{
 		TransactionDBClient:   TransactionClient,
 		CustomerDBClient:      CustomerClient,
 		CustomerLimitDBClient: CustomerLimitClient,
 		AssetDBClient:         AssetClient,
+		LimitService:          LimitService,
+
+		CustomerProfileDBClient: CustomerProfileClient,
+		EligibilityPolicy:       EligibilityPolicy,
+	}
+}
+
+// transactions returns the transaction repository limited to the scope of the caller.
+// It responds and reports false when the route is not behind the Scoped middleware.
+func (u TransactionController) transactions(c *gin.Context) (transactionDB.ITransactionRepository, bool) {
+	s, ok := scope.FromContext(c)
+	if !ok {
+		controller.RespondWithError(c, http.StatusForbidden, constants.FORBIDDEN, errors.New(constants.PERMISSION_DENIED))
+		return nil, false
 	}
+
+	return u.TransactionDBClient.Scoped(s), true
+}
+
+// managedTransactions returns the scoped transaction repository for changes made by admins
+// and partners. Customers create their transactions through checkout, which checks and
+// debits their limit, and cannot change or delete them.
+func (u TransactionController) managedTransactions(c *gin.Context) (transactionDB.ITransactionRepository, bool) {
+	s, ok := scope.FromContext(c)
+	if !ok {
+		controller.RespondWithError(c, http.StatusForbidden, constants.FORBIDDEN, errors.New(constants.PERMISSION_DENIED))
+		return nil, false
+	}
+
+	if s.IsCustomer() {
+		controller.RespondWithError(c, http.StatusForbidden, constants.FORBIDDEN, errors.New(constants.CHECKOUT_REQUIRED))
+		return nil, false
+	}
+
+	return u.TransactionDBClient.Scoped(s), true
 }
 
 func (u TransactionController) Checkout(c *gin.Context) {
@@ -78,6 +123,37 @@ func (u TransactionController) Checkout(c *gin.Context) {
 		return
 	}
 
+	if !user.IsActive() {
+		controller.RespondWithError(c, http.StatusForbidden, constants.FORBIDDEN, errors.New(constants.ACCOUNT_NOT_ACTIVE))
+		return
+	}
+
+	if !user.IsEmailVerified() {
+		controller.RespondWithError(c, http.StatusForbidden, constants.FORBIDDEN, errors.New(constants.EMAIL_NOT_VERIFIED))
+		return
+	}
+
+	// The phone number has to be verified before the first checkout
+	if !user.IsPhoneVerified() {
+		previous, err := u.TransactionDBClient.Get(ctx, map[string]interface{}{transactionDBModels.COLUMN_CUSTOMER_ID: user.ID})
+		if err != nil {
+			log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+			controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+			return
+		}
+
+		if previous.ID == 0 {
+			controller.RespondWithError(c, http.StatusForbidden, constants.FORBIDDEN, errors.New(constants.PHONE_NOT_VERIFIED))
+			return
+		}
+	}
+
+	// Only customers whose identity has been verified may use credit
+	if !user.IsKycVerified() {
+		controller.RespondWithError(c, http.StatusForbidden, constants.FORBIDDEN, errors.New(constants.KYC_NOT_VERIFIED))
+		return
+	}
+
 	// Parse and validate the request body
 	var dataFromBody transactionRequest.CheckoutRequest
 	if err := c.BindJSON(&dataFromBody); err != nil {
@@ -92,6 +168,19 @@ func (u TransactionController) Checkout(c *gin.Context) {
 		return
 	}
 
+	profile, err := u.CustomerProfileDBClient.Get(ctx, map[string]interface{}{customerProfileDBModels.COLUMN_CUSTOMER_ID: user.ID})
+	if err != nil {
+		log.Errorf(constants.INTERNAL_SERVER_ERROR, err)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	// The customer has to be of eligible age for the whole tenor
+	if err := u.EligibilityPolicy.CheckTenor(profile.DateOfBirth, time.Now(), dataFromBody.InstallmentPeriod); err != nil {
+		controller.RespondWithError(c, http.StatusForbidden, constants.FORBIDDEN, err)
+		return
+	}
+
 	// Get the customer limit from the database
 	customerLimit, err := u.CustomerLimitDBClient.Get(ctx, map[string]interface{}{
 		customerLimitDBModels.COLUMN_CUSTOMER_ID: user.ID,
@@ -110,6 +199,17 @@ func (u TransactionController) Checkout(c *gin.Context) {
 		return
 	}
 
+	// Check if the customer limit can still be used
+	if customerLimit.Status == customerLimitDBModels.STATUS_SUSPENDED {
+		controller.RespondWithError(c, http.StatusForbidden, constants.FORBIDDEN, errors.New(constants.LIMIT_SUSPENDED))
+		return
+	}
+
+	if customerLimit.IsExpired(time.Now()) {
+		controller.RespondWithError(c, http.StatusForbidden, constants.FORBIDDEN, errors.New(constants.LIMIT_EXPIRED))
+		return
+	}
+
 	// Create a new UUID
 	uuid, err := uuid.NewRandom()
 	if err != nil {
@@ -132,6 +232,7 @@ func (u TransactionController) Checkout(c *gin.Context) {
 		InstallmentPeriod: dataFromBody.InstallmentPeriod,
 		InterestAmount:    dataFromBody.InterestAmount,
 		SalesChannel:      dataFromBody.SalesChannel,
+		Status:            transactionDBModels.STATUS_PENDING,
 		CreatedAt:         now,
 		UpdatedAt:         &now,
 	}
@@ -182,27 +283,20 @@ func (u TransactionController) Checkout(c *gin.Context) {
 
 	// Update the customer's limit in a goroutine
 	go func() {
-		patcher := map[string]interface{}{
-			customerLimitDBModels.COLUMN_LIMIT_AMOUNT: customerLimit.LimitAmount - dataFromBody.InstallmentAmount,
-			customerLimitDBModels.COLUMN_UPDATED_AT:   time.Now(),
-		}
-
-		filter := map[string]interface{}{
-			customerLimitDBModels.COLUMN_CUSTOMER_ID: user.ID,
-			customerLimitDBModels.COLUMN_TENOR:       dataFromBody.InstallmentPeriod,
-		}
+		reason := fmt.Sprintf("%s %s", limitService.REASON_CHECKOUT, transaction.ContractNumber)
 
-		if err := u.CustomerLimitDBClient.Update(ctx, filter, patcher); err != nil {
+		if err := u.LimitService.Debit(ctx, customerLimit, dataFromBody.InstallmentAmount, limitService.CustomerActor(userUUID), reason); err != nil {
 			customerLimitErrCh <- err
 		} else {
 			customerLimitErrCh <- nil
 		}
 	}()
 
-	// Update the transaction with the asset's ID in a goroutine
+	// Update the transaction with the asset's ID and activate it in a goroutine
 	go func() {
 		patcher := map[string]interface{}{
 			transactionDBModels.COLUMN_ASSET_ID:   asset.ID,
+			transactionDBModels.COLUMN_STATUS:     transactionDBModels.STATUS_ACTIVE,
 			transactionDBModels.COLUMN_UPDATED_AT: time.Now(),
 		}
 
@@ -222,6 +316,21 @@ func (u TransactionController) Checkout(c *gin.Context) {
 	transactionUpdateErr := <-transactionUpdateErrCh
 
 	if customerLimitErr != nil {
+		// Release the hold, the limit was never debited
+		patcher := map[string]interface{}{
+			transactionDBModels.COLUMN_STATUS:     transactionDBModels.STATUS_CANCELLED,
+			transactionDBModels.COLUMN_UPDATED_AT: time.Now(),
+		}
+		if err := u.TransactionDBClient.Update(ctx, map[string]interface{}{transactionDBModels.COLUMN_UUID: transaction.UUID}, patcher); err != nil {
+			log.Error(constants.INTERNAL_SERVER_ERROR, err)
+		}
+
+		// A concurrent checkout spent the limit after it was checked above
+		if errors.Is(customerLimitErr, limitService.ErrInsufficientLimit) {
+			controller.RespondWithError(c, http.StatusForbidden, constants.FORBIDDEN, customerLimitErr)
+			return
+		}
+
 		log.Error(constants.INTERNAL_SERVER_ERROR, customerLimitErr)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, customerLimitErr)
 		return
@@ -234,6 +343,7 @@ func (u TransactionController) Checkout(c *gin.Context) {
 	}
 
 	transaction.AssetID = &asset.ID
+	transaction.Status = transactionDBModels.STATUS_ACTIVE
 
 	controller.RespondWithSuccess(c, http.StatusOK, constants.CREATED_SUCCESSFULLY, transaction, nil)
 }
@@ -242,6 +352,11 @@ func (u TransactionController) CreateTransaction(c *gin.Context) {
 	ctx := correlation.WithReqContext(c)
 	log := logger.Logger(ctx)
 
+	transactions, ok := u.managedTransactions(c)
+	if !ok {
+		return
+	}
+
 	var dataFromBody transactionDBModels.Transaction
 	if err := c.BindJSON(&dataFromBody); err != nil {
 		log.Error(constants.BAD_REQUEST, err)
@@ -267,10 +382,16 @@ func (u TransactionController) CreateTransaction(c *gin.Context) {
 		AdminFee:          dataFromBody.AdminFee,
 		InstallmentAmount: dataFromBody.InstallmentAmount,
 		InstallmentPeriod: dataFromBody.InstallmentPeriod,
+		SalesChannel:      dataFromBody.SalesChannel,
+		Status:            dataFromBody.Status,
 		CreatedAt:         now,
 		UpdatedAt:         &now,
 	}
 
+	if transaction.Status == "" {
+		transaction.Status = transactionDBModels.STATUS_ACTIVE
+	}
+
 	if err := transaction.Validate(); err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.BAD_REQUEST, err)
 		log.Error(errorMsg)
@@ -278,7 +399,7 @@ func (u TransactionController) CreateTransaction(c *gin.Context) {
 		return
 	}
 
-	if err = u.TransactionDBClient.Create(ctx, &transaction); err != nil {
+	if err = transactions.Create(ctx, &transaction); err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
@@ -292,6 +413,11 @@ func (u TransactionController) GetTransactions(c *gin.Context) {
 	ctx := correlation.WithReqContext(c)
 	log := logger.Logger(ctx)
 
+	scoped, ok := u.transactions(c)
+	if !ok {
+		return
+	}
+
 	var pagination request.Pagination
 
 	if err := c.ShouldBindQuery(&pagination); err != nil {
@@ -341,7 +467,12 @@ func (u TransactionController) GetTransactions(c *gin.Context) {
 		f[transactionDBModels.COLUMN_INSTALLMENT_PERIOD] = c.Query(transactionDBModels.COLUMN_INSTALLMENT_PERIOD)
 	}
 
-	transactions, paginationResponse, err := u.TransactionDBClient.List(ctx, pagination, f)
+	if c.Query(transactionDBModels.COLUMN_STATUS) != "" {
+		f[transactionDBModels.COLUMN_STATUS] = c.Query(transactionDBModels.COLUMN_STATUS)
+	}
+
+	// Filters only narrow the scope down, a customer_id of another customer matches nothing
+	transactions, paginationResponse, err := scoped.List(ctx, pagination, f)
 	if err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
@@ -356,6 +487,11 @@ func (u TransactionController) GetTransactionsDetail(c *gin.Context) {
 	ctx := correlation.WithReqContext(c)
 	log := logger.Logger(ctx)
 
+	scoped, ok := u.transactions(c)
+	if !ok {
+		return
+	}
+
 	response := []transactionResponse.TransactionDetailResponse{}
 
 	var pagination request.Pagination
@@ -407,7 +543,12 @@ func (u TransactionController) GetTransactionsDetail(c *gin.Context) {
 		f[transactionDBModels.COLUMN_INSTALLMENT_PERIOD] = c.Query(transactionDBModels.COLUMN_INSTALLMENT_PERIOD)
 	}
 
-	transactions, paginationResponse, err := u.TransactionDBClient.List(ctx, pagination, f)
+	if c.Query(transactionDBModels.COLUMN_STATUS) != "" {
+		f[transactionDBModels.COLUMN_STATUS] = c.Query(transactionDBModels.COLUMN_STATUS)
+	}
+
+	// Filters only narrow the scope down, a customer_id of another customer matches nothing
+	transactions, paginationResponse, err := scoped.List(ctx, pagination, f)
 	if err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
@@ -454,6 +595,11 @@ func (u TransactionController) GetTransaction(c *gin.Context) {
 	ctx := correlation.WithReqContext(c)
 	log := logger.Logger(ctx)
 
+	transactions, ok := u.transactions(c)
+	if !ok {
+		return
+	}
+
 	id := c.Param(transactionDBModels.COLUMN_UUID)
 	if id == "" {
 		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
@@ -469,7 +615,7 @@ func (u TransactionController) GetTransaction(c *gin.Context) {
 		transactionDBModels.COLUMN_UUID: id,
 	}
 
-	r, err := u.TransactionDBClient.Get(ctx, filter)
+	r, err := transactions.Get(ctx, filter)
 	if err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
@@ -489,6 +635,11 @@ func (u TransactionController) UpdateTransaction(c *gin.Context) {
 	ctx := correlation.WithReqContext(c)
 	log := logger.Logger(ctx)
 
+	transactions, ok := u.managedTransactions(c)
+	if !ok {
+		return
+	}
+
 	id := c.Param(transactionDBModels.COLUMN_UUID)
 	if id == "" {
 		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
@@ -530,20 +681,42 @@ func (u TransactionController) UpdateTransaction(c *gin.Context) {
 		patcher[transactionDBModels.COLUMN_INSTALLMENT_PERIOD] = dataFromBody.InstallmentPeriod
 	}
 
+	if dataFromBody.Status != "" {
+		if !transactionDBModels.IsValidStatus(dataFromBody.Status) {
+			controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
+			return
+		}
+		patcher[transactionDBModels.COLUMN_STATUS] = dataFromBody.Status
+	}
+
 	patcher[transactionDBModels.COLUMN_UPDATED_AT] = time.Now()
 
 	filter := map[string]interface{}{
 		transactionDBModels.COLUMN_UUID: id,
 	}
 
-	if err := u.TransactionDBClient.Update(ctx, filter, patcher); err != nil {
+	// Transactions outside the scope of the caller are not found
+	existing, err := transactions.Get(ctx, filter)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if existing.UUID == uuid.Nil {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	if err := transactions.Update(ctx, filter, patcher); err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
 		return
 	}
 
-	transaction, err := u.TransactionDBClient.Get(ctx, filter)
+	transaction, err := transactions.Get(ctx, filter)
 	if err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
@@ -563,6 +736,11 @@ func (u TransactionController) DeleteTransaction(c *gin.Context) {
 	ctx := correlation.WithReqContext(c)
 	log := logger.Logger(ctx)
 
+	transactions, ok := u.managedTransactions(c)
+	if !ok {
+		return
+	}
+
 	id := c.Param(transactionDBModels.COLUMN_UUID)
 	if id == "" {
 		controller.RespondWithError(c, http.StatusBadRequest, constants.BAD_REQUEST, errors.New(constants.INVALID_INPUT))
@@ -578,7 +756,21 @@ func (u TransactionController) DeleteTransaction(c *gin.Context) {
 		transactionDBModels.COLUMN_UUID: id,
 	}
 
-	if err := u.TransactionDBClient.Delete(ctx, filter); err != nil {
+	// Transactions outside the scope of the caller are not found
+	transaction, err := transactions.Get(ctx, filter)
+	if err != nil {
+		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
+		log.Error(errorMsg)
+		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
+		return
+	}
+
+	if transaction.UUID == uuid.Nil {
+		controller.RespondWithError(c, http.StatusNotFound, constants.NOT_FOUND, errors.New(constants.RESOURCE_NOT_FOUND))
+		return
+	}
+
+	if err := transactions.Delete(ctx, filter); err != nil {
 		errorMsg := fmt.Sprintf("%s: %v", constants.INTERNAL_SERVER_ERROR, err)
 		log.Error(errorMsg)
 		controller.RespondWithError(c, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR, err)
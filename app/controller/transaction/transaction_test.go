@@ -0,0 +1,228 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	transactionDBModels "kredit-plus/app/db/dto/transaction"
+	transactionDB "kredit-plus/app/db/repository/transaction"
+	"kredit-plus/app/service/logger"
+	"kredit-plus/app/service/scope"
+	"kredit-plus/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"go.uber.org/zap"
+)
+
+// newTestController returns a controller on an in-memory database holding one transaction
+// of customer 1 sold through channel A and one of customer 2 sold through channel B.
+func newTestController(t *testing.T) (TransactionController, transactionDB.ITransactionRepository, transactionDBModels.Transaction, transactionDBModels.Transaction) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	constants.Config = &config.ServiceConfig{}
+	logger.SugarLogger = zap.NewNop().Sugar()
+
+	conn, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	// Every connection to :memory: opens a database of its own
+	conn.DB().SetMaxOpenConns(1)
+
+	err = conn.Exec(`CREATE TABLE transactions (
+		id integer PRIMARY KEY AUTOINCREMENT,
+		uuid varchar(36) NOT NULL,
+		customer_id integer NOT NULL,
+		asset_id integer,
+		contract_number varchar(255),
+		otr_amount real,
+		admin_fee real,
+		installment_amount real,
+		installment_period integer,
+		interest_amount real,
+		sales_channel varchar(255),
+		status varchar(50),
+		created_at datetime,
+		updated_at datetime
+	)`).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repository := transactionDB.NewTransactionRepository(db.New(conn))
+
+	own := newTestTransaction(1, "A")
+	other := newTestTransaction(2, "B")
+
+	for _, transaction := range []*transactionDBModels.Transaction{&own, &other} {
+		if err := repository.Create(context.Background(), transaction); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return TransactionController{TransactionDBClient: repository}, repository, own, other
+}
+
+func newTestTransaction(customerID int, salesChannel string) transactionDBModels.Transaction {
+	now := time.Now()
+
+	return transactionDBModels.Transaction{
+		UUID:              uuid.New(),
+		CustomerID:        customerID,
+		ContractNumber:    uuid.NewString(),
+		OTRAmount:         1000,
+		AdminFee:          10,
+		InstallmentAmount: 100,
+		InstallmentPeriod: 12,
+		SalesChannel:      salesChannel,
+		Status:            transactionDBModels.STATUS_ACTIVE,
+		CreatedAt:         now,
+		UpdatedAt:         &now,
+	}
+}
+
+// newTestContext returns a request context carrying the scope the Scoped middleware would set.
+func newTestContext(method, target, body string, s scope.Scope, id uuid.UUID) (*gin.Context, *httptest.ResponseRecorder) {
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+
+	c.Request = httptest.NewRequest(method, target, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: transactionDBModels.COLUMN_UUID, Value: id.String()}}
+	c.Set(constants.CTK_SCOPE_KEY.String(), s)
+
+	return c, recorder
+}
+
+func TestGetTransactionOfAnotherCustomer(t *testing.T) {
+	controller, _, own, other := newTestController(t)
+
+	tests := []struct {
+		name  string
+		scope scope.Scope
+		uuid  uuid.UUID
+		code  int
+	}{
+		{"customer reads own transaction", scope.Scope{CustomerID: 1}, own.UUID, http.StatusOK},
+		{"customer reads transaction of another customer", scope.Scope{CustomerID: 1}, other.UUID, http.StatusNotFound},
+		{"partner reads transaction of another channel", scope.Scope{SalesChannel: "A"}, other.UUID, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, recorder := newTestContext(http.MethodGet, "/", "", tt.scope, tt.uuid)
+
+			controller.GetTransaction(c)
+
+			if recorder.Code != tt.code {
+				t.Errorf("code = %d, want %d", recorder.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestUpdateTransactionOfAnotherChannel(t *testing.T) {
+	controller, repository, _, other := newTestController(t)
+
+	c, recorder := newTestContext(http.MethodPatch, "/", `{"status":"cancelled"}`, scope.Scope{SalesChannel: "A"}, other.UUID)
+
+	controller.UpdateTransaction(c)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("code = %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+
+	transaction, err := repository.Get(context.Background(), map[string]interface{}{transactionDBModels.COLUMN_UUID: other.UUID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if transaction.Status != transactionDBModels.STATUS_ACTIVE {
+		t.Errorf("status = %s, want %s", transaction.Status, transactionDBModels.STATUS_ACTIVE)
+	}
+}
+
+func TestDeleteTransactionOfAnotherChannel(t *testing.T) {
+	controller, repository, _, other := newTestController(t)
+
+	c, recorder := newTestContext(http.MethodDelete, "/", "", scope.Scope{SalesChannel: "A"}, other.UUID)
+
+	controller.DeleteTransaction(c)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("code = %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+
+	transaction, err := repository.Get(context.Background(), map[string]interface{}{transactionDBModels.COLUMN_UUID: other.UUID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if transaction.UUID != other.UUID {
+		t.Error("deleted a transaction of another channel")
+	}
+}
+
+func TestCustomerCannotChangeTransactions(t *testing.T) {
+	controller, _, own, _ := newTestController(t)
+	customer := scope.Scope{CustomerID: 1}
+
+	tests := []struct {
+		name    string
+		method  string
+		body    string
+		handler func(c *gin.Context)
+	}{
+		{"create", http.MethodPost, `{"customer_id":1,"contract_number":"C-1","otr_amount":1000,"installment_amount":100,"installment_period":12}`, controller.CreateTransaction},
+		{"update", http.MethodPatch, `{"status":"cancelled"}`, controller.UpdateTransaction},
+		{"delete", http.MethodDelete, "", controller.DeleteTransaction},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, recorder := newTestContext(tt.method, "/", tt.body, customer, own.UUID)
+
+			tt.handler(c)
+
+			if recorder.Code != http.StatusForbidden {
+				t.Errorf("code = %d, want %d", recorder.Code, http.StatusForbidden)
+			}
+		})
+	}
+}
+
+func TestGetTransactionsDetailOfAnotherCustomer(t *testing.T) {
+	controller, _, _, _ := newTestController(t)
+
+	c, recorder := newTestContext(http.MethodGet, "/?customer_id=2", "", scope.Scope{CustomerID: 1}, uuid.Nil)
+
+	controller.GetTransactionsDetail(c)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("code = %d, want %d", recorder.Code, http.StatusOK)
+	}
+
+	var body struct {
+		Data []json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(body.Data) != 0 {
+		t.Errorf("listed %d transactions of another customer", len(body.Data))
+	}
+}
@@ -0,0 +1,29 @@
+package wellknown
+
+import (
+	"kredit-plus/app/api/middleware/jwt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IWellKnownController interface {
+	JWKS(c *gin.Context)
+}
+
+type WellKnownController struct {
+	JWT jwt.IJWTService
+}
+
+func NewWellKnownController(JWT jwt.IJWTService) IWellKnownController {
+	return &WellKnownController{
+		JWT: JWT,
+	}
+}
+
+// JWKS serves the public keys access tokens are signed with. The document is written as is,
+// not wrapped in the usual response, so JWT libraries of other services can read it.
+func (w *WellKnownController) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, w.JWT.JWKS())
+}
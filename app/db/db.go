@@ -2,6 +2,8 @@ package db
 
 import (
 	"context"
+	"database/sql/driver"
+	"errors"
 	"fmt"
 	"kredit-plus/app/constants"
 	"kredit-plus/app/service/logger"
@@ -10,6 +12,7 @@ import (
 	"time"
 
 	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
 
 	"bitbucket.org/liamstask/goose/lib/goose"
 )
@@ -126,3 +129,46 @@ type DBService struct {
 func (d DBService) GetDB() *gorm.DB {
 	return d.DB.Debug()
 }
+
+// ILocker takes locks shared by every instance of the service.
+type ILocker interface {
+	TryLock(ctx context.Context, name string) (unlock func(), locked bool, err error)
+}
+
+// TryLock takes the Postgres advisory lock with the given name on a connection of its own,
+// so jobs started by every instance run in one at a time. It returns false when another
+// session holds the lock. The lock is held until unlock is called.
+func (d DBService) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := d.DB.DB().Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", name).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+
+	if !locked {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", name); err != nil {
+			// A connection still holding the lock must not go back to the pool
+			conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}
+
+	return unlock, true, nil
+}
+
+// IsUniqueViolation reports whether the error is a Postgres unique violation of the
+// constraint or unique index with the given name.
+func IsUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
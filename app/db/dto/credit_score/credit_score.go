@@ -0,0 +1,49 @@
+package credit_score
+
+import (
+	"errors"
+	"kredit-plus/app/constants"
+	"time"
+
+	"github.com/jinzhu/gorm/dialects/postgres"
+)
+
+const (
+	TABLE_NAME             = "credit_scores"
+	COLUMN_ID              = "id"
+	COLUMN_CUSTOMER_ID     = "customer_id"
+	COLUMN_SCORECARD       = "scorecard"
+	COLUMN_SCORE           = "score"
+	COLUMN_GRADE           = "grade"
+	COLUMN_EXPLANATION     = "explanation"
+	COLUMN_PROFILE_VERSION = "profile_version"
+	COLUMN_CREATED_AT      = "created_at"
+)
+
+type CreditScore struct {
+	ID             int            `json:"id"`
+	CustomerID     int            `json:"customer_id" form:"customer_id"`
+	Scorecard      string         `json:"scorecard" form:"scorecard"`
+	Score          float64        `json:"score" form:"score"`
+	Grade          string         `json:"grade" form:"grade"`
+	Explanation    postgres.Jsonb `json:"explanation"`
+	ProfileVersion int            `json:"profile_version"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// Validate the fields of a creditScore.
+func (u *CreditScore) Validate() error {
+	if u.CustomerID == 0 {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.Scorecard == "" {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.Grade == "" {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	return nil
+}
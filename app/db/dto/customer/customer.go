@@ -16,10 +16,43 @@ const (
 	COLUMN_PHONE      = "phone"
 	COLUMN_PASSWORD   = "password"
 	COLUMN_LAST_LOGIN = "last_login"
+	COLUMN_KYC_STATUS = "kyc_status"
+	COLUMN_STATUS     = "status"
+	COLUMN_ROLE       = "role"
 	COLUMN_CREATED_AT = "created_at"
 	COLUMN_UPDATED_AT = "updated_at"
+
+	COLUMN_STATUS_REASON     = "status_reason"
+	COLUMN_STATUS_CHANGED_AT = "status_changed_at"
+	COLUMN_EMAIL_VERIFIED_AT = "email_verified_at"
+	COLUMN_PHONE_VERIFIED_AT = "phone_verified_at"
+	COLUMN_ERASED_AT         = "erased_at"
+	COLUMN_SALES_CHANNEL     = "sales_channel"
+)
+
+const (
+	KYC_STATUS_UNVERIFIED = "unverified"
+	KYC_STATUS_PENDING    = "pending"
+	KYC_STATUS_VERIFIED   = "verified"
+	KYC_STATUS_REJECTED   = "rejected"
+)
+
+const (
+	STATUS_ACTIVE      = "active"
+	STATUS_SUSPENDED   = "suspended"
+	STATUS_CLOSED      = "closed"
+	STATUS_BLACKLISTED = "blacklisted"
 )
 
+// statusTransitions lists the statuses an account may move to from each status.
+// A blacklisted account is final.
+var statusTransitions = map[string][]string{
+	STATUS_ACTIVE:      {STATUS_SUSPENDED, STATUS_CLOSED, STATUS_BLACKLISTED},
+	STATUS_SUSPENDED:   {STATUS_ACTIVE, STATUS_CLOSED, STATUS_BLACKLISTED},
+	STATUS_CLOSED:      {STATUS_ACTIVE, STATUS_BLACKLISTED},
+	STATUS_BLACKLISTED: {},
+}
+
 type Customer struct {
 	ID        int        `json:"-"`
 	UUID      uuid.UUID  `json:"uuid" form:"uuid"`
@@ -27,14 +60,67 @@ type Customer struct {
 	Phone     string     `json:"phone" form:"phone"`
 	Password  string     `json:"password,omitempty"`
 	LastLogin time.Time  `json:"last_login"`
+	KycStatus string     `json:"kyc_status"`
+	Status    string     `json:"status"`
+	Role      string     `json:"role"`
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at"`
+	ErasedAt        *time.Time `json:"erased_at,omitempty"`
+
+	// SalesChannel is the channel a partner account acts for
+	SalesChannel string `json:"sales_channel,omitempty"`
 }
 
 func (Customer) TableName() string {
 	return TABLE_NAME
 }
 
+// IsKycVerified reports whether the identity of the customer has been verified.
+func (f Customer) IsKycVerified() bool {
+	return f.KycStatus == KYC_STATUS_VERIFIED
+}
+
+// IsEmailVerified reports whether the customer has proven ownership of the email address.
+func (f Customer) IsEmailVerified() bool {
+	return f.EmailVerifiedAt != nil
+}
+
+// IsPhoneVerified reports whether the customer has proven ownership of the phone number.
+func (f Customer) IsPhoneVerified() bool {
+	return f.PhoneVerifiedAt != nil
+}
+
+// IsErased reports whether the personal data of the customer has been erased.
+func (f Customer) IsErased() bool {
+	return f.ErasedAt != nil
+}
+
+// IsActive reports whether the customer may sign in and use the account.
+func (f Customer) IsActive() bool {
+	return f.Status == STATUS_ACTIVE
+}
+
+// CanTransition reports whether the account may move from its current status to the given one.
+func (f Customer) CanTransition(status string) bool {
+	for _, next := range statusTransitions[f.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidStatus reports whether the given value is a known account status.
+func IsValidStatus(status string) bool {
+	_, ok := statusTransitions[status]
+	return ok
+}
+
 func (f Customer) Validate() error {
 	if f.Email == "" {
 		return errors.New("email is required")
@@ -45,6 +131,9 @@ func (f Customer) Validate() error {
 	if f.Phone == "" {
 		return errors.New("phone is required")
 	}
+	if !util.IsValidPhone(f.Phone) {
+		return errors.New("phone is invalid")
+	}
 	if f.Password == "" {
 		return errors.New("password is required")
 	}
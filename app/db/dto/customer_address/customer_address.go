@@ -0,0 +1,77 @@
+package customer_address
+
+import (
+	"errors"
+	"kredit-plus/app/constants"
+	"regexp"
+	"time"
+)
+
+const (
+	TABLE_NAME         = "customer_addresses"
+	COLUMN_ID          = "id"
+	COLUMN_CUSTOMER_ID = "customer_id"
+	COLUMN_TYPE        = "type"
+	COLUMN_STREET      = "street"
+	COLUMN_PROVINCE    = "province"
+	COLUMN_CITY        = "city"
+	COLUMN_DISTRICT    = "district"
+	COLUMN_POSTAL_CODE = "postal_code"
+	COLUMN_CREATED_AT  = "created_at"
+	COLUMN_UPDATED_AT  = "updated_at"
+)
+
+const (
+	// TYPE_KTP is the address printed on the KTP
+	TYPE_KTP = "ktp"
+	// TYPE_DOMICILE is where the customer currently lives
+	TYPE_DOMICILE = "domicile"
+)
+
+var postalCode = regexp.MustCompile(`^[1-9][0-9]{4}$`)
+
+// CustomerAddress is an address of a customer. Province, city (kota or kabupaten),
+// district (kecamatan) and postal code narrow the location down from top to bottom.
+type CustomerAddress struct {
+	ID         int        `json:"id"`
+	CustomerID int        `json:"customer_id" form:"customer_id"`
+	Type       string     `json:"type" form:"type"`
+	Street     string     `json:"street" form:"street"`
+	Province   string     `json:"province" form:"province"`
+	City       string     `json:"city" form:"city"`
+	District   string     `json:"district" form:"district"`
+	PostalCode string     `json:"postal_code" form:"postal_code"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+func IsValidType(addressType string) bool {
+	return addressType == TYPE_KTP || addressType == TYPE_DOMICILE
+}
+
+// Validate the fields of a customerAddress.
+func (u *CustomerAddress) Validate() error {
+	if u.CustomerID == 0 {
+		return errors.New(constants.INVALID_INPUT)
+	}
+	if !IsValidType(u.Type) {
+		return errors.New("type must be one of ktp or domicile")
+	}
+	if u.Street == "" {
+		return errors.New("street is required")
+	}
+	if u.Province == "" {
+		return errors.New("province is required")
+	}
+	if u.City == "" {
+		return errors.New("city is required")
+	}
+	if u.District == "" {
+		return errors.New("district is required")
+	}
+	if !postalCode.MatchString(u.PostalCode) {
+		return errors.New("postal code must be 5 digits")
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package customer_emergency_contact
+
+import (
+	"errors"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/service/util"
+	"time"
+)
+
+const (
+	TABLE_NAME          = "customer_emergency_contacts"
+	COLUMN_ID           = "id"
+	COLUMN_CUSTOMER_ID  = "customer_id"
+	COLUMN_NAME         = "name"
+	COLUMN_RELATIONSHIP = "relationship"
+	COLUMN_PHONE        = "phone"
+	COLUMN_CREATED_AT   = "created_at"
+	COLUMN_UPDATED_AT   = "updated_at"
+)
+
+const (
+	RELATIONSHIP_PARENT    = "parent"
+	RELATIONSHIP_SPOUSE    = "spouse"
+	RELATIONSHIP_SIBLING   = "sibling"
+	RELATIONSHIP_CHILD     = "child"
+	RELATIONSHIP_RELATIVE  = "relative"
+	RELATIONSHIP_FRIEND    = "friend"
+	RELATIONSHIP_COLLEAGUE = "colleague"
+)
+
+// MAX_CONTACTS is the number of emergency contacts a customer can have
+const MAX_CONTACTS = 3
+
+var relationships = map[string]bool{
+	RELATIONSHIP_PARENT:    true,
+	RELATIONSHIP_SPOUSE:    true,
+	RELATIONSHIP_SIBLING:   true,
+	RELATIONSHIP_CHILD:     true,
+	RELATIONSHIP_RELATIVE:  true,
+	RELATIONSHIP_FRIEND:    true,
+	RELATIONSHIP_COLLEAGUE: true,
+}
+
+// CustomerEmergencyContact is someone who can be reached when the customer cannot.
+type CustomerEmergencyContact struct {
+	ID           int        `json:"id"`
+	CustomerID   int        `json:"customer_id" form:"customer_id"`
+	Name         string     `json:"name" form:"name"`
+	Relationship string     `json:"relationship" form:"relationship"`
+	Phone        string     `json:"phone" form:"phone"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+func IsValidRelationship(relationship string) bool {
+	return relationships[relationship]
+}
+
+// Validate the fields of a customerEmergencyContact.
+func (u *CustomerEmergencyContact) Validate() error {
+	if u.CustomerID == 0 {
+		return errors.New(constants.INVALID_INPUT)
+	}
+	if u.Name == "" {
+		return errors.New("name is required")
+	}
+	if !IsValidRelationship(u.Relationship) {
+		return errors.New("relationship must be one of parent, spouse, sibling, child, relative, friend or colleague")
+	}
+	if u.Phone == "" {
+		return errors.New("phone is required")
+	}
+	if !util.IsValidPhone(u.Phone) {
+		return errors.New("phone is invalid")
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package customer_employment
+
+import (
+	"errors"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/service/util"
+	"time"
+)
+
+const (
+	TABLE_NAME           = "customer_employments"
+	COLUMN_ID            = "id"
+	COLUMN_CUSTOMER_ID   = "customer_id"
+	COLUMN_EMPLOYER_NAME = "employer_name"
+	COLUMN_POSITION      = "position"
+	COLUMN_START_DATE    = "start_date"
+	COLUMN_INCOME_PROOF  = "income_proof"
+	COLUMN_CREATED_AT    = "created_at"
+	COLUMN_UPDATED_AT    = "updated_at"
+)
+
+// CustomerEmployment is the current job of a customer. The tenure follows from StartDate;
+// IncomeProof is the storage key of an uploaded payslip or employment letter.
+type CustomerEmployment struct {
+	ID           int        `json:"id"`
+	CustomerID   int        `json:"customer_id" form:"customer_id"`
+	EmployerName string     `json:"employer_name" form:"employer_name"`
+	Position     string     `json:"position" form:"position"`
+	StartDate    util.Date  `json:"start_date" form:"start_date"`
+	IncomeProof  string     `json:"income_proof" form:"income_proof"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+// Validate the fields of a customerEmployment.
+func (u *CustomerEmployment) Validate() error {
+	if u.CustomerID == 0 {
+		return errors.New(constants.INVALID_INPUT)
+	}
+	if u.EmployerName == "" {
+		return errors.New("employer name is required")
+	}
+	if u.Position == "" {
+		return errors.New("position is required")
+	}
+	if u.StartDate.IsZero() {
+		return errors.New("start date is required")
+	}
+	if u.StartDate.After(time.Now()) {
+		return errors.New("start date cannot be in the future")
+	}
+
+	return nil
+}
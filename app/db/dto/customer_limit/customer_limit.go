@@ -12,19 +12,62 @@ const (
 	COLUMN_CUSTOMER_ID  = "customer_id"
 	COLUMN_TENOR        = "tenor"
 	COLUMN_LIMIT_AMOUNT = "limit_amount"
+	COLUMN_VALID_FROM   = "valid_from"
+	COLUMN_VALID_UNTIL  = "valid_until"
+	COLUMN_STATUS       = "status"
 	COLUMN_CREATED_AT   = "created_at"
 	COLUMN_UPDATED_AT   = "updated_at"
 )
 
+const (
+	STATUS_ACTIVE    = "active"
+	STATUS_SUSPENDED = "suspended"
+)
+
 type CustomerLimit struct {
 	ID          int        `json:"id"`
 	CustomerID  int        `json:"customer_id" form:"customer_id"`
 	Tenor       int        `json:"tenor" form:"tenor"`
 	LimitAmount float32    `json:"limit_amount" form:"limit_amount"`
+	ValidFrom   time.Time  `json:"valid_from"`
+	ValidUntil  *time.Time `json:"valid_until,omitempty"`
+	Status      string     `json:"status"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
 }
 
+// CustomerLimitUtilization is the remaining limit of a tenor together with the
+// installment amounts of its active and pending (held) transactions.
+type CustomerLimitUtilization struct {
+	Tenor        int        `json:"tenor"`
+	LimitAmount  float32    `json:"limit_amount"`
+	Status       string     `json:"status"`
+	ValidUntil   *time.Time `json:"valid_until,omitempty"`
+	InUseAmount  float32    `json:"in_use_amount"`
+	OnHoldAmount float32    `json:"on_hold_amount"`
+}
+
+// IsExpired reports whether the limit is past its validity at the given time.
+func (u *CustomerLimit) IsExpired(at time.Time) bool {
+	return u.ValidUntil != nil && !at.Before(*u.ValidUntil)
+}
+
+// IsUsable reports whether the limit can be used for a checkout at the given time.
+func (u *CustomerLimit) IsUsable(at time.Time) bool {
+	return u.Status != STATUS_SUSPENDED && !u.IsExpired(at)
+}
+
+// Granted returns the limit granted for the tenor. Checkout debits the limit, so it is the
+// remaining limit plus what active transactions use.
+func (u *CustomerLimitUtilization) Granted() float32 {
+	return u.LimitAmount + u.InUseAmount
+}
+
+// IsUsable reports whether the limit of the utilization can be used at the given time.
+func (u *CustomerLimitUtilization) IsUsable(at time.Time) bool {
+	return u.Status != STATUS_SUSPENDED && (u.ValidUntil == nil || at.Before(*u.ValidUntil))
+}
+
 // Validate the fields of a customerLimit.
 func (u *CustomerLimit) Validate() error {
 	if u.CustomerID == 0 {
@@ -39,5 +82,9 @@ func (u *CustomerLimit) Validate() error {
 		return errors.New(constants.INVALID_INPUT)
 	}
 
+	if u.Status != "" && u.Status != STATUS_ACTIVE && u.Status != STATUS_SUSPENDED {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
 	return nil
 }
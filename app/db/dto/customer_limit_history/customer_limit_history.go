@@ -0,0 +1,64 @@
+package customer_limit_history
+
+import (
+	"errors"
+	"kredit-plus/app/constants"
+	"time"
+)
+
+const (
+	TABLE_NAME               = "customer_limit_history"
+	COLUMN_ID                = "id"
+	COLUMN_CUSTOMER_LIMIT_ID = "customer_limit_id"
+	COLUMN_CUSTOMER_ID       = "customer_id"
+	COLUMN_TENOR             = "tenor"
+	COLUMN_ACTION            = "action"
+	COLUMN_OLD_LIMIT_AMOUNT  = "old_limit_amount"
+	COLUMN_NEW_LIMIT_AMOUNT  = "new_limit_amount"
+	COLUMN_ACTOR             = "actor"
+	COLUMN_REASON            = "reason"
+	COLUMN_CORRELATION_ID    = "correlation_id"
+	COLUMN_CREATED_AT        = "created_at"
+)
+
+const (
+	ACTION_CREATE = "create"
+	ACTION_UPDATE = "update"
+	ACTION_DELETE = "delete"
+	ACTION_DEBIT  = "debit"
+)
+
+type CustomerLimitHistory struct {
+	ID              int       `json:"id"`
+	CustomerLimitID int       `json:"customer_limit_id" form:"customer_limit_id"`
+	CustomerID      int       `json:"customer_id" form:"customer_id"`
+	Tenor           int       `json:"tenor" form:"tenor"`
+	Action          string    `json:"action" form:"action"`
+	OldLimitAmount  *float32  `json:"old_limit_amount" form:"old_limit_amount"`
+	NewLimitAmount  *float32  `json:"new_limit_amount" form:"new_limit_amount"`
+	Actor           string    `json:"actor" form:"actor"`
+	Reason          string    `json:"reason" form:"reason"`
+	CorrelationID   string    `json:"correlation_id" form:"correlation_id"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Validate the fields of a customerLimitHistory.
+func (u *CustomerLimitHistory) Validate() error {
+	if u.CustomerLimitID == 0 {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.CustomerID == 0 {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.Action == "" {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.Actor == "" {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package customer_limit_review
+
+import (
+	"errors"
+	"kredit-plus/app/constants"
+	"time"
+
+	"github.com/jinzhu/gorm/dialects/postgres"
+)
+
+const (
+	TABLE_NAME             = "customer_limit_reviews"
+	COLUMN_ID              = "id"
+	COLUMN_CUSTOMER_ID     = "customer_id"
+	COLUMN_CREDIT_SCORE_ID = "credit_score_id"
+	COLUMN_OUTCOME         = "outcome"
+	COLUMN_GRADE           = "grade"
+	COLUMN_SCORE           = "score"
+	COLUMN_DETAILS         = "details"
+	COLUMN_CREATED_AT      = "created_at"
+)
+
+const (
+	OUTCOME_RENEWED   = "renewed"
+	OUTCOME_REDUCED   = "reduced"
+	OUTCOME_SUSPENDED = "suspended"
+)
+
+type CustomerLimitReview struct {
+	ID            int            `json:"id"`
+	CustomerID    int            `json:"customer_id" form:"customer_id"`
+	CreditScoreID *int           `json:"credit_score_id" form:"credit_score_id"`
+	Outcome       string         `json:"outcome" form:"outcome"`
+	Grade         string         `json:"grade" form:"grade"`
+	Score         float64        `json:"score" form:"score"`
+	Details       postgres.Jsonb `json:"details"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+// Validate the fields of a customerLimitReview.
+func (u *CustomerLimitReview) Validate() error {
+	if u.CustomerID == 0 {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.Outcome != OUTCOME_RENEWED && u.Outcome != OUTCOME_REDUCED && u.Outcome != OUTCOME_SUSPENDED {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	return nil
+}
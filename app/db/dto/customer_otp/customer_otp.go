@@ -0,0 +1,55 @@
+package customer_otp
+
+import (
+	"errors"
+	"kredit-plus/app/constants"
+	"time"
+)
+
+const (
+	TABLE_NAME         = "customer_otps"
+	COLUMN_ID          = "id"
+	COLUMN_CUSTOMER_ID = "customer_id"
+	COLUMN_PURPOSE     = "purpose"
+	COLUMN_TARGET      = "target"
+	COLUMN_CODE_HASH   = "code_hash"
+	COLUMN_ATTEMPTS    = "attempts"
+	COLUMN_EXPIRES_AT  = "expires_at"
+	COLUMN_USED_AT     = "used_at"
+	COLUMN_CREATED_AT  = "created_at"
+)
+
+const (
+	PURPOSE_PHONE_VERIFICATION = "phone_verification"
+)
+
+// CustomerOtp is a one time code sent to the target, e.g. a phone number.
+// Only a keyed hash of the code is stored.
+type CustomerOtp struct {
+	ID         int        `json:"id"`
+	CustomerID int        `json:"customer_id"`
+	Purpose    string     `json:"purpose"`
+	Target     string     `json:"target"`
+	CodeHash   string     `json:"-"`
+	Attempts   int        `json:"attempts"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	UsedAt     *time.Time `json:"used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Validate the fields of a customerOtp.
+func (u *CustomerOtp) Validate() error {
+	if u.CustomerID == 0 {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.Purpose == "" || u.Target == "" || u.CodeHash == "" {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.ExpiresAt.IsZero() {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	return nil
+}
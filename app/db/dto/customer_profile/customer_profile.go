@@ -3,7 +3,11 @@ package customer_profile
 import (
 	"errors"
 	"kredit-plus/app/constants"
+	"kredit-plus/app/service/nik"
+	"kredit-plus/app/service/util"
+	"strings"
 	"time"
+	"unicode"
 )
 
 const (
@@ -11,6 +15,7 @@ const (
 	COLUMN_ID             = "id"
 	COLUMN_CUSTOMER_ID    = "customer_id"
 	COLUMN_NIK            = "nik"
+	COLUMN_NIK_BIDX       = "nik_bidx"
 	COLUMN_FULL_NAME      = "full_name"
 	COLUMN_LEGAL_NAME     = "legal_name"
 	COLUMN_PLACE_OF_BIRTH = "place_of_birth"
@@ -18,8 +23,17 @@ const (
 	COLUMN_SALARY         = "salary"
 	COLUMN_KTP_IMAGE      = "ktp_image"
 	COLUMN_SELFIE_IMAGE   = "selfie_image"
+	COLUMN_SELFIE_HASH    = "selfie_hash"
+	COLUMN_VERSION        = "version"
 	COLUMN_CREATED_AT     = "created_at"
 	COLUMN_UPDATED_AT     = "updated_at"
+
+	// COLUMN_DATE_OF_BIRTH_ENCRYPTED holds the dates of birth cmd/reencrypt has not moved to
+	// the DATE column yet
+	COLUMN_DATE_OF_BIRTH_ENCRYPTED = "date_of_birth_encrypted"
+	// COLUMN_NAME_DATE_OF_BIRTH_BIDX is a blind index of the name and the date of birth, so
+	// profiles of the same person can be looked up without decrypting every profile
+	COLUMN_NAME_DATE_OF_BIRTH_BIDX = "name_date_of_birth_bidx"
 )
 
 type CustomerProfile struct {
@@ -29,10 +43,12 @@ type CustomerProfile struct {
 	FullName     string     `json:"full_name" form:"full_name"`
 	LegalName    string     `json:"legal_name" form:"legal_name"`
 	PlaceOfBirth string     `json:"place_of_birth" form:"place_of_birth"`
-	DateOfBirth  string     `json:"date_of_birth" form:"date_of_birth"`
+	DateOfBirth  util.Date  `json:"date_of_birth" form:"date_of_birth"`
 	Salary       float32    `json:"salary" form:"salary"`
 	KtpImage     string     `json:"ktp_image" form:"ktp_image"`
 	SelfieImage  string     `json:"selfie_image" form:"selfie_image"`
+	SelfieHash   string     `json:"-"`
+	Version      int        `json:"version"`
 	CreatedAt    time.Time  `json:"created_at"`
 	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
 }
@@ -43,5 +59,40 @@ func (u *CustomerProfile) Validate() error {
 		return errors.New(constants.INVALID_INPUT)
 	}
 
+	if u.NIK != "" {
+		return u.validateNIK()
+	}
+
+	return nil
+}
+
+// validateNIK checks the NIK and, when a date of birth is given, that both agree.
+func (u *CustomerProfile) validateNIK() error {
+	parsed, err := nik.Parse(u.NIK)
+	if err != nil {
+		return err
+	}
+
+	if u.DateOfBirth.IsZero() {
+		return nil
+	}
+
+	if !parsed.MatchesDateOfBirth(u.DateOfBirth.Time) {
+		return errors.New(constants.NIK_DATE_OF_BIRTH_MISMATCH)
+	}
+
 	return nil
 }
+
+// Name is the name of the profile as it appears on the KTP, lowercased and without
+// punctuation so that spelling variants of the same name compare equal.
+func (u *CustomerProfile) Name() string {
+	value := u.LegalName
+	if value == "" {
+		value = u.FullName
+	}
+
+	return strings.Join(strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}), " ")
+}
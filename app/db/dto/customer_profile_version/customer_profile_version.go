@@ -0,0 +1,66 @@
+package customer_profile_version
+
+import (
+	"errors"
+	"kredit-plus/app/constants"
+	"strings"
+	"time"
+)
+
+const (
+	TABLE_NAME            = "customer_profile_versions"
+	COLUMN_ID             = "id"
+	COLUMN_CUSTOMER_ID    = "customer_id"
+	COLUMN_VERSION        = "version"
+	COLUMN_ACTION         = "action"
+	COLUMN_CHANGED_FIELDS = "changed_fields"
+	COLUMN_SNAPSHOT       = "snapshot"
+	COLUMN_ACTOR          = "actor"
+	COLUMN_CORRELATION_ID = "correlation_id"
+	COLUMN_CREATED_AT     = "created_at"
+)
+
+const (
+	ACTION_CREATE = "create"
+	ACTION_UPDATE = "update"
+	ACTION_DELETE = "delete"
+)
+
+// CustomerProfileVersion is an immutable copy of a customer profile after a change.
+// Snapshot is the profile as JSON, empty once the profile was deleted or erased.
+type CustomerProfileVersion struct {
+	ID            int       `json:"id"`
+	CustomerID    int       `json:"customer_id" form:"customer_id"`
+	Version       int       `json:"version" form:"version"`
+	Action        string    `json:"action" form:"action"`
+	ChangedFields string    `json:"changed_fields" form:"changed_fields"`
+	Snapshot      string    `json:"-"`
+	Actor         string    `json:"actor" form:"actor"`
+	CorrelationID string    `json:"correlation_id" form:"correlation_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Fields returns the names of the changed profile fields.
+func (u *CustomerProfileVersion) Fields() []string {
+	if u.ChangedFields == "" {
+		return []string{}
+	}
+	return strings.Split(u.ChangedFields, ",")
+}
+
+// Validate the fields of a customerProfileVersion.
+func (u *CustomerProfileVersion) Validate() error {
+	if u.CustomerID == 0 || u.Version == 0 {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.Action != ACTION_CREATE && u.Action != ACTION_UPDATE && u.Action != ACTION_DELETE {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.Actor == "" {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	return nil
+}
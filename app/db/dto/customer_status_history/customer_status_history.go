@@ -0,0 +1,47 @@
+package customer_status_history
+
+import (
+	"errors"
+	"kredit-plus/app/constants"
+	"time"
+)
+
+const (
+	TABLE_NAME            = "customer_status_history"
+	COLUMN_ID             = "id"
+	COLUMN_CUSTOMER_ID    = "customer_id"
+	COLUMN_OLD_STATUS     = "old_status"
+	COLUMN_NEW_STATUS     = "new_status"
+	COLUMN_ACTOR          = "actor"
+	COLUMN_REASON         = "reason"
+	COLUMN_CORRELATION_ID = "correlation_id"
+	COLUMN_CREATED_AT     = "created_at"
+)
+
+type CustomerStatusHistory struct {
+	ID            int       `json:"id"`
+	CustomerID    int       `json:"customer_id" form:"customer_id"`
+	OldStatus     string    `json:"old_status" form:"old_status"`
+	NewStatus     string    `json:"new_status" form:"new_status"`
+	Actor         string    `json:"actor" form:"actor"`
+	Reason        string    `json:"reason" form:"reason"`
+	CorrelationID string    `json:"correlation_id" form:"correlation_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Validate the fields of a customerStatusHistory.
+func (u *CustomerStatusHistory) Validate() error {
+	if u.CustomerID == 0 {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.OldStatus == "" || u.NewStatus == "" {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.Actor == "" {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	return nil
+}
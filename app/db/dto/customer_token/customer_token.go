@@ -4,33 +4,50 @@ import (
 	"errors"
 	"kredit-plus/app/constants"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 const (
 	TABLE_NAME                      = "customer_tokens"
 	COLUMN_ID                       = "id"
 	COLUMN_CUSTOMER_ID              = "customer_id"
-	COLUMN_ACCESS_TOKEN             = "access_token"
-	COLUMN_REFRESH_TOKEN            = "refresh_token"
+	COLUMN_ACCESS_TOKEN_HASH        = "access_token_hash"
+	COLUMN_REFRESH_TOKEN_HASH       = "refresh_token_hash"
 	COLUMN_USER_AGENT               = "user_agent"
 	COLUMN_IP_ADDRESS               = "ip_address"
+	COLUMN_NAME                     = "name"
+	COLUMN_DEVICE                   = "device"
+	COLUMN_LAST_SEEN_AT             = "last_seen_at"
+	COLUMN_FAMILY_ID                = "family_id"
+	COLUMN_REFRESH_TOKEN_JTI        = "refresh_token_jti"
 	COLUMN_ACCESS_TOKEN_EXPIRED_AT  = "access_token_expired_at"
 	COLUMN_REFRESH_TOKEN_EXPIRED_AT = "refresh_token_expired_at"
 	COLUMN_CREATED_AT               = "created_at"
 	COLUMN_UPDATED_AT               = "updated_at"
 )
 
+// CustomerToken is a session, one per sign in. Only the SHA-256 hashes of its tokens
+// are stored, see verification.HashToken.
 type CustomerToken struct {
 	ID                    int        `json:"id"`
 	CustomerID            int        `json:"customer_id" form:"customer_id"`
-	AccessToken           string     `json:"access_token" form:"access_token"`
-	RefreshToken          string     `json:"refresh_token" form:"refresh_token"`
+	AccessTokenHash       string     `json:"-"`
+	RefreshTokenHash      string     `json:"-"`
+	FamilyID              uuid.UUID  `json:"-"`
+	RefreshTokenJTI       *uuid.UUID `json:"-"`
+	Name                  string     `json:"name" form:"name"`
+	Device                string     `json:"device" form:"device"`
 	UserAgent             string     `json:"user_agent" form:"user_agent"`
 	IPAddress             string     `json:"ip_address" form:"ip_address"`
 	AccessTokenExpiredAt  time.Time  `json:"access_token_expired_at" form:"access_token_expired_at"`
 	RefreshTokenExpiredAt time.Time  `json:"refresh_token_expired_at" form:"refresh_token_expired_at"`
+	LastSeenAt            *time.Time `json:"last_seen_at"`
 	CreatedAt             time.Time  `json:"created_at"`
 	UpdatedAt             *time.Time `json:"updated_at,omitempty"`
+
+	// Current marks the session of the request that listed it
+	Current bool `json:"current" gorm:"-"`
 }
 
 // Validate the fields of a customerToken.
@@ -39,11 +56,11 @@ func (u *CustomerToken) Validate() error {
 		return errors.New(constants.INVALID_INPUT)
 	}
 
-	if len(u.AccessToken) == 0 {
+	if len(u.AccessTokenHash) == 0 {
 		return errors.New(constants.INVALID_INPUT)
 	}
 
-	if len(u.RefreshToken) == 0 {
+	if len(u.RefreshTokenHash) == 0 {
 		return errors.New(constants.INVALID_INPUT)
 	}
 
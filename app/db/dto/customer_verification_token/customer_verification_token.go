@@ -0,0 +1,59 @@
+package customer_verification_token
+
+import (
+	"errors"
+	"kredit-plus/app/constants"
+	"time"
+)
+
+const (
+	TABLE_NAME         = "customer_verification_tokens"
+	COLUMN_ID          = "id"
+	COLUMN_CUSTOMER_ID = "customer_id"
+	COLUMN_PURPOSE     = "purpose"
+	COLUMN_TARGET      = "target"
+	COLUMN_TOKEN_HASH  = "token_hash"
+	COLUMN_EXPIRES_AT  = "expires_at"
+	COLUMN_USED_AT     = "used_at"
+	COLUMN_CREATED_AT  = "created_at"
+)
+
+const (
+	PURPOSE_EMAIL_VERIFICATION = "email_verification"
+	PURPOSE_PASSWORD_RESET     = "password_reset"
+)
+
+// CustomerVerificationToken is a single use token proving control of the target,
+// e.g. an email address. Only the SHA-256 hash of the token is stored.
+type CustomerVerificationToken struct {
+	ID         int        `json:"id"`
+	CustomerID int        `json:"customer_id"`
+	Purpose    string     `json:"purpose"`
+	Target     string     `json:"target"`
+	TokenHash  string     `json:"-"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	UsedAt     *time.Time `json:"used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsUsable reports whether the token has not been used and has not expired at the given time.
+func (u *CustomerVerificationToken) IsUsable(now time.Time) bool {
+	return u.UsedAt == nil && now.Before(u.ExpiresAt)
+}
+
+// Validate the fields of a customerVerificationToken.
+func (u *CustomerVerificationToken) Validate() error {
+	if u.CustomerID == 0 {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.Purpose == "" || u.Target == "" || u.TokenHash == "" {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.ExpiresAt.IsZero() {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package duplicate_match
+
+import (
+	"errors"
+	"kredit-plus/app/constants"
+	"time"
+)
+
+const (
+	TABLE_NAME                 = "duplicate_matches"
+	COLUMN_ID                  = "id"
+	COLUMN_CUSTOMER_ID         = "customer_id"
+	COLUMN_MATCHED_CUSTOMER_ID = "matched_customer_id"
+	COLUMN_REASON              = "reason"
+	COLUMN_SCORE               = "score"
+	COLUMN_STATUS              = "status"
+	COLUMN_REVIEWER            = "reviewer"
+	COLUMN_REVIEW_NOTES        = "review_notes"
+	COLUMN_REVIEWED_AT         = "reviewed_at"
+	COLUMN_CREATED_AT          = "created_at"
+	COLUMN_UPDATED_AT          = "updated_at"
+)
+
+const (
+	REASON_NIK                = "nik"
+	REASON_NAME_DATE_OF_BIRTH = "name_date_of_birth"
+	REASON_SELFIE             = "selfie"
+)
+
+const (
+	// STATUS_PENDING matches wait in the review queue and block new limits of both customers
+	STATUS_PENDING   = "pending"
+	STATUS_CONFIRMED = "confirmed"
+	STATUS_DISMISSED = "dismissed"
+)
+
+// DuplicateMatch is a pair of customers that may be the same person. CustomerID is
+// the older account of the two.
+type DuplicateMatch struct {
+	ID                int        `json:"id"`
+	CustomerID        int        `json:"customer_id" form:"customer_id"`
+	MatchedCustomerID int        `json:"matched_customer_id" form:"matched_customer_id"`
+	Reason            string     `json:"reason" form:"reason"`
+	Score             float64    `json:"score" form:"score"`
+	Status            string     `json:"status" form:"status"`
+	Reviewer          string     `json:"reviewer,omitempty" form:"reviewer"`
+	ReviewNotes       string     `json:"review_notes,omitempty" form:"review_notes"`
+	ReviewedAt        *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+}
+
+// Validate the fields of a duplicateMatch.
+func (u *DuplicateMatch) Validate() error {
+	if u.CustomerID == 0 || u.MatchedCustomerID == 0 || u.CustomerID >= u.MatchedCustomerID {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.Reason != REASON_NIK && u.Reason != REASON_NAME_DATE_OF_BIRTH && u.Reason != REASON_SELFIE {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.Status != STATUS_PENDING && u.Status != STATUS_CONFIRMED && u.Status != STATUS_DISMISSED {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package kyc_verification
+
+import (
+	"errors"
+	"kredit-plus/app/constants"
+	"time"
+)
+
+const (
+	TABLE_NAME                   = "kyc_verifications"
+	COLUMN_ID                    = "id"
+	COLUMN_CUSTOMER_ID           = "customer_id"
+	COLUMN_PROVIDER              = "provider"
+	COLUMN_STATUS                = "status"
+	COLUMN_KTP_IMAGE             = "ktp_image"
+	COLUMN_SELFIE_IMAGE          = "selfie_image"
+	COLUMN_OCR_NIK               = "ocr_nik"
+	COLUMN_OCR_NAME              = "ocr_name"
+	COLUMN_OCR_DATE_OF_BIRTH     = "ocr_date_of_birth"
+	COLUMN_NIK_MATCHED           = "nik_matched"
+	COLUMN_NAME_MATCHED          = "name_matched"
+	COLUMN_DATE_OF_BIRTH_MATCHED = "date_of_birth_matched"
+	COLUMN_FACE_MATCH_SCORE      = "face_match_score"
+	COLUMN_REASON                = "reason"
+	COLUMN_REVIEWER              = "reviewer"
+	COLUMN_REVIEW_NOTES          = "review_notes"
+	COLUMN_REVIEWED_AT           = "reviewed_at"
+	COLUMN_CREATED_AT            = "created_at"
+	COLUMN_UPDATED_AT            = "updated_at"
+)
+
+const (
+	// STATUS_PENDING verifications wait in the manual review queue
+	STATUS_PENDING  = "pending"
+	STATUS_VERIFIED = "verified"
+	STATUS_REJECTED = "rejected"
+)
+
+type KycVerification struct {
+	ID                 int        `json:"id"`
+	CustomerID         int        `json:"customer_id" form:"customer_id"`
+	Provider           string     `json:"provider" form:"provider"`
+	Status             string     `json:"status" form:"status"`
+	KtpImage           string     `json:"ktp_image" form:"ktp_image"`
+	SelfieImage        string     `json:"selfie_image" form:"selfie_image"`
+	OcrNIK             string     `json:"ocr_nik" form:"ocr_nik" gorm:"column:ocr_nik"`
+	OcrName            string     `json:"ocr_name" form:"ocr_name"`
+	OcrDateOfBirth     string     `json:"ocr_date_of_birth" form:"ocr_date_of_birth"`
+	NIKMatched         bool       `json:"nik_matched" form:"nik_matched" gorm:"column:nik_matched"`
+	NameMatched        bool       `json:"name_matched" form:"name_matched"`
+	DateOfBirthMatched bool       `json:"date_of_birth_matched" form:"date_of_birth_matched"`
+	FaceMatchScore     float64    `json:"face_match_score" form:"face_match_score"`
+	Reason             string     `json:"reason" form:"reason"`
+	Reviewer           string     `json:"reviewer,omitempty" form:"reviewer"`
+	ReviewNotes        string     `json:"review_notes,omitempty" form:"review_notes"`
+	ReviewedAt         *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          *time.Time `json:"updated_at,omitempty"`
+}
+
+// Validate the fields of a kycVerification.
+func (u *KycVerification) Validate() error {
+	if u.CustomerID == 0 {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.Provider == "" || u.KtpImage == "" || u.SelfieImage == "" {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.Status != STATUS_PENDING && u.Status != STATUS_VERIFIED && u.Status != STATUS_REJECTED {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package limit_increase_request
+
+import (
+	"errors"
+	"kredit-plus/app/constants"
+	"time"
+
+	"github.com/jinzhu/gorm/dialects/postgres"
+)
+
+const (
+	TABLE_NAME                  = "limit_increase_requests"
+	COLUMN_ID                   = "id"
+	COLUMN_CUSTOMER_ID          = "customer_id"
+	COLUMN_TENOR                = "tenor"
+	COLUMN_CURRENT_LIMIT_AMOUNT = "current_limit_amount"
+	COLUMN_REQUESTED_AMOUNT     = "requested_amount"
+	COLUMN_APPROVED_AMOUNT      = "approved_amount"
+	COLUMN_DOCUMENTS            = "documents"
+	COLUMN_REASON               = "reason"
+	COLUMN_STATUS               = "status"
+	COLUMN_REVIEWER             = "reviewer"
+	COLUMN_REVIEW_NOTES         = "review_notes"
+	COLUMN_REVIEWED_AT          = "reviewed_at"
+	COLUMN_CREATED_AT           = "created_at"
+	COLUMN_UPDATED_AT           = "updated_at"
+)
+
+const (
+	STATUS_SUBMITTED    = "submitted"
+	STATUS_UNDER_REVIEW = "under_review"
+	STATUS_APPROVED     = "approved"
+	STATUS_REJECTED     = "rejected"
+)
+
+var AvailableStatus = []string{STATUS_SUBMITTED, STATUS_UNDER_REVIEW, STATUS_APPROVED, STATUS_REJECTED}
+
+// transitions lists the statuses a request may move to from each status.
+// Approved and rejected requests are final.
+var transitions = map[string][]string{
+	STATUS_SUBMITTED:    {STATUS_UNDER_REVIEW, STATUS_APPROVED, STATUS_REJECTED},
+	STATUS_UNDER_REVIEW: {STATUS_APPROVED, STATUS_REJECTED},
+}
+
+type LimitIncreaseRequest struct {
+	ID                 int            `json:"id"`
+	CustomerID         int            `json:"customer_id" form:"customer_id"`
+	Tenor              int            `json:"tenor" form:"tenor"`
+	CurrentLimitAmount float32        `json:"current_limit_amount" form:"current_limit_amount"`
+	RequestedAmount    float32        `json:"requested_amount" form:"requested_amount"`
+	ApprovedAmount     *float32       `json:"approved_amount,omitempty" form:"approved_amount"`
+	Documents          postgres.Jsonb `json:"documents"`
+	Reason             string         `json:"reason" form:"reason"`
+	Status             string         `json:"status" form:"status"`
+	Reviewer           string         `json:"reviewer,omitempty" form:"reviewer"`
+	ReviewNotes        string         `json:"review_notes,omitempty" form:"review_notes"`
+	ReviewedAt         *time.Time     `json:"reviewed_at,omitempty"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          *time.Time     `json:"updated_at,omitempty"`
+}
+
+// IsValidStatus reports whether status is one of the known request statuses.
+func IsValidStatus(status string) bool {
+	for _, s := range AvailableStatus {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransition reports whether the request may move to the given status.
+func (u *LimitIncreaseRequest) CanTransition(status string) bool {
+	for _, s := range transitions[u.Status] {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusesBefore returns the statuses a request may move to the given status from.
+func StatusesBefore(status string) []string {
+	statuses := []string{}
+	for from, to := range transitions {
+		for _, s := range to {
+			if s == status {
+				statuses = append(statuses, from)
+			}
+		}
+	}
+	return statuses
+}
+
+// IsOpen reports whether the request still waits for a decision.
+func (u *LimitIncreaseRequest) IsOpen() bool {
+	return u.Status == STATUS_SUBMITTED || u.Status == STATUS_UNDER_REVIEW
+}
+
+// Validate the fields of a limitIncreaseRequest.
+func (u *LimitIncreaseRequest) Validate() error {
+	if u.CustomerID == 0 {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.Tenor == 0 {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if u.RequestedAmount <= 0 {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	if !IsValidStatus(u.Status) {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
+	return nil
+}
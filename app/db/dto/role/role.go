@@ -0,0 +1,21 @@
+package role
+
+import "time"
+
+const (
+	TABLE_NAME         = "roles"
+	COLUMN_ID          = "id"
+	COLUMN_NAME        = "name"
+	COLUMN_DESCRIPTION = "description"
+	COLUMN_CREATED_AT  = "created_at"
+	COLUMN_UPDATED_AT  = "updated_at"
+)
+
+// Role is a named set of permissions held by a customer account.
+type Role struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,19 @@
+package role_permission
+
+import "time"
+
+const (
+	TABLE_NAME        = "role_permissions"
+	COLUMN_ID         = "id"
+	COLUMN_ROLE       = "role"
+	COLUMN_PERMISSION = "permission"
+	COLUMN_CREATED_AT = "created_at"
+)
+
+// RolePermission grants a permission to every account with the role.
+type RolePermission struct {
+	ID         int       `json:"id"`
+	Role       string    `json:"role"`
+	Permission string    `json:"permission"`
+	CreatedAt  time.Time `json:"created_at"`
+}
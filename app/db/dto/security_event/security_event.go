@@ -0,0 +1,37 @@
+package security_event
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TABLE_NAME         = "security_events"
+	COLUMN_ID          = "id"
+	COLUMN_CUSTOMER_ID = "customer_id"
+	COLUMN_TYPE        = "type"
+	COLUMN_FAMILY_ID   = "family_id"
+	COLUMN_USER_AGENT  = "user_agent"
+	COLUMN_IP_ADDRESS  = "ip_address"
+	COLUMN_DETAILS     = "details"
+	COLUMN_CREATED_AT  = "created_at"
+)
+
+const (
+	// TYPE_REFRESH_TOKEN_REUSE is recorded when a rotated refresh token is used again.
+	// The session it belonged to has been revoked.
+	TYPE_REFRESH_TOKEN_REUSE = "refresh_token_reuse"
+)
+
+// SecurityEvent is something suspicious that happened to an account, kept for investigation.
+type SecurityEvent struct {
+	ID         int        `json:"id"`
+	CustomerID int        `json:"customer_id" form:"customer_id"`
+	Type       string     `json:"type" form:"type"`
+	FamilyID   *uuid.UUID `json:"family_id,omitempty" form:"family_id"`
+	UserAgent  string     `json:"user_agent" form:"user_agent"`
+	IPAddress  string     `json:"ip_address" form:"ip_address"`
+	Details    string     `json:"details,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -20,10 +20,22 @@ const (
 	COLUMN_INSTALLMENT_AMOUNT = "installment_amount"
 	COLUMN_INSTALLMENT_PERIOD = "installment_period"
 	COLUMN_INTEREST_AMOUNT    = "interest_amount"
+	COLUMN_SALES_CHANNEL      = "sales_channel"
+	COLUMN_STATUS             = "status"
 	COLUMN_CREATED_AT         = "created_at"
 	COLUMN_UPDATED_AT         = "updated_at"
 )
 
+const (
+	// STATUS_PENDING holds part of the limit while a checkout is in progress
+	STATUS_PENDING   = "pending"
+	STATUS_ACTIVE    = "active"
+	STATUS_SETTLED   = "settled"
+	STATUS_CANCELLED = "cancelled"
+)
+
+var AvailableStatus = []string{STATUS_PENDING, STATUS_ACTIVE, STATUS_SETTLED, STATUS_CANCELLED}
+
 type Transaction struct {
 	ID                int        `json:"-"`
 	UUID              uuid.UUID  `json:"uuid" form:"uuid"`
@@ -36,6 +48,7 @@ type Transaction struct {
 	InstallmentPeriod int        `json:"installment_period" form:"installment_period"`
 	InterestAmount    float32    `json:"interest_amount" form:"interest_amount"`
 	SalesChannel      string     `json:"sales_channel" form:"sales_channel"`
+	Status            string     `json:"status" form:"status"`
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
 }
@@ -62,5 +75,18 @@ func (u *Transaction) Validate() error {
 		return errors.New(constants.INVALID_INPUT)
 	}
 
+	if !IsValidStatus(u.Status) {
+		return errors.New(constants.INVALID_INPUT)
+	}
+
 	return nil
 }
+
+func IsValidStatus(status string) bool {
+	for _, s := range AvailableStatus {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE credit_scores (
+    id serial PRIMARY KEY,
+    customer_id integer NOT NULL REFERENCES customers(id),
+    scorecard varchar(50) NOT NULL,
+    score numeric(5, 2) NOT NULL,
+    grade varchar(5) NOT NULL,
+    explanation jsonb NOT NULL,
+    created_at timestamptz DEFAULT NOW()
+);
+
+CREATE INDEX idx_credit_scores_customer_id ON credit_scores (customer_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE credit_scores;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE customer_limit_history (
+    id serial PRIMARY KEY,
+    customer_limit_id integer NOT NULL,
+    customer_id integer NOT NULL REFERENCES customers(id),
+    tenor integer NOT NULL,
+    action varchar(20) NOT NULL,
+    old_limit_amount numeric(15, 2),
+    new_limit_amount numeric(15, 2),
+    actor varchar(255) NOT NULL,
+    reason text,
+    correlation_id varchar(64),
+    created_at timestamptz DEFAULT NOW()
+);
+
+CREATE INDEX idx_customer_limit_history_customer_limit_id ON customer_limit_history (customer_limit_id);
+CREATE INDEX idx_customer_limit_history_customer_id ON customer_limit_history (customer_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE customer_limit_history;
+-- +goose StatementEnd
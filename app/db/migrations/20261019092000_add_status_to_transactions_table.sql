@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS installment_period integer;
+ALTER TABLE transactions ADD COLUMN status varchar(20) NOT NULL DEFAULT 'active' CHECK (status IN ('pending', 'active', 'settled', 'cancelled'));
+
+-- Checkout holds the limit with a pending transaction before the asset exists
+ALTER TABLE transactions ALTER COLUMN asset_id DROP NOT NULL;
+
+CREATE INDEX idx_transactions_customer_id_status ON transactions (customer_id, status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_transactions_customer_id_status;
+
+-- Only checkout holds that never got an asset have none, they cannot exist without status
+DELETE FROM transactions WHERE asset_id IS NULL;
+ALTER TABLE transactions ALTER COLUMN asset_id SET NOT NULL;
+
+ALTER TABLE transactions DROP COLUMN status;
+-- +goose StatementEnd
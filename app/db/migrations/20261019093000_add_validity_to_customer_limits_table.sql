@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE customer_limits ADD COLUMN valid_from timestamptz DEFAULT NOW();
+ALTER TABLE customer_limits ADD COLUMN valid_until timestamptz;
+ALTER TABLE customer_limits ADD COLUMN status varchar(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended'));
+
+UPDATE customer_limits SET valid_from = created_at, valid_until = created_at + INTERVAL '12 months';
+
+CREATE INDEX idx_customer_limits_valid_until ON customer_limits (valid_until);
+
+CREATE TABLE customer_limit_reviews (
+    id serial PRIMARY KEY,
+    customer_id integer NOT NULL REFERENCES customers(id),
+    credit_score_id integer REFERENCES credit_scores(id),
+    outcome varchar(20) NOT NULL CHECK (outcome IN ('renewed', 'reduced', 'suspended')),
+    grade varchar(5),
+    score numeric(5, 2),
+    details jsonb,
+    created_at timestamptz DEFAULT NOW()
+);
+
+CREATE INDEX idx_customer_limit_reviews_customer_id ON customer_limit_reviews (customer_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE customer_limit_reviews;
+
+DROP INDEX idx_customer_limits_valid_until;
+
+ALTER TABLE customer_limits DROP COLUMN status;
+ALTER TABLE customer_limits DROP COLUMN valid_until;
+ALTER TABLE customer_limits DROP COLUMN valid_from;
+-- +goose StatementEnd
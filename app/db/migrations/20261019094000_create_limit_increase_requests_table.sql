@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE limit_increase_requests (
+    id serial PRIMARY KEY,
+    customer_id integer NOT NULL REFERENCES customers(id),
+    tenor integer NOT NULL,
+    current_limit_amount numeric(15, 2) NOT NULL DEFAULT 0,
+    requested_amount numeric(15, 2) NOT NULL,
+    approved_amount numeric(15, 2),
+    documents jsonb,
+    reason text,
+    status varchar(20) NOT NULL DEFAULT 'submitted' CHECK (status IN ('submitted', 'under_review', 'approved', 'rejected')),
+    reviewer varchar(255),
+    review_notes text,
+    reviewed_at timestamptz,
+    created_at timestamptz DEFAULT NOW(),
+    updated_at timestamptz
+);
+
+CREATE INDEX idx_limit_increase_requests_customer_id ON limit_increase_requests (customer_id);
+CREATE INDEX idx_limit_increase_requests_status ON limit_increase_requests (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE limit_increase_requests;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE customers ADD COLUMN kyc_status varchar(20) NOT NULL DEFAULT 'unverified' CHECK (kyc_status IN ('unverified', 'pending', 'verified', 'rejected'));
+
+CREATE TABLE kyc_verifications (
+    id serial PRIMARY KEY,
+    customer_id integer NOT NULL REFERENCES customers(id),
+    provider varchar(50) NOT NULL,
+    status varchar(20) NOT NULL CHECK (status IN ('pending', 'verified', 'rejected')),
+    ktp_image varchar(255) NOT NULL,
+    selfie_image varchar(255) NOT NULL,
+    ocr_nik varchar(16),
+    ocr_name varchar(255),
+    ocr_date_of_birth varchar(20),
+    nik_matched boolean NOT NULL DEFAULT false,
+    name_matched boolean NOT NULL DEFAULT false,
+    date_of_birth_matched boolean NOT NULL DEFAULT false,
+    face_match_score numeric(5, 4),
+    reason text,
+    reviewer varchar(255),
+    review_notes text,
+    reviewed_at timestamptz,
+    created_at timestamptz DEFAULT NOW(),
+    updated_at timestamptz
+);
+
+CREATE INDEX idx_kyc_verifications_customer_id ON kyc_verifications (customer_id);
+CREATE INDEX idx_kyc_verifications_status ON kyc_verifications (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE kyc_verifications;
+
+ALTER TABLE customers DROP COLUMN kyc_status;
+-- +goose StatementEnd
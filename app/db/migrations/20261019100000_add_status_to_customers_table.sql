@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE customers ADD COLUMN status varchar(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended', 'closed', 'blacklisted'));
+ALTER TABLE customers ADD COLUMN status_reason text;
+ALTER TABLE customers ADD COLUMN status_changed_at timestamptz;
+
+CREATE TABLE customer_status_history (
+    id serial PRIMARY KEY,
+    customer_id integer NOT NULL REFERENCES customers(id),
+    old_status varchar(20) NOT NULL,
+    new_status varchar(20) NOT NULL,
+    actor varchar(255) NOT NULL,
+    reason text,
+    correlation_id varchar(64),
+    created_at timestamptz DEFAULT NOW()
+);
+
+CREATE INDEX idx_customer_status_history_customer_id ON customer_status_history (customer_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE customer_status_history;
+
+ALTER TABLE customers DROP COLUMN status_changed_at;
+ALTER TABLE customers DROP COLUMN status_reason;
+ALTER TABLE customers DROP COLUMN status;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE customers ADD COLUMN email_verified_at timestamptz;
+
+CREATE TABLE customer_verification_tokens (
+    id serial PRIMARY KEY,
+    customer_id integer NOT NULL REFERENCES customers(id),
+    purpose varchar(30) NOT NULL,
+    target varchar(255) NOT NULL,
+    token_hash varchar(64) NOT NULL UNIQUE,
+    expires_at timestamptz NOT NULL,
+    used_at timestamptz,
+    created_at timestamptz DEFAULT NOW()
+);
+
+CREATE INDEX idx_customer_verification_tokens_customer_id ON customer_verification_tokens (customer_id, purpose, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE customer_verification_tokens;
+
+ALTER TABLE customers DROP COLUMN email_verified_at;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Phones are stored in E.164 form, e.g. +6281234567890
+ALTER TABLE customers DROP CONSTRAINT IF EXISTS customers_phone_check;
+
+UPDATE customers SET phone = '+62' || substr(phone, 2) WHERE phone LIKE '0%';
+UPDATE customers SET phone = '+' || phone WHERE phone LIKE '62%';
+
+-- Rows that could not be normalized are left as they are and only new values are checked
+ALTER TABLE customers ADD CONSTRAINT customers_phone_check CHECK (phone ~ '^\+62[0-9]{9,12}$') NOT VALID;
+
+ALTER TABLE customers ADD COLUMN phone_verified_at timestamptz;
+
+CREATE TABLE customer_otps (
+    id serial PRIMARY KEY,
+    customer_id integer NOT NULL REFERENCES customers(id),
+    purpose varchar(30) NOT NULL,
+    target varchar(255) NOT NULL,
+    code_hash varchar(64) NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    expires_at timestamptz NOT NULL,
+    used_at timestamptz,
+    created_at timestamptz DEFAULT NOW()
+);
+
+CREATE INDEX idx_customer_otps_customer_id ON customer_otps (customer_id, purpose, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE customer_otps;
+
+ALTER TABLE customers DROP COLUMN phone_verified_at;
+
+ALTER TABLE customers DROP CONSTRAINT IF EXISTS customers_phone_check;
+
+UPDATE customers SET phone = '0' || substr(phone, 4) WHERE phone LIKE '+62%';
+
+ALTER TABLE customers ADD CONSTRAINT customers_phone_check CHECK (phone ~* '^[0-9]+$') NOT VALID;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Erased customers keep their row for the financial records that reference it,
+-- with email and phone set to NULL
+ALTER TABLE customers ADD COLUMN erased_at timestamptz;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE customers DROP COLUMN erased_at;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Identity columns hold AES-GCM ciphertext ("enc:<key id>:<payload>") written by the
+-- application; existing plaintext values are encrypted by cmd/reencrypt.
+-- nik_bidx is an HMAC of the NIK so it can still be looked up by exact match.
+ALTER TABLE customer_profiles
+    ALTER COLUMN nik TYPE text,
+    ALTER COLUMN legal_name TYPE text,
+    ALTER COLUMN date_of_birth TYPE text USING to_char(date_of_birth, 'YYYY-MM-DD'),
+    ALTER COLUMN salary TYPE text USING salary::text,
+    ADD COLUMN nik_bidx varchar(64);
+
+CREATE INDEX idx_customer_profiles_nik_bidx ON customer_profiles (nik_bidx);
+
+ALTER TABLE kyc_verifications
+    ALTER COLUMN ktp_image TYPE text,
+    ALTER COLUMN selfie_image TYPE text,
+    ALTER COLUMN ocr_nik TYPE text,
+    ALTER COLUMN ocr_name TYPE text,
+    ALTER COLUMN ocr_date_of_birth TYPE text;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Only valid once every value has been decrypted back to plaintext
+DROP INDEX idx_customer_profiles_nik_bidx;
+
+ALTER TABLE customer_profiles
+    DROP COLUMN nik_bidx,
+    ALTER COLUMN nik TYPE varchar(20),
+    ALTER COLUMN legal_name TYPE varchar(255),
+    ALTER COLUMN date_of_birth TYPE date USING NULLIF(date_of_birth, '')::date,
+    ALTER COLUMN salary TYPE numeric(15, 2) USING NULLIF(salary, '')::numeric;
+
+ALTER TABLE kyc_verifications
+    ALTER COLUMN ktp_image TYPE varchar(255),
+    ALTER COLUMN selfie_image TYPE varchar(255),
+    ALTER COLUMN ocr_nik TYPE varchar(16),
+    ALTER COLUMN ocr_name TYPE varchar(255),
+    ALTER COLUMN ocr_date_of_birth TYPE varchar(20);
+-- +goose StatementEnd
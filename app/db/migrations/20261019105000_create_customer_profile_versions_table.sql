@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every change of a customer profile is kept as an immutable version. The snapshot
+-- is the encrypted JSON of the profile after the change; profiles created before
+-- versioning stay at version 0 until their next change.
+ALTER TABLE customer_profiles ADD COLUMN version integer NOT NULL DEFAULT 0;
+
+CREATE TABLE customer_profile_versions (
+    id serial PRIMARY KEY,
+    customer_id integer NOT NULL REFERENCES customers(id),
+    version integer NOT NULL,
+    action varchar(20) NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    changed_fields text NOT NULL DEFAULT '',
+    snapshot text,
+    actor varchar(255) NOT NULL,
+    correlation_id varchar(255),
+    created_at timestamptz DEFAULT NOW(),
+    UNIQUE (customer_id, version)
+);
+
+-- Credit decisions record the profile version they were made on
+ALTER TABLE credit_scores ADD COLUMN profile_version integer;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE credit_scores DROP COLUMN profile_version;
+
+DROP TABLE customer_profile_versions;
+
+ALTER TABLE customer_profiles DROP COLUMN version;
+-- +goose StatementEnd
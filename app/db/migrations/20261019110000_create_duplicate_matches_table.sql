@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Perceptual hash of the selfie, compared by Hamming distance to find reused selfies
+ALTER TABLE customer_profiles ADD COLUMN selfie_hash varchar(16);
+
+-- Possible duplicate identities wait here for review. customer_id is the older of the
+-- two accounts; a confirmed match keeps blocking new limits of matched_customer_id.
+CREATE TABLE duplicate_matches (
+    id serial PRIMARY KEY,
+    customer_id integer NOT NULL REFERENCES customers(id),
+    matched_customer_id integer NOT NULL REFERENCES customers(id),
+    reason varchar(30) NOT NULL CHECK (reason IN ('nik', 'name_date_of_birth', 'selfie')),
+    score numeric(5, 4) NOT NULL DEFAULT 1,
+    status varchar(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'confirmed', 'dismissed')),
+    reviewer varchar(255),
+    review_notes text,
+    reviewed_at timestamptz,
+    created_at timestamptz DEFAULT NOW(),
+    updated_at timestamptz DEFAULT NOW(),
+    CHECK (customer_id < matched_customer_id),
+    UNIQUE (customer_id, matched_customer_id, reason)
+);
+
+CREATE INDEX idx_duplicate_matches_customer_id ON duplicate_matches (customer_id);
+CREATE INDEX idx_duplicate_matches_matched_customer_id ON duplicate_matches (matched_customer_id);
+CREATE INDEX idx_duplicate_matches_status ON duplicate_matches (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE duplicate_matches;
+
+ALTER TABLE customer_profiles DROP COLUMN selfie_hash;
+-- +goose StatementEnd
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A customer has at most one address of each type, the one on the KTP and where they live
+CREATE TABLE customer_addresses (
+    id serial PRIMARY KEY,
+    customer_id integer NOT NULL REFERENCES customers(id),
+    type varchar(10) NOT NULL CHECK (type IN ('ktp', 'domicile')),
+    street varchar(255) NOT NULL,
+    province varchar(100) NOT NULL,
+    city varchar(100) NOT NULL,
+    district varchar(100) NOT NULL,
+    postal_code varchar(5) NOT NULL,
+    created_at timestamptz DEFAULT NOW(),
+    updated_at timestamptz DEFAULT NOW(),
+    UNIQUE (customer_id, type)
+);
+
+CREATE TABLE customer_employments (
+    id serial PRIMARY KEY,
+    customer_id integer UNIQUE NOT NULL REFERENCES customers(id),
+    employer_name varchar(255) NOT NULL,
+    position varchar(255) NOT NULL,
+    start_date date NOT NULL,
+    income_proof text,
+    created_at timestamptz DEFAULT NOW(),
+    updated_at timestamptz DEFAULT NOW()
+);
+
+CREATE TABLE customer_emergency_contacts (
+    id serial PRIMARY KEY,
+    customer_id integer NOT NULL REFERENCES customers(id),
+    name varchar(255) NOT NULL,
+    relationship varchar(20) NOT NULL CHECK (relationship IN ('parent', 'spouse', 'sibling', 'child', 'relative', 'friend', 'colleague')),
+    phone varchar(20) NOT NULL,
+    created_at timestamptz DEFAULT NOW(),
+    updated_at timestamptz DEFAULT NOW()
+);
+
+CREATE INDEX idx_customer_emergency_contacts_customer_id ON customer_emergency_contacts (customer_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE customer_emergency_contacts;
+DROP TABLE customer_employments;
+DROP TABLE customer_addresses;
+-- +goose StatementEnd
@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE roles (
+    id serial PRIMARY KEY,
+    name varchar(50) NOT NULL UNIQUE,
+    description text,
+    created_at timestamptz DEFAULT NOW(),
+    updated_at timestamptz DEFAULT NOW()
+);
+
+CREATE TABLE permissions (
+    id serial PRIMARY KEY,
+    name varchar(100) NOT NULL UNIQUE,
+    description text,
+    created_at timestamptz DEFAULT NOW()
+);
+
+CREATE TABLE role_permissions (
+    id serial PRIMARY KEY,
+    role varchar(50) NOT NULL REFERENCES roles(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    permission varchar(100) NOT NULL REFERENCES permissions(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    created_at timestamptz DEFAULT NOW(),
+    UNIQUE (role, permission)
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('user', 'Customer using the app, limited to their own resources'),
+    ('admin', 'Back office staff');
+
+INSERT INTO permissions (name, description) VALUES
+    ('customers:read', 'List and view any customer'),
+    ('customers:write', 'Create and change any customer'),
+    ('customers:delete', 'Delete any customer'),
+    ('customers:status', 'Suspend, close, reopen and blacklist accounts'),
+    ('customers:erase', 'Erase the personal data of a customer'),
+    ('customers:role', 'Assign roles to customers'),
+    ('profiles:read', 'View the profile history of any customer'),
+    ('limit_increases:review', 'Approve and reject limit increase requests'),
+    ('kyc:review', 'Approve and reject KYC verifications'),
+    ('duplicates:review', 'Confirm and dismiss duplicate identity matches');
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'admin', name FROM permissions;
+
+-- Every existing account is a customer; admins are promoted afterwards
+ALTER TABLE customers ADD COLUMN role varchar(50) NOT NULL DEFAULT 'user' REFERENCES roles(name) ON UPDATE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE customers DROP COLUMN role;
+
+DROP TABLE role_permissions;
+DROP TABLE permissions;
+DROP TABLE roles;
+-- +goose StatementEnd
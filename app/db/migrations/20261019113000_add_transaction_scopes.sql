@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO roles (name, description) VALUES
+    ('partner', 'Sales channel partner, limited to the transactions sold through its channel');
+
+INSERT INTO permissions (name, description) VALUES
+    ('transactions:read_all', 'List and view the transactions of every customer'),
+    ('transactions:write_all', 'Create, change and delete the transactions of every customer'),
+    ('transactions:read_channel', 'List and view the transactions sold through the own sales channel');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'transactions:read_all'),
+    ('admin', 'transactions:write_all'),
+    ('partner', 'transactions:read_channel');
+
+-- Sales channel a partner account acts for, empty for everyone else
+ALTER TABLE customers ADD COLUMN sales_channel varchar(255);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE customers DROP COLUMN sales_channel;
+
+UPDATE customers SET role = 'user' WHERE role = 'partner';
+
+DELETE FROM permissions WHERE name IN ('transactions:read_all', 'transactions:write_all', 'transactions:read_channel');
+DELETE FROM roles WHERE name = 'partner';
+-- +goose StatementEnd
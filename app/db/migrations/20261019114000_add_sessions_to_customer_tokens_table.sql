@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every sign in is a session of its own, so a customer can have several tokens
+ALTER TABLE customer_tokens DROP CONSTRAINT IF EXISTS customer_tokens_customer_id_key;
+
+ALTER TABLE customer_tokens ADD COLUMN name varchar(100);
+ALTER TABLE customer_tokens ADD COLUMN device varchar(255);
+ALTER TABLE customer_tokens ADD COLUMN last_seen_at timestamptz;
+
+UPDATE customer_tokens SET last_seen_at = COALESCE(updated_at, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Keep the most recent session of every customer
+DELETE FROM customer_tokens t
+USING customer_tokens newer
+WHERE t.customer_id = newer.customer_id
+  AND (t.created_at, t.id) < (newer.created_at, newer.id);
+
+ALTER TABLE customer_tokens DROP COLUMN last_seen_at;
+ALTER TABLE customer_tokens DROP COLUMN device;
+ALTER TABLE customer_tokens DROP COLUMN name;
+
+ALTER TABLE customer_tokens ADD CONSTRAINT customer_tokens_customer_id_key UNIQUE (customer_id);
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A session is one refresh token family. Only the refresh token with refresh_token_jti
+-- may be used; any older token of the family showing up again means it was stolen.
+ALTER TABLE customer_tokens ADD COLUMN family_id uuid;
+ALTER TABLE customer_tokens ADD COLUMN refresh_token_jti uuid;
+
+-- gen_random_uuid() is only built in from PostgreSQL 13, older servers need pgcrypto
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+UPDATE customer_tokens SET family_id = gen_random_uuid();
+
+ALTER TABLE customer_tokens ALTER COLUMN family_id SET NOT NULL;
+CREATE UNIQUE INDEX idx_customer_tokens_family_id ON customer_tokens (family_id);
+
+CREATE TABLE security_events (
+    id serial PRIMARY KEY,
+    customer_id integer NOT NULL REFERENCES customers(id),
+    type varchar(50) NOT NULL,
+    family_id uuid,
+    user_agent varchar(255),
+    ip_address varchar(45),
+    details text,
+    created_at timestamptz DEFAULT NOW()
+);
+
+CREATE INDEX idx_security_events_customer_id ON security_events (customer_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE security_events;
+
+DROP INDEX idx_customer_tokens_family_id;
+ALTER TABLE customer_tokens DROP COLUMN refresh_token_jti;
+ALTER TABLE customer_tokens DROP COLUMN family_id;
+-- +goose StatementEnd
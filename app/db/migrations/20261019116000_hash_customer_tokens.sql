@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tokens are stored as the hex encoded SHA-256 hash of the JWT, the way verification
+-- tokens are, so a copy of the table does not hand out live sessions
+ALTER TABLE customer_tokens RENAME COLUMN access_token TO access_token_hash;
+ALTER TABLE customer_tokens RENAME COLUMN refresh_token TO refresh_token_hash;
+
+UPDATE customer_tokens SET
+    access_token_hash = encode(sha256(convert_to(access_token_hash, 'UTF8')), 'hex'),
+    refresh_token_hash = encode(sha256(convert_to(refresh_token_hash, 'UTF8')), 'hex');
+
+ALTER TABLE customer_tokens ALTER COLUMN access_token_hash TYPE varchar(64);
+ALTER TABLE customer_tokens ALTER COLUMN refresh_token_hash TYPE varchar(64);
+
+CREATE INDEX idx_customer_tokens_access_token_hash ON customer_tokens (access_token_hash);
+CREATE INDEX idx_customer_tokens_refresh_token_hash ON customer_tokens (refresh_token_hash);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- The tokens cannot be recovered from their hashes, every session is signed out
+DELETE FROM customer_tokens;
+
+DROP INDEX idx_customer_tokens_refresh_token_hash;
+DROP INDEX idx_customer_tokens_access_token_hash;
+
+ALTER TABLE customer_tokens ALTER COLUMN access_token_hash TYPE text;
+ALTER TABLE customer_tokens ALTER COLUMN refresh_token_hash TYPE text;
+
+ALTER TABLE customer_tokens RENAME COLUMN refresh_token_hash TO refresh_token;
+ALTER TABLE customer_tokens RENAME COLUMN access_token_hash TO access_token;
+-- +goose StatementEnd
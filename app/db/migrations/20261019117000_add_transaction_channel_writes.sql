@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Customers create transactions through checkout only, changes are made by admins and by
+-- partners for the transactions sold through their channel
+INSERT INTO permissions (name, description) VALUES
+    ('transactions:write_channel', 'Create, change and delete the transactions sold through the own sales channel');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('partner', 'transactions:write_channel');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'transactions:write_channel';
+-- +goose StatementEnd
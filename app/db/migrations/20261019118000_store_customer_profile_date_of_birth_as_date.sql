@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The date of birth is stored as a plain DATE so it can be compared and indexed. Encrypted
+-- values cannot be converted here, they are kept in date_of_birth_encrypted, read from
+-- there until then and moved over by cmd/reencrypt.
+ALTER TABLE customer_profiles RENAME COLUMN date_of_birth TO date_of_birth_encrypted;
+
+ALTER TABLE customer_profiles ADD COLUMN date_of_birth date;
+
+UPDATE customer_profiles
+    SET date_of_birth = date_of_birth_encrypted::date, date_of_birth_encrypted = NULL
+    WHERE date_of_birth_encrypted ~ '^\d{4}-\d{2}-\d{2}$';
+
+UPDATE customer_profiles SET date_of_birth_encrypted = NULL WHERE date_of_birth_encrypted = '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Dates are written back in plaintext, cmd/reencrypt encrypts them again
+UPDATE customer_profiles
+    SET date_of_birth_encrypted = to_char(date_of_birth, 'YYYY-MM-DD')
+    WHERE date_of_birth IS NOT NULL;
+
+ALTER TABLE customer_profiles DROP COLUMN date_of_birth;
+
+ALTER TABLE customer_profiles RENAME COLUMN date_of_birth_encrypted TO date_of_birth;
+-- +goose StatementEnd
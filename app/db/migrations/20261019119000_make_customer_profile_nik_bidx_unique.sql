@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A NIK belongs to one profile. The NIK is looked up before a profile is written, the
+-- unique index keeps concurrent writes from both passing that check. Profiles without a
+-- NIK have an empty blind index and are left out. Duplicates already stored have to be
+-- resolved before this migration runs.
+DROP INDEX idx_customer_profiles_nik_bidx;
+
+CREATE UNIQUE INDEX idx_customer_profiles_nik_bidx ON customer_profiles (nik_bidx) WHERE nik_bidx <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_customer_profiles_nik_bidx;
+
+CREATE INDEX idx_customer_profiles_nik_bidx ON customer_profiles (nik_bidx);
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A changed profile is compared with the profiles sharing its NIK, its selfie hash or its
+-- name and date of birth, looked up through these indexes. name_date_of_birth_bidx is an
+-- HMAC like nik_bidx; it is filled for existing profiles by cmd/reencrypt.
+ALTER TABLE customer_profiles ADD COLUMN name_date_of_birth_bidx varchar(64);
+
+CREATE INDEX idx_customer_profiles_name_date_of_birth_bidx ON customer_profiles (name_date_of_birth_bidx);
+
+CREATE INDEX idx_customer_profiles_selfie_hash ON customer_profiles (selfie_hash);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_customer_profiles_selfie_hash;
+
+DROP INDEX idx_customer_profiles_name_date_of_birth_bidx;
+
+ALTER TABLE customer_profiles DROP COLUMN name_date_of_birth_bidx;
+-- +goose StatementEnd
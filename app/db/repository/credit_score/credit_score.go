@@ -0,0 +1,102 @@
+package credit_score
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	creditScoreDBModels "kredit-plus/app/db/dto/credit_score"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/util"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with credit score data.
+type ICreditScoreRepository interface {
+	Create(ctx context.Context, creditScore *creditScoreDBModels.CreditScore) error
+	Get(ctx context.Context, filter map[string]interface{}) (creditScoreDBModels.CreditScore, error)
+	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]creditScoreDBModels.CreditScore, response.Pagination, error)
+}
+
+type CreditScoreRepository struct {
+	DBService *db.DBService
+}
+
+// Constructor for creating a new CreditScoreRepository.
+func NewCreditScoreRepository(dbService *db.DBService) ICreditScoreRepository {
+	return &CreditScoreRepository{
+		DBService: dbService,
+	}
+}
+
+const tableName = creditScoreDBModels.TABLE_NAME
+
+// Create a new creditScore record.
+func (u *CreditScoreRepository) Create(ctx context.Context, creditScore *creditScoreDBModels.CreditScore) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(creditScore).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Retrieve a creditScore based on filter criteria.
+func (u *CreditScoreRepository) Get(ctx context.Context, filter map[string]interface{}) (creditScoreDBModels.CreditScore, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var creditScore creditScoreDBModels.CreditScore
+
+	if err := tx.Where(filter).First(&creditScore).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return creditScore, nil
+		}
+		return creditScore, err
+	}
+
+	return creditScore, nil
+}
+
+// List creditScores based on filtering and pagination criteria.
+func (u *CreditScoreRepository) List(ctx context.Context, paginationRequest request.Pagination, filter map[string]interface{}) (record []creditScoreDBModels.CreditScore, paginationResponse response.Pagination, err error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	tx, err = util.ApplyFilterCondition(tx, filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
+	if err := tx.Count(&paginationResponse.TotalCount).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return nil, paginationResponse, err
+	}
+
+	if !paginationRequest.GetAllData {
+		offset := (*paginationRequest.Page - 1) * *paginationRequest.Limit
+		tx = tx.Limit(*paginationRequest.Limit).Offset(offset)
+		paginationResponse.Page = *paginationRequest.Page
+		paginationResponse.PerPage = *paginationRequest.Limit
+		paginationResponse.TotalPages = (paginationResponse.TotalCount + *paginationRequest.Limit - 1) / *paginationRequest.Limit
+	}
+
+	tx = tx.Order(fmt.Sprintf("%s %s", paginationRequest.Sort, paginationRequest.Order))
+
+	if err := tx.Find(&record).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return record, paginationResponse, err
+	}
+
+	return record, paginationResponse, nil
+}
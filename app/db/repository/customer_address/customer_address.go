@@ -0,0 +1,153 @@
+package customer_address
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	customerAddressDBModels "kredit-plus/app/db/dto/customer_address"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/util"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with customer address data.
+type ICustomerAddressRepository interface {
+	Create(ctx context.Context, customerAddress *customerAddressDBModels.CustomerAddress) error
+	Get(ctx context.Context, filter map[string]interface{}) (customerAddressDBModels.CustomerAddress, error)
+	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]customerAddressDBModels.CustomerAddress, response.Pagination, error)
+	Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error
+	Delete(ctx context.Context, filter map[string]interface{}) error
+}
+
+type CustomerAddressRepository struct {
+	DBService *db.DBService
+}
+
+// Constructor for creating a new CustomerAddressRepository.
+func NewCustomerAddressRepository(dbService *db.DBService) ICustomerAddressRepository {
+	return &CustomerAddressRepository{
+		DBService: dbService,
+	}
+}
+
+const tableName = customerAddressDBModels.TABLE_NAME
+
+// Create a new customerAddress record.
+func (u *CustomerAddressRepository) Create(ctx context.Context, customerAddress *customerAddressDBModels.CustomerAddress) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(customerAddress).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Retrieve a customerAddress based on filter criteria.
+func (u *CustomerAddressRepository) Get(ctx context.Context, filter map[string]interface{}) (customerAddressDBModels.CustomerAddress, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var customerAddress customerAddressDBModels.CustomerAddress
+
+	if err := tx.Where(filter).First(&customerAddress).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customerAddress, nil
+		}
+		return customerAddress, err
+	}
+
+	return customerAddress, nil
+}
+
+// List customerAddresses based on filtering and pagination criteria.
+func (u *CustomerAddressRepository) List(ctx context.Context, paginationRequest request.Pagination, filter map[string]interface{}) (record []customerAddressDBModels.CustomerAddress, paginationResponse response.Pagination, err error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	tx, err = util.ApplyFilterCondition(tx, filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
+	if err := tx.Count(&paginationResponse.TotalCount).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return nil, paginationResponse, err
+	}
+
+	if !paginationRequest.GetAllData {
+		offset := (*paginationRequest.Page - 1) * *paginationRequest.Limit
+		tx = tx.Limit(*paginationRequest.Limit).Offset(offset)
+		paginationResponse.Page = *paginationRequest.Page
+		paginationResponse.PerPage = *paginationRequest.Limit
+		paginationResponse.TotalPages = (paginationResponse.TotalCount + *paginationRequest.Limit - 1) / *paginationRequest.Limit
+	}
+
+	tx = tx.Order(fmt.Sprintf("%s %s", paginationRequest.Sort, paginationRequest.Order))
+
+	if err := tx.Find(&record).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return record, paginationResponse, err
+	}
+
+	return record, paginationResponse, nil
+}
+
+// Update customerAddress records based on filter criteria and a patch.
+func (u *CustomerAddressRepository) Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where(filter).Updates(patch).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var customerAddress customerAddressDBModels.CustomerAddress
+
+	if err := tx.Where(filter).First(&customerAddress).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete customerAddress records based on filter criteria.
+func (u *CustomerAddressRepository) Delete(ctx context.Context, filter map[string]interface{}) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where(filter).Delete(&customerAddressDBModels.CustomerAddress{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
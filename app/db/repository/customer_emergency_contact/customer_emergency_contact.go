@@ -0,0 +1,153 @@
+package customer_emergency_contact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	customerEmergencyContactDBModels "kredit-plus/app/db/dto/customer_emergency_contact"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/util"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with customer emergency contact data.
+type ICustomerEmergencyContactRepository interface {
+	Create(ctx context.Context, customerEmergencyContact *customerEmergencyContactDBModels.CustomerEmergencyContact) error
+	Get(ctx context.Context, filter map[string]interface{}) (customerEmergencyContactDBModels.CustomerEmergencyContact, error)
+	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]customerEmergencyContactDBModels.CustomerEmergencyContact, response.Pagination, error)
+	Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error
+	Delete(ctx context.Context, filter map[string]interface{}) error
+}
+
+type CustomerEmergencyContactRepository struct {
+	DBService *db.DBService
+}
+
+// Constructor for creating a new CustomerEmergencyContactRepository.
+func NewCustomerEmergencyContactRepository(dbService *db.DBService) ICustomerEmergencyContactRepository {
+	return &CustomerEmergencyContactRepository{
+		DBService: dbService,
+	}
+}
+
+const tableName = customerEmergencyContactDBModels.TABLE_NAME
+
+// Create a new customerEmergencyContact record.
+func (u *CustomerEmergencyContactRepository) Create(ctx context.Context, customerEmergencyContact *customerEmergencyContactDBModels.CustomerEmergencyContact) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(customerEmergencyContact).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Retrieve a customerEmergencyContact based on filter criteria.
+func (u *CustomerEmergencyContactRepository) Get(ctx context.Context, filter map[string]interface{}) (customerEmergencyContactDBModels.CustomerEmergencyContact, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var customerEmergencyContact customerEmergencyContactDBModels.CustomerEmergencyContact
+
+	if err := tx.Where(filter).First(&customerEmergencyContact).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customerEmergencyContact, nil
+		}
+		return customerEmergencyContact, err
+	}
+
+	return customerEmergencyContact, nil
+}
+
+// List customerEmergencyContacts based on filtering and pagination criteria.
+func (u *CustomerEmergencyContactRepository) List(ctx context.Context, paginationRequest request.Pagination, filter map[string]interface{}) (record []customerEmergencyContactDBModels.CustomerEmergencyContact, paginationResponse response.Pagination, err error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	tx, err = util.ApplyFilterCondition(tx, filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
+	if err := tx.Count(&paginationResponse.TotalCount).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return nil, paginationResponse, err
+	}
+
+	if !paginationRequest.GetAllData {
+		offset := (*paginationRequest.Page - 1) * *paginationRequest.Limit
+		tx = tx.Limit(*paginationRequest.Limit).Offset(offset)
+		paginationResponse.Page = *paginationRequest.Page
+		paginationResponse.PerPage = *paginationRequest.Limit
+		paginationResponse.TotalPages = (paginationResponse.TotalCount + *paginationRequest.Limit - 1) / *paginationRequest.Limit
+	}
+
+	tx = tx.Order(fmt.Sprintf("%s %s", paginationRequest.Sort, paginationRequest.Order))
+
+	if err := tx.Find(&record).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return record, paginationResponse, err
+	}
+
+	return record, paginationResponse, nil
+}
+
+// Update customerEmergencyContact records based on filter criteria and a patch.
+func (u *CustomerEmergencyContactRepository) Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where(filter).Updates(patch).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var customerEmergencyContact customerEmergencyContactDBModels.CustomerEmergencyContact
+
+	if err := tx.Where(filter).First(&customerEmergencyContact).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete customerEmergencyContact records based on filter criteria.
+func (u *CustomerEmergencyContactRepository) Delete(ctx context.Context, filter map[string]interface{}) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where(filter).Delete(&customerEmergencyContactDBModels.CustomerEmergencyContact{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
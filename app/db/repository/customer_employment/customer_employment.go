@@ -0,0 +1,153 @@
+package customer_employment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	customerEmploymentDBModels "kredit-plus/app/db/dto/customer_employment"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/util"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with customer employment data.
+type ICustomerEmploymentRepository interface {
+	Create(ctx context.Context, customerEmployment *customerEmploymentDBModels.CustomerEmployment) error
+	Get(ctx context.Context, filter map[string]interface{}) (customerEmploymentDBModels.CustomerEmployment, error)
+	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]customerEmploymentDBModels.CustomerEmployment, response.Pagination, error)
+	Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error
+	Delete(ctx context.Context, filter map[string]interface{}) error
+}
+
+type CustomerEmploymentRepository struct {
+	DBService *db.DBService
+}
+
+// Constructor for creating a new CustomerEmploymentRepository.
+func NewCustomerEmploymentRepository(dbService *db.DBService) ICustomerEmploymentRepository {
+	return &CustomerEmploymentRepository{
+		DBService: dbService,
+	}
+}
+
+const tableName = customerEmploymentDBModels.TABLE_NAME
+
+// Create a new customerEmployment record.
+func (u *CustomerEmploymentRepository) Create(ctx context.Context, customerEmployment *customerEmploymentDBModels.CustomerEmployment) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(customerEmployment).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Retrieve a customerEmployment based on filter criteria.
+func (u *CustomerEmploymentRepository) Get(ctx context.Context, filter map[string]interface{}) (customerEmploymentDBModels.CustomerEmployment, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var customerEmployment customerEmploymentDBModels.CustomerEmployment
+
+	if err := tx.Where(filter).First(&customerEmployment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customerEmployment, nil
+		}
+		return customerEmployment, err
+	}
+
+	return customerEmployment, nil
+}
+
+// List customerEmployments based on filtering and pagination criteria.
+func (u *CustomerEmploymentRepository) List(ctx context.Context, paginationRequest request.Pagination, filter map[string]interface{}) (record []customerEmploymentDBModels.CustomerEmployment, paginationResponse response.Pagination, err error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	tx, err = util.ApplyFilterCondition(tx, filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
+	if err := tx.Count(&paginationResponse.TotalCount).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return nil, paginationResponse, err
+	}
+
+	if !paginationRequest.GetAllData {
+		offset := (*paginationRequest.Page - 1) * *paginationRequest.Limit
+		tx = tx.Limit(*paginationRequest.Limit).Offset(offset)
+		paginationResponse.Page = *paginationRequest.Page
+		paginationResponse.PerPage = *paginationRequest.Limit
+		paginationResponse.TotalPages = (paginationResponse.TotalCount + *paginationRequest.Limit - 1) / *paginationRequest.Limit
+	}
+
+	tx = tx.Order(fmt.Sprintf("%s %s", paginationRequest.Sort, paginationRequest.Order))
+
+	if err := tx.Find(&record).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return record, paginationResponse, err
+	}
+
+	return record, paginationResponse, nil
+}
+
+// Update customerEmployment records based on filter criteria and a patch.
+func (u *CustomerEmploymentRepository) Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where(filter).Updates(patch).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var customerEmployment customerEmploymentDBModels.CustomerEmployment
+
+	if err := tx.Where(filter).First(&customerEmployment).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete customerEmployment records based on filter criteria.
+func (u *CustomerEmploymentRepository) Delete(ctx context.Context, filter map[string]interface{}) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where(filter).Delete(&customerEmploymentDBModels.CustomerEmployment{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
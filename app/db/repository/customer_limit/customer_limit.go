@@ -7,9 +7,12 @@ import (
 	"kredit-plus/app/constants"
 	"kredit-plus/app/db"
 	customerLimitDBModels "kredit-plus/app/db/dto/customer_limit"
+	customerLimitHistoryDBModels "kredit-plus/app/db/dto/customer_limit_history"
+	transactionDBModels "kredit-plus/app/db/dto/transaction"
 	"kredit-plus/app/service/dto/request"
 	"kredit-plus/app/service/dto/response"
 	"kredit-plus/app/service/util"
+	"time"
 
 	"github.com/jinzhu/gorm"
 )
@@ -21,6 +24,13 @@ type ICustomerLimitRepository interface {
 	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]customerLimitDBModels.CustomerLimit, response.Pagination, error)
 	Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error
 	Delete(ctx context.Context, filter map[string]interface{}) error
+	CreateWithHistory(ctx context.Context, customerLimit *customerLimitDBModels.CustomerLimit, history customerLimitHistoryDBModels.CustomerLimitHistory) error
+	UpdateWithHistory(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}, history customerLimitHistoryDBModels.CustomerLimitHistory) error
+	DeleteWithHistory(ctx context.Context, filter map[string]interface{}, history customerLimitHistoryDBModels.CustomerLimitHistory) error
+	Debit(ctx context.Context, id int, amount float32, history customerLimitHistoryDBModels.CustomerLimitHistory) (bool, error)
+	Grant(ctx context.Context, customerLimit *customerLimitDBModels.CustomerLimit, grantedAmount float32, history customerLimitHistoryDBModels.CustomerLimitHistory) error
+	Utilization(ctx context.Context, customerID int) ([]customerLimitDBModels.CustomerLimitUtilization, error)
+	ListDueForReview(ctx context.Context, before time.Time) ([]customerLimitDBModels.CustomerLimit, error)
 }
 
 type CustomerLimitRepository struct {
@@ -150,3 +160,306 @@ func (u *CustomerLimitRepository) Delete(ctx context.Context, filter map[string]
 
 	return tx.Commit().Error
 }
+
+// The ...WithHistory methods, Debit and Grant insert the history rows describing a change in
+// the transaction of the change, after locking the limits it touches. The history passed in
+// carries the action, actor and reason; the limit and its amounts are filled in per row.
+
+// CreateWithHistory creates a customerLimit record and its history.
+func (u *CustomerLimitRepository) CreateWithHistory(ctx context.Context, customerLimit *customerLimitDBModels.CustomerLimit, history customerLimitHistoryDBModels.CustomerLimitHistory) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(customerLimit).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := recordHistory(tx, history, *customerLimit, nil, &customerLimit.LimitAmount); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// UpdateWithHistory updates customerLimit records based on filter criteria and a patch and
+// records the old and new amount of every updated limit.
+func (u *CustomerLimitRepository) UpdateWithHistory(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}, history customerLimitHistoryDBModels.CustomerLimitHistory) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var before []customerLimitDBModels.CustomerLimit
+
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").Where(filter).Find(&before).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(before) == 0 {
+		tx.Rollback()
+		return gorm.ErrRecordNotFound
+	}
+
+	if err := tx.Where(filter).Updates(patch).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, old := range before {
+		var updated customerLimitDBModels.CustomerLimit
+
+		if err := tx.Where(map[string]interface{}{customerLimitDBModels.COLUMN_ID: old.ID}).First(&updated).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		oldAmount := old.LimitAmount
+		if err := recordHistory(tx, history, updated, &oldAmount, &updated.LimitAmount); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// DeleteWithHistory deletes customerLimit records based on filter criteria and records the
+// amount every deleted limit had.
+func (u *CustomerLimitRepository) DeleteWithHistory(ctx context.Context, filter map[string]interface{}, history customerLimitHistoryDBModels.CustomerLimitHistory) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var before []customerLimitDBModels.CustomerLimit
+
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").Where(filter).Find(&before).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where(filter).Delete(&customerLimitDBModels.CustomerLimit{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, old := range before {
+		oldAmount := old.LimitAmount
+		if err := recordHistory(tx, history, old, &oldAmount, nil); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// Debit lowers the limit by amount in a single conditional update, so concurrent checkouts
+// cannot together spend more than the limit. It reports false when the limit is lower than amount.
+func (u *CustomerLimitRepository) Debit(ctx context.Context, id int, amount float32, history customerLimitHistoryDBModels.CustomerLimitHistory) (bool, error) {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var before customerLimitDBModels.CustomerLimit
+
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").Where(map[string]interface{}{customerLimitDBModels.COLUMN_ID: id}).First(&before).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	result := tx.Where(fmt.Sprintf("%s = ? AND %s >= ?", customerLimitDBModels.COLUMN_ID, customerLimitDBModels.COLUMN_LIMIT_AMOUNT), id, amount).
+		Updates(map[string]interface{}{
+			customerLimitDBModels.COLUMN_LIMIT_AMOUNT: gorm.Expr(fmt.Sprintf("%s - ?", customerLimitDBModels.COLUMN_LIMIT_AMOUNT), amount),
+			customerLimitDBModels.COLUMN_UPDATED_AT:   time.Now(),
+		})
+	if result.Error != nil {
+		tx.Rollback()
+		return false, result.Error
+	}
+
+	if result.RowsAffected != 1 {
+		tx.Rollback()
+		return false, nil
+	}
+
+	var after customerLimitDBModels.CustomerLimit
+
+	if err := tx.Where(map[string]interface{}{customerLimitDBModels.COLUMN_ID: id}).First(&after).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	oldAmount := before.LimitAmount
+	if err := recordHistory(tx, history, after, &oldAmount, &after.LimitAmount); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit().Error
+}
+
+// Grant sets the limit of the customer and tenor to the granted amount less the installments
+// of its active transactions, floored at zero, and creates the limit when there is none yet.
+// The history action is set to create or update accordingly.
+// The amount in use is read after the limit is locked, so a concurrent checkout debiting the
+// limit is either fully counted or waits for the grant. The validity, status and timestamps
+// are taken from customerLimit, which is filled with the stored limit.
+func (u *CustomerLimitRepository) Grant(ctx context.Context, customerLimit *customerLimitDBModels.CustomerLimit, grantedAmount float32, history customerLimitHistoryDBModels.CustomerLimitHistory) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	filter := map[string]interface{}{
+		customerLimitDBModels.COLUMN_CUSTOMER_ID: customerLimit.CustomerID,
+		customerLimitDBModels.COLUMN_TENOR:       customerLimit.Tenor,
+	}
+
+	var before customerLimitDBModels.CustomerLimit
+
+	err := tx.Set("gorm:query_option", "FOR UPDATE").Where(filter).First(&before).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		return err
+	}
+
+	query := fmt.Sprintf(`SELECT COALESCE(SUM(installment_amount), 0) FROM %s
+		WHERE customer_id = ? AND installment_period = ? AND status = ?`, transactionDBModels.TABLE_NAME)
+
+	var inUse float64
+	if err := tx.Raw(query, customerLimit.CustomerID, customerLimit.Tenor, transactionDBModels.STATUS_ACTIVE).Row().Scan(&inUse); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	remaining := grantedAmount - float32(inUse)
+	if remaining < 0 {
+		remaining = 0
+	}
+	customerLimit.LimitAmount = remaining
+
+	if before.ID == 0 {
+		history.Action = customerLimitHistoryDBModels.ACTION_CREATE
+
+		if err := tx.Create(customerLimit).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := recordHistory(tx, history, *customerLimit, nil, &customerLimit.LimitAmount); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		return tx.Commit().Error
+	}
+
+	history.Action = customerLimitHistoryDBModels.ACTION_UPDATE
+
+	patch := map[string]interface{}{
+		customerLimitDBModels.COLUMN_LIMIT_AMOUNT: remaining,
+		customerLimitDBModels.COLUMN_VALID_FROM:   customerLimit.ValidFrom,
+		customerLimitDBModels.COLUMN_VALID_UNTIL:  customerLimit.ValidUntil,
+		customerLimitDBModels.COLUMN_STATUS:       customerLimit.Status,
+		customerLimitDBModels.COLUMN_UPDATED_AT:   customerLimit.UpdatedAt,
+	}
+
+	if err := tx.Where(map[string]interface{}{customerLimitDBModels.COLUMN_ID: before.ID}).Updates(patch).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where(map[string]interface{}{customerLimitDBModels.COLUMN_ID: before.ID}).First(customerLimit).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	oldAmount := before.LimitAmount
+	if err := recordHistory(tx, history, *customerLimit, &oldAmount, &customerLimit.LimitAmount); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// recordHistory inserts the history of a change to the limit on the transaction of the change.
+func recordHistory(tx *gorm.DB, history customerLimitHistoryDBModels.CustomerLimitHistory, customerLimit customerLimitDBModels.CustomerLimit, oldAmount *float32, newAmount *float32) error {
+	history.CustomerLimitID = customerLimit.ID
+	history.CustomerID = customerLimit.CustomerID
+	history.Tenor = customerLimit.Tenor
+	history.OldLimitAmount = oldAmount
+	history.NewLimitAmount = newAmount
+	history.CreatedAt = time.Now()
+
+	if err := history.Validate(); err != nil {
+		return err
+	}
+
+	return tx.Table(customerLimitHistoryDBModels.TABLE_NAME).Create(&history).Error
+}
+
+// Utilization aggregates the limits of a customer with the amounts used by active
+// transactions and held by pending ones, per tenor, in a single query.
+func (u *CustomerLimitRepository) Utilization(ctx context.Context, customerID int) ([]customerLimitDBModels.CustomerLimitUtilization, error) {
+	tx := u.DBService.GetDB()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	query := fmt.Sprintf(`SELECT cl.tenor, cl.limit_amount, cl.status, cl.valid_until,
+		COALESCE(SUM(t.installment_amount) FILTER (WHERE t.status = ?), 0) AS in_use_amount,
+		COALESCE(SUM(t.installment_amount) FILTER (WHERE t.status = ?), 0) AS on_hold_amount
+		FROM %s cl
+		LEFT JOIN %s t ON t.customer_id = cl.customer_id AND t.installment_period = cl.tenor
+		WHERE cl.customer_id = ?
+		GROUP BY cl.id, cl.tenor, cl.limit_amount, cl.status, cl.valid_until
+		ORDER BY cl.tenor`, tableName, transactionDBModels.TABLE_NAME)
+
+	var record []customerLimitDBModels.CustomerLimitUtilization
+
+	if err := tx.Raw(query, transactionDBModels.STATUS_ACTIVE, transactionDBModels.STATUS_PENDING, customerID).Scan(&record).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return record, nil
+		}
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// ListDueForReview returns the active limits whose validity ends before the given time.
+func (u *CustomerLimitRepository) ListDueForReview(ctx context.Context, before time.Time) ([]customerLimitDBModels.CustomerLimit, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var record []customerLimitDBModels.CustomerLimit
+
+	err := tx.Where(fmt.Sprintf("%s = ? AND %s <= ?", customerLimitDBModels.COLUMN_STATUS, customerLimitDBModels.COLUMN_VALID_UNTIL), customerLimitDBModels.STATUS_ACTIVE, before).
+		Order(customerLimitDBModels.COLUMN_CUSTOMER_ID).
+		Find(&record).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
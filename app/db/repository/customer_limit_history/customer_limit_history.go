@@ -0,0 +1,102 @@
+package customer_limit_history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	customerLimitHistoryDBModels "kredit-plus/app/db/dto/customer_limit_history"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/util"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with customer limit history data.
+type ICustomerLimitHistoryRepository interface {
+	Create(ctx context.Context, customerLimitHistory *customerLimitHistoryDBModels.CustomerLimitHistory) error
+	Get(ctx context.Context, filter map[string]interface{}) (customerLimitHistoryDBModels.CustomerLimitHistory, error)
+	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]customerLimitHistoryDBModels.CustomerLimitHistory, response.Pagination, error)
+}
+
+type CustomerLimitHistoryRepository struct {
+	DBService *db.DBService
+}
+
+// Constructor for creating a new CustomerLimitHistoryRepository.
+func NewCustomerLimitHistoryRepository(dbService *db.DBService) ICustomerLimitHistoryRepository {
+	return &CustomerLimitHistoryRepository{
+		DBService: dbService,
+	}
+}
+
+const tableName = customerLimitHistoryDBModels.TABLE_NAME
+
+// Create a new customerLimitHistory record.
+func (u *CustomerLimitHistoryRepository) Create(ctx context.Context, customerLimitHistory *customerLimitHistoryDBModels.CustomerLimitHistory) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(customerLimitHistory).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Retrieve a customerLimitHistory based on filter criteria.
+func (u *CustomerLimitHistoryRepository) Get(ctx context.Context, filter map[string]interface{}) (customerLimitHistoryDBModels.CustomerLimitHistory, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var customerLimitHistory customerLimitHistoryDBModels.CustomerLimitHistory
+
+	if err := tx.Where(filter).First(&customerLimitHistory).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customerLimitHistory, nil
+		}
+		return customerLimitHistory, err
+	}
+
+	return customerLimitHistory, nil
+}
+
+// List customerLimitHistorys based on filtering and pagination criteria.
+func (u *CustomerLimitHistoryRepository) List(ctx context.Context, paginationRequest request.Pagination, filter map[string]interface{}) (record []customerLimitHistoryDBModels.CustomerLimitHistory, paginationResponse response.Pagination, err error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	tx, err = util.ApplyFilterCondition(tx, filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
+	if err := tx.Count(&paginationResponse.TotalCount).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return nil, paginationResponse, err
+	}
+
+	if !paginationRequest.GetAllData {
+		offset := (*paginationRequest.Page - 1) * *paginationRequest.Limit
+		tx = tx.Limit(*paginationRequest.Limit).Offset(offset)
+		paginationResponse.Page = *paginationRequest.Page
+		paginationResponse.PerPage = *paginationRequest.Limit
+		paginationResponse.TotalPages = (paginationResponse.TotalCount + *paginationRequest.Limit - 1) / *paginationRequest.Limit
+	}
+
+	tx = tx.Order(fmt.Sprintf("%s %s", paginationRequest.Sort, paginationRequest.Order))
+
+	if err := tx.Find(&record).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return record, paginationResponse, err
+	}
+
+	return record, paginationResponse, nil
+}
@@ -0,0 +1,102 @@
+package customer_limit_review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	customerLimitReviewDBModels "kredit-plus/app/db/dto/customer_limit_review"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/util"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with customer limit review data.
+type ICustomerLimitReviewRepository interface {
+	Create(ctx context.Context, customerLimitReview *customerLimitReviewDBModels.CustomerLimitReview) error
+	Get(ctx context.Context, filter map[string]interface{}) (customerLimitReviewDBModels.CustomerLimitReview, error)
+	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]customerLimitReviewDBModels.CustomerLimitReview, response.Pagination, error)
+}
+
+type CustomerLimitReviewRepository struct {
+	DBService *db.DBService
+}
+
+// Constructor for creating a new CustomerLimitReviewRepository.
+func NewCustomerLimitReviewRepository(dbService *db.DBService) ICustomerLimitReviewRepository {
+	return &CustomerLimitReviewRepository{
+		DBService: dbService,
+	}
+}
+
+const tableName = customerLimitReviewDBModels.TABLE_NAME
+
+// Create a new customerLimitReview record.
+func (u *CustomerLimitReviewRepository) Create(ctx context.Context, customerLimitReview *customerLimitReviewDBModels.CustomerLimitReview) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(customerLimitReview).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Retrieve a customerLimitReview based on filter criteria.
+func (u *CustomerLimitReviewRepository) Get(ctx context.Context, filter map[string]interface{}) (customerLimitReviewDBModels.CustomerLimitReview, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var customerLimitReview customerLimitReviewDBModels.CustomerLimitReview
+
+	if err := tx.Where(filter).First(&customerLimitReview).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customerLimitReview, nil
+		}
+		return customerLimitReview, err
+	}
+
+	return customerLimitReview, nil
+}
+
+// List customerLimitReviews based on filtering and pagination criteria.
+func (u *CustomerLimitReviewRepository) List(ctx context.Context, paginationRequest request.Pagination, filter map[string]interface{}) (record []customerLimitReviewDBModels.CustomerLimitReview, paginationResponse response.Pagination, err error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	tx, err = util.ApplyFilterCondition(tx, filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
+	if err := tx.Count(&paginationResponse.TotalCount).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return nil, paginationResponse, err
+	}
+
+	if !paginationRequest.GetAllData {
+		offset := (*paginationRequest.Page - 1) * *paginationRequest.Limit
+		tx = tx.Limit(*paginationRequest.Limit).Offset(offset)
+		paginationResponse.Page = *paginationRequest.Page
+		paginationResponse.PerPage = *paginationRequest.Limit
+		paginationResponse.TotalPages = (paginationResponse.TotalCount + *paginationRequest.Limit - 1) / *paginationRequest.Limit
+	}
+
+	tx = tx.Order(fmt.Sprintf("%s %s", paginationRequest.Sort, paginationRequest.Order))
+
+	if err := tx.Find(&record).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return record, paginationResponse, err
+	}
+
+	return record, paginationResponse, nil
+}
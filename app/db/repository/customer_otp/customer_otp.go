@@ -0,0 +1,121 @@
+package customer_otp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	customerOtpDBModels "kredit-plus/app/db/dto/customer_otp"
+
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with customer OTP data.
+type ICustomerOtpRepository interface {
+	Create(ctx context.Context, customerOtp *customerOtpDBModels.CustomerOtp) error
+	Latest(ctx context.Context, customerID int, purpose string) (customerOtpDBModels.CustomerOtp, error)
+	ClaimAttempt(ctx context.Context, id int, maxAttempts int) (bool, error)
+	Use(ctx context.Context, id int, at time.Time, maxAttempts int) (bool, error)
+	Delete(ctx context.Context, filter map[string]interface{}) error
+}
+
+type CustomerOtpRepository struct {
+	DBService *db.DBService
+}
+
+// Constructor for creating a new CustomerOtpRepository.
+func NewCustomerOtpRepository(dbService *db.DBService) ICustomerOtpRepository {
+	return &CustomerOtpRepository{
+		DBService: dbService,
+	}
+}
+
+const tableName = customerOtpDBModels.TABLE_NAME
+
+// Create a new customerOtp record.
+func (u *CustomerOtpRepository) Create(ctx context.Context, customerOtp *customerOtpDBModels.CustomerOtp) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(customerOtp).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Latest returns the most recently issued OTP of the customer for the purpose.
+func (u *CustomerOtpRepository) Latest(ctx context.Context, customerID int, purpose string) (customerOtpDBModels.CustomerOtp, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var customerOtp customerOtpDBModels.CustomerOtp
+
+	err := tx.Where(map[string]interface{}{
+		customerOtpDBModels.COLUMN_CUSTOMER_ID: customerID,
+		customerOtpDBModels.COLUMN_PURPOSE:     purpose,
+	}).Order(fmt.Sprintf("%s DESC", customerOtpDBModels.COLUMN_CREATED_AT)).First(&customerOtp).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customerOtp, nil
+		}
+		return customerOtp, err
+	}
+
+	return customerOtp, nil
+}
+
+// ClaimAttempt counts an attempt to enter the OTP before the code is compared. It reports
+// false when the OTP has no attempts left, so concurrent guesses cannot together make more
+// than maxAttempts attempts.
+func (u *CustomerOtpRepository) ClaimAttempt(ctx context.Context, id int, maxAttempts int) (bool, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	result := tx.Where(fmt.Sprintf("%s = ? AND %s < ?", customerOtpDBModels.COLUMN_ID, customerOtpDBModels.COLUMN_ATTEMPTS), id, maxAttempts).
+		UpdateColumn(customerOtpDBModels.COLUMN_ATTEMPTS, gorm.Expr(fmt.Sprintf("%s + 1", customerOtpDBModels.COLUMN_ATTEMPTS)))
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// Use marks the OTP as used. It reports false when the OTP was already used or has made
+// more than maxAttempts attempts, so concurrent requests cannot both consume the same code.
+// The attempt that uses the code has been claimed already and may be the last one.
+func (u *CustomerOtpRepository) Use(ctx context.Context, id int, at time.Time, maxAttempts int) (bool, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	result := tx.Where(fmt.Sprintf("%s = ? AND %s IS NULL AND %s <= ?", customerOtpDBModels.COLUMN_ID, customerOtpDBModels.COLUMN_USED_AT, customerOtpDBModels.COLUMN_ATTEMPTS), id, maxAttempts).
+		Updates(map[string]interface{}{customerOtpDBModels.COLUMN_USED_AT: at})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// Delete customerOtp records matching the filter.
+func (u *CustomerOtpRepository) Delete(ctx context.Context, filter map[string]interface{}) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where(filter).Delete(&customerOtpDBModels.CustomerOtp{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
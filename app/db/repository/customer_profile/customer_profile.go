@@ -6,10 +6,16 @@ import (
 	"fmt"
 	"kredit-plus/app/constants"
 	"kredit-plus/app/db"
+	customerDBModels "kredit-plus/app/db/dto/customer"
 	customerProfileDBModels "kredit-plus/app/db/dto/customer_profile"
+	customerProfileVersionDBModels "kredit-plus/app/db/dto/customer_profile_version"
 	"kredit-plus/app/service/dto/request"
 	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/encryption"
 	"kredit-plus/app/service/util"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jinzhu/gorm"
 )
@@ -21,49 +27,112 @@ type ICustomerProfileRepository interface {
 	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]customerProfileDBModels.CustomerProfile, response.Pagination, error)
 	Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error
 	Delete(ctx context.Context, filter map[string]interface{}) error
+	CreateVersioned(ctx context.Context, customerProfile *customerProfileDBModels.CustomerProfile, record VersionFunc) error
+	UpdateVersioned(ctx context.Context, customerID int, patch map[string]interface{}, record VersionFunc) (customerProfileDBModels.CustomerProfile, error)
+	DeleteVersioned(ctx context.Context, customerID int, record VersionFunc) error
+	Candidates(ctx context.Context, customerProfile customerProfileDBModels.CustomerProfile) ([]customerProfileDBModels.CustomerProfile, error)
+	Reencrypt(ctx context.Context, afterID int, limit int) (int, int, error)
 }
 
 type CustomerProfileRepository struct {
 	DBService *db.DBService
+	Cipher    *encryption.Cipher
 }
 
 // Constructor for creating a new CustomerProfileRepository.
-func NewCustomerProfileRepository(dbService *db.DBService) ICustomerProfileRepository {
+func NewCustomerProfileRepository(dbService *db.DBService, cipher *encryption.Cipher) ICustomerProfileRepository {
 	return &CustomerProfileRepository{
 		DBService: dbService,
+		Cipher:    cipher,
 	}
 }
 
 const tableName = customerProfileDBModels.TABLE_NAME
 
+// nikIndex is the unique index on the blind index of the NIK.
+const nikIndex = "idx_customer_profiles_nik_bidx"
+
+// ErrNIKRegistered is returned when the NIK written is already on the profile of another customer.
+var ErrNIKRegistered = errors.New(constants.NIK_ALREADY_REGISTERED)
+
+// VersionFunc returns the version recorded for a change of the profile. previous is the zero
+// profile when the customer had none and current is nil when the profile was deleted; the
+// repository fills in the number of the version.
+type VersionFunc func(previous customerProfileDBModels.CustomerProfile, current *customerProfileDBModels.CustomerProfile) (customerProfileVersionDBModels.CustomerProfileVersion, error)
+
+// encryptedColumns are stored encrypted; callers read and write them in plaintext.
+var encryptedColumns = map[string]bool{
+	customerProfileDBModels.COLUMN_NIK:          true,
+	customerProfileDBModels.COLUMN_LEGAL_NAME:   true,
+	customerProfileDBModels.COLUMN_SALARY:       true,
+	customerProfileDBModels.COLUMN_KTP_IMAGE:    true,
+	customerProfileDBModels.COLUMN_SELFIE_IMAGE: true,
+}
+
+// customerProfileRow is a customer profile as stored, with the encrypted columns as
+// ciphertext and the blind indexes of the NIK and of the name and date of birth. DateOfBirthEncrypted is only set on rows
+// cmd/reencrypt has not moved to the DATE column yet.
+type customerProfileRow struct {
+	ID           int
+	CustomerID   int
+	NIK          string `gorm:"column:nik"`
+	NIKBidx      string `gorm:"column:nik_bidx"`
+	FullName     string
+	LegalName    string
+	PlaceOfBirth string
+	DateOfBirth  util.Date
+	Salary       string
+	KtpImage     string
+	SelfieImage  string
+	SelfieHash   string
+	Version      int
+	CreatedAt    time.Time
+	UpdatedAt    *time.Time
+
+	DateOfBirthEncrypted string
+	NameDateOfBirthBidx  string
+}
+
 // Create a new customerProfile record.
 func (u *CustomerProfileRepository) Create(ctx context.Context, customerProfile *customerProfileDBModels.CustomerProfile) error {
+	row, err := u.encrypt(*customerProfile)
+	if err != nil {
+		return err
+	}
+
 	tx := u.DBService.GetDB().Table(tableName).Begin()
 	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
 
-	if err := tx.Create(customerProfile).Error; err != nil {
+	if err := tx.Create(&row).Error; err != nil {
 		tx.Rollback()
-		return err
+		return nikConflict(err)
 	}
 
+	customerProfile.ID = row.ID
+
 	return tx.Commit().Error
 }
 
-// Retrieve a customerProfile based on filter criteria.
+// Retrieve a customerProfile based on filter criteria. A NIK in the filter is matched through its blind index.
 func (u *CustomerProfileRepository) Get(ctx context.Context, filter map[string]interface{}) (customerProfileDBModels.CustomerProfile, error) {
 	tx := u.DBService.GetDB().Table(tableName)
 	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
 
-	var customerProfile customerProfileDBModels.CustomerProfile
+	filter, err := u.encryptFilter(filter)
+	if err != nil {
+		return customerProfileDBModels.CustomerProfile{}, err
+	}
 
-	if err := tx.Where(filter).First(&customerProfile).Error; err != nil {
+	var row customerProfileRow
+
+	if err := tx.Where(filter).First(&row).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return customerProfile, nil
+			return customerProfileDBModels.CustomerProfile{}, nil
 		}
-		return customerProfile, err
+		return customerProfileDBModels.CustomerProfile{}, err
 	}
 
-	return customerProfile, nil
+	return u.decrypt(row)
 }
 
 // List customers based on filtering and pagination criteria.
@@ -71,6 +140,11 @@ func (u *CustomerProfileRepository) List(ctx context.Context, paginationRequest
 	tx := u.DBService.GetDB().Table(tableName)
 	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
 
+	filter, err = u.encryptFilter(filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
 	tx, err = util.ApplyFilterCondition(tx, filter)
 	if err != nil {
 		return nil, paginationResponse, err
@@ -93,18 +167,38 @@ func (u *CustomerProfileRepository) List(ctx context.Context, paginationRequest
 
 	tx = tx.Order(fmt.Sprintf("%s %s", paginationRequest.Sort, paginationRequest.Order))
 
-	if err := tx.Find(&record).Error; err != nil {
+	var rows []customerProfileRow
+
+	if err := tx.Find(&rows).Error; err != nil {
 		if gorm.IsRecordNotFoundError(err) {
 			return nil, paginationResponse, nil
 		}
 		return record, paginationResponse, err
 	}
 
+	for _, row := range rows {
+		customerProfile, err := u.decrypt(row)
+		if err != nil {
+			return nil, paginationResponse, err
+		}
+		record = append(record, customerProfile)
+	}
+
 	return record, paginationResponse, nil
 }
 
 // Update customerProfile records based on filter criteria and a patch.
 func (u *CustomerProfileRepository) Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error {
+	filter, err := u.encryptFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	patch, err = u.encryptPatch(patch)
+	if err != nil {
+		return err
+	}
+
 	tx := u.DBService.GetDB().Table(tableName).Begin()
 	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
 
@@ -116,16 +210,34 @@ func (u *CustomerProfileRepository) Update(ctx context.Context, filter map[strin
 
 	if err := tx.Where(filter).Updates(patch).Error; err != nil {
 		tx.Rollback()
-		return err
+		return nikConflict(err)
 	}
 
-	var customerProfile customerProfileDBModels.CustomerProfile
+	var rows []customerProfileRow
 
-	if err := tx.Where(filter).First(&customerProfile).Error; err != nil {
+	if err := tx.Where(filter).Find(&rows).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
 
+	if len(rows) == 0 {
+		tx.Rollback()
+		return gorm.ErrRecordNotFound
+	}
+
+	for _, row := range rows {
+		customerProfile, err := u.decrypt(row)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := u.refreshNameIndex(tx, row, customerProfile); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return err
 	}
@@ -135,6 +247,11 @@ func (u *CustomerProfileRepository) Update(ctx context.Context, filter map[strin
 
 // Delete customerProfile records based on filter criteria.
 func (u *CustomerProfileRepository) Delete(ctx context.Context, filter map[string]interface{}) error {
+	filter, err := u.encryptFilter(filter)
+	if err != nil {
+		return err
+	}
+
 	tx := u.DBService.GetDB().Table(tableName).Begin()
 	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
 
@@ -144,9 +261,510 @@ func (u *CustomerProfileRepository) Delete(ctx context.Context, filter map[strin
 		}
 	}()
 
-	if err := tx.Where(filter).Delete(&customerProfileDBModels.CustomerProfile{}).Error; err != nil {
+	if err := tx.Where(filter).Delete(&customerProfileRow{}).Error; err != nil {
 		return err
 	}
 
 	return tx.Commit().Error
 }
+
+// CreateVersioned stores the profile, replacing the current profile of the customer in place
+// if there is one, and records the change as the next version in the same transaction.
+func (u *CustomerProfileRepository) CreateVersioned(ctx context.Context, customerProfile *customerProfileDBModels.CustomerProfile, record VersionFunc) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	previous, version, err := u.lockForVersion(tx, customerProfile.CustomerID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	customerProfile.ID = previous.ID
+	customerProfile.Version = version
+	if previous.ID != 0 {
+		customerProfile.CreatedAt = previous.CreatedAt
+	}
+
+	row, err := u.encrypt(*customerProfile)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if previous.ID != 0 {
+		err = tx.Table(tableName).Save(&row).Error
+	} else {
+		err = tx.Table(tableName).Create(&row).Error
+	}
+	if err != nil {
+		tx.Rollback()
+		return nikConflict(err)
+	}
+
+	customerProfile.ID = row.ID
+
+	if err := u.recordVersion(tx, record, version, previous, customerProfile); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// UpdateVersioned patches the profile of the customer, records the change as the next
+// version in the same transaction and returns the updated profile. It returns
+// gorm.ErrRecordNotFound when the customer has no profile.
+func (u *CustomerProfileRepository) UpdateVersioned(ctx context.Context, customerID int, patch map[string]interface{}, record VersionFunc) (customerProfileDBModels.CustomerProfile, error) {
+	patch, err := u.encryptPatch(patch)
+	if err != nil {
+		return customerProfileDBModels.CustomerProfile{}, err
+	}
+
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	previous, version, err := u.lockForVersion(tx, customerID)
+	if err != nil {
+		tx.Rollback()
+		return previous, err
+	}
+
+	if previous.ID == 0 {
+		tx.Rollback()
+		return previous, gorm.ErrRecordNotFound
+	}
+
+	patch[customerProfileDBModels.COLUMN_VERSION] = version
+
+	if err := tx.Table(tableName).Where(fmt.Sprintf("%s = ?", customerProfileDBModels.COLUMN_ID), previous.ID).Updates(patch).Error; err != nil {
+		tx.Rollback()
+		return previous, nikConflict(err)
+	}
+
+	var row customerProfileRow
+
+	if err := tx.Table(tableName).Where(fmt.Sprintf("%s = ?", customerProfileDBModels.COLUMN_ID), previous.ID).First(&row).Error; err != nil {
+		tx.Rollback()
+		return previous, err
+	}
+
+	customerProfile, err := u.decrypt(row)
+	if err != nil {
+		tx.Rollback()
+		return previous, err
+	}
+
+	if err := u.refreshNameIndex(tx, row, customerProfile); err != nil {
+		tx.Rollback()
+		return previous, err
+	}
+
+	if err := u.recordVersion(tx, record, version, previous, &customerProfile); err != nil {
+		tx.Rollback()
+		return previous, err
+	}
+
+	return customerProfile, tx.Commit().Error
+}
+
+// DeleteVersioned removes the profile of the customer and records the deletion as the next
+// version in the same transaction. Nothing is recorded when the customer has no profile.
+func (u *CustomerProfileRepository) DeleteVersioned(ctx context.Context, customerID int, record VersionFunc) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	previous, version, err := u.lockForVersion(tx, customerID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if previous.ID == 0 {
+		return tx.Rollback().Error
+	}
+
+	if err := tx.Table(tableName).Where(fmt.Sprintf("%s = ?", customerProfileDBModels.COLUMN_ID), previous.ID).Delete(&customerProfileRow{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := u.recordVersion(tx, record, version, previous, nil); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// lockForVersion locks the customer, so changes of the same profile are versioned one after
+// the other, and returns the current profile and the number of the next version.
+func (u *CustomerProfileRepository) lockForVersion(tx *gorm.DB, customerID int) (customerProfileDBModels.CustomerProfile, int, error) {
+	var customer struct{ ID int }
+
+	if err := tx.Table(customerDBModels.TABLE_NAME).Set("gorm:query_option", "FOR UPDATE").
+		Where(fmt.Sprintf("%s = ?", customerDBModels.COLUMN_ID), customerID).Select(customerDBModels.COLUMN_ID).First(&customer).Error; err != nil {
+		return customerProfileDBModels.CustomerProfile{}, 0, err
+	}
+
+	var row customerProfileRow
+	var previous customerProfileDBModels.CustomerProfile
+
+	err := tx.Table(tableName).Where(fmt.Sprintf("%s = ?", customerProfileDBModels.COLUMN_CUSTOMER_ID), customerID).First(&row).Error
+	switch {
+	case err == nil:
+		if previous, err = u.decrypt(row); err != nil {
+			return previous, 0, err
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return previous, 0, err
+	}
+
+	var latest struct{ Version int }
+
+	if err := tx.Table(customerProfileVersionDBModels.TABLE_NAME).
+		Select(fmt.Sprintf("COALESCE(MAX(%s), 0) AS version", customerProfileVersionDBModels.COLUMN_VERSION)).
+		Where(fmt.Sprintf("%s = ?", customerProfileVersionDBModels.COLUMN_CUSTOMER_ID), customerID).Scan(&latest).Error; err != nil {
+		return previous, 0, err
+	}
+
+	return previous, latest.Version + 1, nil
+}
+
+// Candidates returns the profiles of other customers with the same NIK, the same selfie hash
+// or the same name and date of birth as the profile, looked up through indexed columns.
+func (u *CustomerProfileRepository) Candidates(ctx context.Context, customerProfile customerProfileDBModels.CustomerProfile) ([]customerProfileDBModels.CustomerProfile, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	conditions := []string{}
+	values := []interface{}{}
+
+	for column, value := range map[string]string{
+		customerProfileDBModels.COLUMN_NIK_BIDX:                u.Cipher.BlindIndex(customerProfile.NIK),
+		customerProfileDBModels.COLUMN_SELFIE_HASH:             customerProfile.SelfieHash,
+		customerProfileDBModels.COLUMN_NAME_DATE_OF_BIRTH_BIDX: u.nameDateOfBirthIndex(customerProfile),
+	} {
+		if value != "" {
+			conditions = append(conditions, fmt.Sprintf("%s = ?", column))
+			values = append(values, value)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	var rows []customerProfileRow
+
+	if err := tx.Where(fmt.Sprintf("%s <> ?", customerProfileDBModels.COLUMN_CUSTOMER_ID), customerProfile.CustomerID).
+		Where(strings.Join(conditions, " OR "), values...).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	record := make([]customerProfileDBModels.CustomerProfile, 0, len(rows))
+	for _, row := range rows {
+		candidate, err := u.decrypt(row)
+		if err != nil {
+			return nil, err
+		}
+		record = append(record, candidate)
+	}
+
+	return record, nil
+}
+
+// nameDateOfBirthIndex is the blind index of the name and date of birth of the profile, empty
+// when either is missing.
+func (u *CustomerProfileRepository) nameDateOfBirthIndex(customerProfile customerProfileDBModels.CustomerProfile) string {
+	name := customerProfile.Name()
+	if name == "" || customerProfile.DateOfBirth.IsZero() {
+		return ""
+	}
+
+	return u.Cipher.BlindIndex(name + "|" + customerProfile.DateOfBirth.String())
+}
+
+// refreshNameIndex keeps the blind index of the name and date of birth in step after a patch.
+func (u *CustomerProfileRepository) refreshNameIndex(tx *gorm.DB, row customerProfileRow, customerProfile customerProfileDBModels.CustomerProfile) error {
+	index := u.nameDateOfBirthIndex(customerProfile)
+	if index == row.NameDateOfBirthBidx {
+		return nil
+	}
+
+	return tx.Table(tableName).Where(fmt.Sprintf("%s = ?", customerProfileDBModels.COLUMN_ID), row.ID).
+		UpdateColumn(customerProfileDBModels.COLUMN_NAME_DATE_OF_BIRTH_BIDX, index).Error
+}
+
+// nikConflict returns ErrNIKRegistered for a write that violates the unique index on the NIK.
+func nikConflict(err error) error {
+	if db.IsUniqueViolation(err, nikIndex) {
+		return ErrNIKRegistered
+	}
+	return err
+}
+
+// recordVersion stores the version returned by record with its snapshot encrypted.
+func (u *CustomerProfileRepository) recordVersion(tx *gorm.DB, record VersionFunc, version int, previous customerProfileDBModels.CustomerProfile, current *customerProfileDBModels.CustomerProfile) error {
+	customerProfileVersion, err := record(previous, current)
+	if err != nil {
+		return err
+	}
+
+	customerProfileVersion.Version = version
+
+	if err := customerProfileVersion.Validate(); err != nil {
+		return err
+	}
+
+	if customerProfileVersion.Snapshot, err = u.Cipher.Encrypt(customerProfileVersion.Snapshot, customerProfileVersionDBModels.COLUMN_SNAPSHOT); err != nil {
+		return err
+	}
+
+	return tx.Table(customerProfileVersionDBModels.TABLE_NAME).Create(&customerProfileVersion).Error
+}
+
+// Reencrypt encrypts up to limit profiles after afterID with the current key, including
+// rows still in plaintext, refreshes the blind indexes and moves encrypted dates of birth to
+// the DATE column. It returns the last ID read and the number of rows rewritten.
+func (u *CustomerProfileRepository) Reencrypt(ctx context.Context, afterID int, limit int) (int, int, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var rows []customerProfileRow
+
+	if err := tx.Where(fmt.Sprintf("%s > ?", customerProfileDBModels.COLUMN_ID), afterID).
+		Order(customerProfileDBModels.COLUMN_ID).Limit(limit).Find(&rows).Error; err != nil {
+		return afterID, 0, err
+	}
+
+	rewritten := 0
+
+	for _, row := range rows {
+		afterID = row.ID
+
+		customerProfile, err := u.decrypt(row)
+		if err != nil {
+			return afterID, rewritten, fmt.Errorf("customer profile %d: %w", row.ID, err)
+		}
+
+		fresh, err := u.encrypt(customerProfile)
+		if err != nil {
+			return afterID, rewritten, err
+		}
+
+		if !u.needsRotation(row) && row.NIKBidx == fresh.NIKBidx && row.NameDateOfBirthBidx == fresh.NameDateOfBirthBidx && row.DateOfBirthEncrypted == "" {
+			continue
+		}
+
+		patch := map[string]interface{}{
+			customerProfileDBModels.COLUMN_NIK:                     fresh.NIK,
+			customerProfileDBModels.COLUMN_NIK_BIDX:                fresh.NIKBidx,
+			customerProfileDBModels.COLUMN_LEGAL_NAME:              fresh.LegalName,
+			customerProfileDBModels.COLUMN_DATE_OF_BIRTH:           fresh.DateOfBirth,
+			customerProfileDBModels.COLUMN_DATE_OF_BIRTH_ENCRYPTED: nil,
+			customerProfileDBModels.COLUMN_SALARY:                  fresh.Salary,
+			customerProfileDBModels.COLUMN_KTP_IMAGE:               fresh.KtpImage,
+			customerProfileDBModels.COLUMN_SELFIE_IMAGE:            fresh.SelfieImage,
+			customerProfileDBModels.COLUMN_NAME_DATE_OF_BIRTH_BIDX: fresh.NameDateOfBirthBidx,
+		}
+
+		if err := u.DBService.GetDB().Table(tableName).Where(fmt.Sprintf("%s = ?", customerProfileDBModels.COLUMN_ID), row.ID).UpdateColumns(patch).Error; err != nil {
+			return afterID, rewritten, err
+		}
+
+		rewritten++
+	}
+
+	return afterID, rewritten, nil
+}
+
+func (u *CustomerProfileRepository) needsRotation(row customerProfileRow) bool {
+	for _, value := range []string{row.NIK, row.LegalName, row.Salary, row.KtpImage, row.SelfieImage} {
+		if u.Cipher.NeedsRotation(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *CustomerProfileRepository) encrypt(customerProfile customerProfileDBModels.CustomerProfile) (customerProfileRow, error) {
+	row := customerProfileRow{
+		ID:           customerProfile.ID,
+		CustomerID:   customerProfile.CustomerID,
+		NIKBidx:      u.Cipher.BlindIndex(customerProfile.NIK),
+		FullName:     customerProfile.FullName,
+		PlaceOfBirth: customerProfile.PlaceOfBirth,
+		DateOfBirth:  customerProfile.DateOfBirth,
+		SelfieHash:   customerProfile.SelfieHash,
+		Version:      customerProfile.Version,
+		CreatedAt:    customerProfile.CreatedAt,
+		UpdatedAt:    customerProfile.UpdatedAt,
+
+		NameDateOfBirthBidx: u.nameDateOfBirthIndex(customerProfile),
+	}
+
+	fields := []struct {
+		column string
+		value  string
+		target *string
+	}{
+		{customerProfileDBModels.COLUMN_NIK, customerProfile.NIK, &row.NIK},
+		{customerProfileDBModels.COLUMN_LEGAL_NAME, customerProfile.LegalName, &row.LegalName},
+		{customerProfileDBModels.COLUMN_SALARY, formatSalary(customerProfile.Salary), &row.Salary},
+		{customerProfileDBModels.COLUMN_KTP_IMAGE, customerProfile.KtpImage, &row.KtpImage},
+		{customerProfileDBModels.COLUMN_SELFIE_IMAGE, customerProfile.SelfieImage, &row.SelfieImage},
+	}
+
+	for _, field := range fields {
+		ciphertext, err := u.Cipher.Encrypt(field.value, field.column)
+		if err != nil {
+			return row, err
+		}
+		*field.target = ciphertext
+	}
+
+	return row, nil
+}
+
+func (u *CustomerProfileRepository) decrypt(row customerProfileRow) (customerProfileDBModels.CustomerProfile, error) {
+	customerProfile := customerProfileDBModels.CustomerProfile{
+		ID:           row.ID,
+		CustomerID:   row.CustomerID,
+		FullName:     row.FullName,
+		PlaceOfBirth: row.PlaceOfBirth,
+		DateOfBirth:  row.DateOfBirth,
+		SelfieHash:   row.SelfieHash,
+		Version:      row.Version,
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+	}
+
+	var dateOfBirth, salary string
+
+	fields := []struct {
+		column string
+		value  string
+		target *string
+	}{
+		{customerProfileDBModels.COLUMN_NIK, row.NIK, &customerProfile.NIK},
+		{customerProfileDBModels.COLUMN_LEGAL_NAME, row.LegalName, &customerProfile.LegalName},
+		{customerProfileDBModels.COLUMN_DATE_OF_BIRTH, row.DateOfBirthEncrypted, &dateOfBirth},
+		{customerProfileDBModels.COLUMN_SALARY, row.Salary, &salary},
+		{customerProfileDBModels.COLUMN_KTP_IMAGE, row.KtpImage, &customerProfile.KtpImage},
+		{customerProfileDBModels.COLUMN_SELFIE_IMAGE, row.SelfieImage, &customerProfile.SelfieImage},
+	}
+
+	for _, field := range fields {
+		plaintext, err := u.Cipher.Decrypt(field.value, field.column)
+		if err != nil {
+			return customerProfile, err
+		}
+		*field.target = plaintext
+	}
+
+	if customerProfile.DateOfBirth.IsZero() && dateOfBirth != "" {
+		value, err := util.ParseDate(dateOfBirth)
+		if err != nil {
+			return customerProfile, err
+		}
+		customerProfile.DateOfBirth = value
+	}
+
+	if salary != "" {
+		value, err := strconv.ParseFloat(salary, 32)
+		if err != nil {
+			return customerProfile, err
+		}
+		customerProfile.Salary = float32(value)
+	}
+
+	return customerProfile, nil
+}
+
+// encryptFilter replaces a NIK by its blind index. Other encrypted columns cannot be filtered on.
+func (u *CustomerProfileRepository) encryptFilter(filter map[string]interface{}) (map[string]interface{}, error) {
+	encrypted := make(map[string]interface{}, len(filter))
+
+	for column, value := range filter {
+		switch {
+		case column == customerProfileDBModels.COLUMN_NIK:
+			nik, ok := value.(string)
+			if !ok {
+				return nil, errors.New(constants.INVALID_INPUT)
+			}
+			encrypted[customerProfileDBModels.COLUMN_NIK_BIDX] = u.Cipher.BlindIndex(nik)
+		case encryptedColumns[column]:
+			return nil, fmt.Errorf("cannot filter on encrypted column %s", column)
+		default:
+			encrypted[column] = value
+		}
+	}
+
+	return encrypted, nil
+}
+
+// encryptPatch encrypts the values of encrypted columns and keeps the blind index of the NIK in step.
+func (u *CustomerProfileRepository) encryptPatch(patch map[string]interface{}) (map[string]interface{}, error) {
+	encrypted := make(map[string]interface{}, len(patch))
+
+	for column, value := range patch {
+		if column == customerProfileDBModels.COLUMN_DATE_OF_BIRTH {
+			encrypted[customerProfileDBModels.COLUMN_DATE_OF_BIRTH_ENCRYPTED] = nil
+		}
+
+		if !encryptedColumns[column] || value == nil {
+			encrypted[column] = value
+			if column == customerProfileDBModels.COLUMN_NIK && value == nil {
+				encrypted[customerProfileDBModels.COLUMN_NIK_BIDX] = nil
+			}
+			continue
+		}
+
+		var plaintext string
+		switch v := value.(type) {
+		case string:
+			plaintext = v
+		case float32:
+			plaintext = formatSalary(v)
+		case float64:
+			plaintext = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			plaintext = fmt.Sprint(v)
+		}
+
+		ciphertext, err := u.Cipher.Encrypt(plaintext, column)
+		if err != nil {
+			return nil, err
+		}
+		encrypted[column] = ciphertext
+
+		if column == customerProfileDBModels.COLUMN_NIK {
+			encrypted[customerProfileDBModels.COLUMN_NIK_BIDX] = u.Cipher.BlindIndex(plaintext)
+		}
+	}
+
+	return encrypted, nil
+}
+
+func formatSalary(salary float32) string {
+	return strconv.FormatFloat(float64(salary), 'f', -1, 32)
+}
@@ -0,0 +1,183 @@
+package customer_profile_version
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	customerProfileVersionDBModels "kredit-plus/app/db/dto/customer_profile_version"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/encryption"
+	"kredit-plus/app/service/util"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with customer profile version data.
+type ICustomerProfileVersionRepository interface {
+	Create(ctx context.Context, customerProfileVersion *customerProfileVersionDBModels.CustomerProfileVersion) error
+	Get(ctx context.Context, filter map[string]interface{}) (customerProfileVersionDBModels.CustomerProfileVersion, error)
+	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]customerProfileVersionDBModels.CustomerProfileVersion, response.Pagination, error)
+	ClearSnapshots(ctx context.Context, customerID int) error
+	Reencrypt(ctx context.Context, afterID int, limit int) (int, int, error)
+}
+
+type CustomerProfileVersionRepository struct {
+	DBService *db.DBService
+	Cipher    *encryption.Cipher
+}
+
+// Constructor for creating a new CustomerProfileVersionRepository.
+func NewCustomerProfileVersionRepository(dbService *db.DBService, cipher *encryption.Cipher) ICustomerProfileVersionRepository {
+	return &CustomerProfileVersionRepository{
+		DBService: dbService,
+		Cipher:    cipher,
+	}
+}
+
+const tableName = customerProfileVersionDBModels.TABLE_NAME
+
+// Create a new customerProfileVersion record. The snapshot is stored encrypted.
+func (u *CustomerProfileVersionRepository) Create(ctx context.Context, customerProfileVersion *customerProfileVersionDBModels.CustomerProfileVersion) error {
+	row := *customerProfileVersion
+
+	snapshot, err := u.Cipher.Encrypt(row.Snapshot, customerProfileVersionDBModels.COLUMN_SNAPSHOT)
+	if err != nil {
+		return err
+	}
+	row.Snapshot = snapshot
+
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(&row).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	customerProfileVersion.ID = row.ID
+
+	return tx.Commit().Error
+}
+
+// Retrieve a customerProfileVersion based on filter criteria.
+func (u *CustomerProfileVersionRepository) Get(ctx context.Context, filter map[string]interface{}) (customerProfileVersionDBModels.CustomerProfileVersion, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var customerProfileVersion customerProfileVersionDBModels.CustomerProfileVersion
+
+	if err := tx.Where(filter).First(&customerProfileVersion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customerProfileVersion, nil
+		}
+		return customerProfileVersion, err
+	}
+
+	snapshot, err := u.Cipher.Decrypt(customerProfileVersion.Snapshot, customerProfileVersionDBModels.COLUMN_SNAPSHOT)
+	if err != nil {
+		return customerProfileVersion, err
+	}
+	customerProfileVersion.Snapshot = snapshot
+
+	return customerProfileVersion, nil
+}
+
+// List customerProfileVersions based on filtering and pagination criteria.
+func (u *CustomerProfileVersionRepository) List(ctx context.Context, paginationRequest request.Pagination, filter map[string]interface{}) (record []customerProfileVersionDBModels.CustomerProfileVersion, paginationResponse response.Pagination, err error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	tx, err = util.ApplyFilterCondition(tx, filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
+	if err := tx.Count(&paginationResponse.TotalCount).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return nil, paginationResponse, err
+	}
+
+	if !paginationRequest.GetAllData {
+		offset := (*paginationRequest.Page - 1) * *paginationRequest.Limit
+		tx = tx.Limit(*paginationRequest.Limit).Offset(offset)
+		paginationResponse.Page = *paginationRequest.Page
+		paginationResponse.PerPage = *paginationRequest.Limit
+		paginationResponse.TotalPages = (paginationResponse.TotalCount + *paginationRequest.Limit - 1) / *paginationRequest.Limit
+	}
+
+	tx = tx.Order(fmt.Sprintf("%s %s", paginationRequest.Sort, paginationRequest.Order))
+
+	if err := tx.Find(&record).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return record, paginationResponse, err
+	}
+
+	for i := range record {
+		snapshot, err := u.Cipher.Decrypt(record[i].Snapshot, customerProfileVersionDBModels.COLUMN_SNAPSHOT)
+		if err != nil {
+			return nil, paginationResponse, err
+		}
+		record[i].Snapshot = snapshot
+	}
+
+	return record, paginationResponse, nil
+}
+
+// ClearSnapshots removes the profile data from every version of the customer, keeping
+// which fields changed when and by whom.
+func (u *CustomerProfileVersionRepository) ClearSnapshots(ctx context.Context, customerID int) error {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	return tx.Where(fmt.Sprintf("%s = ?", customerProfileVersionDBModels.COLUMN_CUSTOMER_ID), customerID).
+		UpdateColumn(customerProfileVersionDBModels.COLUMN_SNAPSHOT, gorm.Expr("NULL")).Error
+}
+
+// Reencrypt encrypts up to limit snapshots after afterID with the current key, including
+// snapshots still in plaintext. It returns the last ID read and the number of rows rewritten.
+func (u *CustomerProfileVersionRepository) Reencrypt(ctx context.Context, afterID int, limit int) (int, int, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var rows []customerProfileVersionDBModels.CustomerProfileVersion
+
+	if err := tx.Where(fmt.Sprintf("%s > ?", customerProfileVersionDBModels.COLUMN_ID), afterID).
+		Order(customerProfileVersionDBModels.COLUMN_ID).Limit(limit).Find(&rows).Error; err != nil {
+		return afterID, 0, err
+	}
+
+	rewritten := 0
+
+	for _, row := range rows {
+		afterID = row.ID
+
+		if !u.Cipher.NeedsRotation(row.Snapshot) {
+			continue
+		}
+
+		snapshot, err := u.Cipher.Decrypt(row.Snapshot, customerProfileVersionDBModels.COLUMN_SNAPSHOT)
+		if err != nil {
+			return afterID, rewritten, fmt.Errorf("customer profile version %d: %w", row.ID, err)
+		}
+
+		if snapshot, err = u.Cipher.Encrypt(snapshot, customerProfileVersionDBModels.COLUMN_SNAPSHOT); err != nil {
+			return afterID, rewritten, err
+		}
+
+		if err := u.DBService.GetDB().Table(tableName).Where(fmt.Sprintf("%s = ?", customerProfileVersionDBModels.COLUMN_ID), row.ID).
+			UpdateColumn(customerProfileVersionDBModels.COLUMN_SNAPSHOT, snapshot).Error; err != nil {
+			return afterID, rewritten, err
+		}
+
+		rewritten++
+	}
+
+	return afterID, rewritten, nil
+}
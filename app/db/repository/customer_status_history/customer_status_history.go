@@ -0,0 +1,83 @@
+package customer_status_history
+
+import (
+	"context"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	customerStatusHistoryDBModels "kredit-plus/app/db/dto/customer_status_history"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/util"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with customer status history data.
+type ICustomerStatusHistoryRepository interface {
+	Create(ctx context.Context, customerStatusHistory *customerStatusHistoryDBModels.CustomerStatusHistory) error
+	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]customerStatusHistoryDBModels.CustomerStatusHistory, response.Pagination, error)
+}
+
+type CustomerStatusHistoryRepository struct {
+	DBService *db.DBService
+}
+
+// Constructor for creating a new CustomerStatusHistoryRepository.
+func NewCustomerStatusHistoryRepository(dbService *db.DBService) ICustomerStatusHistoryRepository {
+	return &CustomerStatusHistoryRepository{
+		DBService: dbService,
+	}
+}
+
+const tableName = customerStatusHistoryDBModels.TABLE_NAME
+
+// Create a new customerStatusHistory record.
+func (u *CustomerStatusHistoryRepository) Create(ctx context.Context, customerStatusHistory *customerStatusHistoryDBModels.CustomerStatusHistory) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(customerStatusHistory).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// List customerStatusHistorys based on filtering and pagination criteria.
+func (u *CustomerStatusHistoryRepository) List(ctx context.Context, paginationRequest request.Pagination, filter map[string]interface{}) (record []customerStatusHistoryDBModels.CustomerStatusHistory, paginationResponse response.Pagination, err error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	tx, err = util.ApplyFilterCondition(tx, filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
+	if err := tx.Count(&paginationResponse.TotalCount).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return nil, paginationResponse, err
+	}
+
+	if !paginationRequest.GetAllData {
+		offset := (*paginationRequest.Page - 1) * *paginationRequest.Limit
+		tx = tx.Limit(*paginationRequest.Limit).Offset(offset)
+		paginationResponse.Page = *paginationRequest.Page
+		paginationResponse.PerPage = *paginationRequest.Limit
+		paginationResponse.TotalPages = (paginationResponse.TotalCount + *paginationRequest.Limit - 1) / *paginationRequest.Limit
+	}
+
+	tx = tx.Order(fmt.Sprintf("%s %s", paginationRequest.Sort, paginationRequest.Order))
+
+	if err := tx.Find(&record).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return record, paginationResponse, err
+	}
+
+	return record, paginationResponse, nil
+}
@@ -10,7 +10,9 @@ import (
 	"kredit-plus/app/service/dto/request"
 	"kredit-plus/app/service/dto/response"
 	"kredit-plus/app/service/util"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
 )
 
@@ -21,6 +23,9 @@ type ICustomerTokenRepository interface {
 	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]customerTokenDBModels.CustomerToken, response.Pagination, error)
 	Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error
 	Delete(ctx context.Context, filter map[string]interface{}) error
+	DeleteOthers(ctx context.Context, customerID int, keepID int) error
+	Prune(ctx context.Context, customerID int, keep int) error
+	Rotate(ctx context.Context, id int, jti *uuid.UUID, patch map[string]interface{}) (bool, error)
 }
 
 type CustomerTokenRepository struct {
@@ -150,3 +155,66 @@ func (u *CustomerTokenRepository) Delete(ctx context.Context, filter map[string]
 
 	return tx.Commit().Error
 }
+
+// DeleteOthers deletes every token of the customer except the one with keepID.
+func (u *CustomerTokenRepository) DeleteOthers(ctx context.Context, customerID int, keepID int) error {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	return tx.Where(fmt.Sprintf("%s = ? AND %s <> ?", customerTokenDBModels.COLUMN_CUSTOMER_ID, customerTokenDBModels.COLUMN_ID), customerID, keepID).
+		Delete(&customerTokenDBModels.CustomerToken{}).Error
+}
+
+// Prune deletes the expired tokens of the customer and, when keep is positive, every
+// token but the keep most recently created ones.
+func (u *CustomerTokenRepository) Prune(ctx context.Context, customerID int, keep int) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where(fmt.Sprintf("%s = ? AND %s < ?", customerTokenDBModels.COLUMN_CUSTOMER_ID, customerTokenDBModels.COLUMN_REFRESH_TOKEN_EXPIRED_AT), customerID, time.Now()).
+		Delete(&customerTokenDBModels.CustomerToken{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if keep > 0 {
+		newest := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ? ORDER BY %s DESC, %s DESC LIMIT ?",
+			customerTokenDBModels.COLUMN_ID, tableName, customerTokenDBModels.COLUMN_CUSTOMER_ID, customerTokenDBModels.COLUMN_CREATED_AT, customerTokenDBModels.COLUMN_ID)
+
+		if err := tx.Where(fmt.Sprintf("%s = ? AND %s NOT IN (%s)", customerTokenDBModels.COLUMN_CUSTOMER_ID, customerTokenDBModels.COLUMN_ID, newest), customerID, customerID, keep).
+			Delete(&customerTokenDBModels.CustomerToken{}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// Rotate applies the patch to the token with the given id as long as its refresh token
+// still has the jti, and reports whether it did. A concurrent rotation makes it false.
+func (u *CustomerTokenRepository) Rotate(ctx context.Context, id int, jti *uuid.UUID, patch map[string]interface{}) (bool, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	tx = tx.Where(map[string]interface{}{customerTokenDBModels.COLUMN_ID: id})
+
+	if jti == nil {
+		tx = tx.Where(fmt.Sprintf("%s IS NULL", customerTokenDBModels.COLUMN_REFRESH_TOKEN_JTI))
+	} else {
+		tx = tx.Where(map[string]interface{}{customerTokenDBModels.COLUMN_REFRESH_TOKEN_JTI: *jti})
+	}
+
+	result := tx.Updates(patch)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,138 @@
+package customer_verification_token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	customerVerificationTokenDBModels "kredit-plus/app/db/dto/customer_verification_token"
+
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with customer verification token data.
+type ICustomerVerificationTokenRepository interface {
+	Create(ctx context.Context, customerVerificationToken *customerVerificationTokenDBModels.CustomerVerificationToken) error
+	Get(ctx context.Context, filter map[string]interface{}) (customerVerificationTokenDBModels.CustomerVerificationToken, error)
+	Latest(ctx context.Context, customerID int, purpose string) (customerVerificationTokenDBModels.CustomerVerificationToken, error)
+	CountSince(ctx context.Context, customerID int, purpose string, since time.Time) (int, error)
+	Use(ctx context.Context, id int, at time.Time) (bool, error)
+	Delete(ctx context.Context, filter map[string]interface{}) error
+}
+
+type CustomerVerificationTokenRepository struct {
+	DBService *db.DBService
+}
+
+// Constructor for creating a new CustomerVerificationTokenRepository.
+func NewCustomerVerificationTokenRepository(dbService *db.DBService) ICustomerVerificationTokenRepository {
+	return &CustomerVerificationTokenRepository{
+		DBService: dbService,
+	}
+}
+
+const tableName = customerVerificationTokenDBModels.TABLE_NAME
+
+// Create a new customerVerificationToken record.
+func (u *CustomerVerificationTokenRepository) Create(ctx context.Context, customerVerificationToken *customerVerificationTokenDBModels.CustomerVerificationToken) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(customerVerificationToken).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Retrieve a customerVerificationToken based on filter criteria.
+func (u *CustomerVerificationTokenRepository) Get(ctx context.Context, filter map[string]interface{}) (customerVerificationTokenDBModels.CustomerVerificationToken, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var customerVerificationToken customerVerificationTokenDBModels.CustomerVerificationToken
+
+	if err := tx.Where(filter).First(&customerVerificationToken).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customerVerificationToken, nil
+		}
+		return customerVerificationToken, err
+	}
+
+	return customerVerificationToken, nil
+}
+
+// Latest returns the most recently issued token of the customer for the purpose.
+func (u *CustomerVerificationTokenRepository) Latest(ctx context.Context, customerID int, purpose string) (customerVerificationTokenDBModels.CustomerVerificationToken, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var customerVerificationToken customerVerificationTokenDBModels.CustomerVerificationToken
+
+	err := tx.Where(map[string]interface{}{
+		customerVerificationTokenDBModels.COLUMN_CUSTOMER_ID: customerID,
+		customerVerificationTokenDBModels.COLUMN_PURPOSE:     purpose,
+	}).Order(fmt.Sprintf("%s DESC", customerVerificationTokenDBModels.COLUMN_CREATED_AT)).First(&customerVerificationToken).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customerVerificationToken, nil
+		}
+		return customerVerificationToken, err
+	}
+
+	return customerVerificationToken, nil
+}
+
+// CountSince counts the tokens issued to the customer for the purpose since the given time.
+func (u *CustomerVerificationTokenRepository) CountSince(ctx context.Context, customerID int, purpose string, since time.Time) (int, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var count int
+
+	err := tx.Where(fmt.Sprintf("%s = ? AND %s = ? AND %s >= ?", customerVerificationTokenDBModels.COLUMN_CUSTOMER_ID, customerVerificationTokenDBModels.COLUMN_PURPOSE, customerVerificationTokenDBModels.COLUMN_CREATED_AT), customerID, purpose, since).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Use marks the token as used. It reports false when the token was already used,
+// so concurrent requests cannot both consume the same token.
+func (u *CustomerVerificationTokenRepository) Use(ctx context.Context, id int, at time.Time) (bool, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	result := tx.Where(fmt.Sprintf("%s = ? AND %s IS NULL", customerVerificationTokenDBModels.COLUMN_ID, customerVerificationTokenDBModels.COLUMN_USED_AT), id).
+		Updates(map[string]interface{}{customerVerificationTokenDBModels.COLUMN_USED_AT: at})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// Delete customerVerificationToken records matching the filter.
+func (u *CustomerVerificationTokenRepository) Delete(ctx context.Context, filter map[string]interface{}) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where(filter).Delete(&customerVerificationTokenDBModels.CustomerVerificationToken{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
@@ -0,0 +1,151 @@
+package duplicate_match
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	duplicateMatchDBModels "kredit-plus/app/db/dto/duplicate_match"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/util"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with duplicate match data.
+type IDuplicateMatchRepository interface {
+	Create(ctx context.Context, duplicateMatch *duplicateMatchDBModels.DuplicateMatch) error
+	Get(ctx context.Context, filter map[string]interface{}) (duplicateMatchDBModels.DuplicateMatch, error)
+	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]duplicateMatchDBModels.DuplicateMatch, response.Pagination, error)
+	Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error
+	CountBlocking(ctx context.Context, customerID int) (int, error)
+}
+
+type DuplicateMatchRepository struct {
+	DBService *db.DBService
+}
+
+// Constructor for creating a new DuplicateMatchRepository.
+func NewDuplicateMatchRepository(dbService *db.DBService) IDuplicateMatchRepository {
+	return &DuplicateMatchRepository{
+		DBService: dbService,
+	}
+}
+
+const tableName = duplicateMatchDBModels.TABLE_NAME
+
+// Create a new duplicateMatch record.
+func (u *DuplicateMatchRepository) Create(ctx context.Context, duplicateMatch *duplicateMatchDBModels.DuplicateMatch) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(duplicateMatch).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Retrieve a duplicateMatch based on filter criteria.
+func (u *DuplicateMatchRepository) Get(ctx context.Context, filter map[string]interface{}) (duplicateMatchDBModels.DuplicateMatch, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var duplicateMatch duplicateMatchDBModels.DuplicateMatch
+
+	if err := tx.Where(filter).First(&duplicateMatch).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return duplicateMatch, nil
+		}
+		return duplicateMatch, err
+	}
+
+	return duplicateMatch, nil
+}
+
+// List duplicateMatches based on filtering and pagination criteria.
+func (u *DuplicateMatchRepository) List(ctx context.Context, paginationRequest request.Pagination, filter map[string]interface{}) (record []duplicateMatchDBModels.DuplicateMatch, paginationResponse response.Pagination, err error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	tx, err = util.ApplyFilterCondition(tx, filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
+	if err := tx.Count(&paginationResponse.TotalCount).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return nil, paginationResponse, err
+	}
+
+	if !paginationRequest.GetAllData {
+		offset := (*paginationRequest.Page - 1) * *paginationRequest.Limit
+		tx = tx.Limit(*paginationRequest.Limit).Offset(offset)
+		paginationResponse.Page = *paginationRequest.Page
+		paginationResponse.PerPage = *paginationRequest.Limit
+		paginationResponse.TotalPages = (paginationResponse.TotalCount + *paginationRequest.Limit - 1) / *paginationRequest.Limit
+	}
+
+	tx = tx.Order(fmt.Sprintf("%s %s", paginationRequest.Sort, paginationRequest.Order))
+
+	if err := tx.Find(&record).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return record, paginationResponse, err
+	}
+
+	return record, paginationResponse, nil
+}
+
+// Update duplicateMatch records based on filter criteria and a patch.
+func (u *DuplicateMatchRepository) Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where(filter).Updates(patch).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var duplicateMatch duplicateMatchDBModels.DuplicateMatch
+
+	if err := tx.Where(filter).First(&duplicateMatch).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CountBlocking counts the matches that block new limits of the customer: pending matches
+// of either customer, and confirmed matches in which the customer is the newer account.
+func (u *DuplicateMatchRepository) CountBlocking(ctx context.Context, customerID int) (int, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var count int
+
+	err := tx.Where(fmt.Sprintf("(%s = ? AND (%s = ? OR %s = ?)) OR (%s = ? AND %s = ?)",
+		duplicateMatchDBModels.COLUMN_STATUS, duplicateMatchDBModels.COLUMN_CUSTOMER_ID, duplicateMatchDBModels.COLUMN_MATCHED_CUSTOMER_ID,
+		duplicateMatchDBModels.COLUMN_STATUS, duplicateMatchDBModels.COLUMN_MATCHED_CUSTOMER_ID),
+		duplicateMatchDBModels.STATUS_PENDING, customerID, customerID,
+		duplicateMatchDBModels.STATUS_CONFIRMED, customerID).Count(&count).Error
+
+	return count, err
+}
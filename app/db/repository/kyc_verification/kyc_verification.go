@@ -0,0 +1,314 @@
+package kyc_verification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	kycVerificationDBModels "kredit-plus/app/db/dto/kyc_verification"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/encryption"
+	"kredit-plus/app/service/util"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with kyc verification data.
+type IKycVerificationRepository interface {
+	Create(ctx context.Context, kycVerification *kycVerificationDBModels.KycVerification) error
+	Get(ctx context.Context, filter map[string]interface{}) (kycVerificationDBModels.KycVerification, error)
+	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]kycVerificationDBModels.KycVerification, response.Pagination, error)
+	Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error
+	Transition(ctx context.Context, id int, from string, patch map[string]interface{}) (bool, error)
+	Reencrypt(ctx context.Context, afterID int, limit int) (int, int, error)
+}
+
+type KycVerificationRepository struct {
+	DBService *db.DBService
+	Cipher    *encryption.Cipher
+}
+
+// Constructor for creating a new KycVerificationRepository.
+func NewKycVerificationRepository(dbService *db.DBService, cipher *encryption.Cipher) IKycVerificationRepository {
+	return &KycVerificationRepository{
+		DBService: dbService,
+		Cipher:    cipher,
+	}
+}
+
+const tableName = kycVerificationDBModels.TABLE_NAME
+
+// encryptedColumns are stored encrypted; callers read and write them in plaintext.
+var encryptedColumns = map[string]bool{
+	kycVerificationDBModels.COLUMN_KTP_IMAGE:         true,
+	kycVerificationDBModels.COLUMN_SELFIE_IMAGE:      true,
+	kycVerificationDBModels.COLUMN_OCR_NIK:           true,
+	kycVerificationDBModels.COLUMN_OCR_NAME:          true,
+	kycVerificationDBModels.COLUMN_OCR_DATE_OF_BIRTH: true,
+}
+
+// Create a new kycVerification record.
+func (u *KycVerificationRepository) Create(ctx context.Context, kycVerification *kycVerificationDBModels.KycVerification) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	row := *kycVerification
+	if err := u.crypt(&row, u.Cipher.Encrypt); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Create(&row).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	kycVerification.ID = row.ID
+	kycVerification.CreatedAt = row.CreatedAt
+
+	return tx.Commit().Error
+}
+
+// Retrieve a kycVerification based on filter criteria.
+func (u *KycVerificationRepository) Get(ctx context.Context, filter map[string]interface{}) (kycVerificationDBModels.KycVerification, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := checkFilter(filter); err != nil {
+		return kycVerificationDBModels.KycVerification{}, err
+	}
+
+	var kycVerification kycVerificationDBModels.KycVerification
+
+	if err := tx.Where(filter).First(&kycVerification).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return kycVerification, nil
+		}
+		return kycVerification, err
+	}
+
+	if err := u.crypt(&kycVerification, u.Cipher.Decrypt); err != nil {
+		return kycVerification, err
+	}
+
+	return kycVerification, nil
+}
+
+// List kycVerifications based on filtering and pagination criteria.
+func (u *KycVerificationRepository) List(ctx context.Context, paginationRequest request.Pagination, filter map[string]interface{}) (record []kycVerificationDBModels.KycVerification, paginationResponse response.Pagination, err error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := checkFilter(filter); err != nil {
+		return nil, paginationResponse, err
+	}
+
+	tx, err = util.ApplyFilterCondition(tx, filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
+	if err := tx.Count(&paginationResponse.TotalCount).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return nil, paginationResponse, err
+	}
+
+	if !paginationRequest.GetAllData {
+		offset := (*paginationRequest.Page - 1) * *paginationRequest.Limit
+		tx = tx.Limit(*paginationRequest.Limit).Offset(offset)
+		paginationResponse.Page = *paginationRequest.Page
+		paginationResponse.PerPage = *paginationRequest.Limit
+		paginationResponse.TotalPages = (paginationResponse.TotalCount + *paginationRequest.Limit - 1) / *paginationRequest.Limit
+	}
+
+	tx = tx.Order(fmt.Sprintf("%s %s", paginationRequest.Sort, paginationRequest.Order))
+
+	if err := tx.Find(&record).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return record, paginationResponse, err
+	}
+
+	for i := range record {
+		if err := u.crypt(&record[i], u.Cipher.Decrypt); err != nil {
+			return nil, paginationResponse, err
+		}
+	}
+
+	return record, paginationResponse, nil
+}
+
+// Update kycVerification records based on filter criteria and a patch.
+func (u *KycVerificationRepository) Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error {
+	if err := checkFilter(filter); err != nil {
+		return err
+	}
+
+	patch, err := u.encryptPatch(patch)
+	if err != nil {
+		return err
+	}
+
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where(filter).Updates(patch).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var kycVerification kycVerificationDBModels.KycVerification
+
+	if err := tx.Where(filter).First(&kycVerification).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Transition applies the patch to the verification if it still has the from status.
+// It reports false when the verification was moved on in the meantime, so concurrent
+// reviews cannot both decide the same verification.
+func (u *KycVerificationRepository) Transition(ctx context.Context, id int, from string, patch map[string]interface{}) (bool, error) {
+	patch, err := u.encryptPatch(patch)
+	if err != nil {
+		return false, err
+	}
+
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	result := tx.Where(fmt.Sprintf("%s = ? AND %s = ?", kycVerificationDBModels.COLUMN_ID, kycVerificationDBModels.COLUMN_STATUS), id, from).
+		Updates(patch)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// Reencrypt encrypts up to limit verifications after afterID with the current key,
+// including rows still in plaintext. It returns the last ID read and the number of
+// rows rewritten.
+func (u *KycVerificationRepository) Reencrypt(ctx context.Context, afterID int, limit int) (int, int, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var rows []kycVerificationDBModels.KycVerification
+
+	if err := tx.Where(fmt.Sprintf("%s > ?", kycVerificationDBModels.COLUMN_ID), afterID).
+		Order(kycVerificationDBModels.COLUMN_ID).Limit(limit).Find(&rows).Error; err != nil {
+		return afterID, 0, err
+	}
+
+	rewritten := 0
+
+	for _, row := range rows {
+		afterID = row.ID
+
+		if !u.needsRotation(row) {
+			continue
+		}
+
+		if err := u.crypt(&row, u.Cipher.Decrypt); err != nil {
+			return afterID, rewritten, fmt.Errorf("kyc verification %d: %w", row.ID, err)
+		}
+
+		if err := u.crypt(&row, u.Cipher.Encrypt); err != nil {
+			return afterID, rewritten, err
+		}
+
+		patch := map[string]interface{}{
+			kycVerificationDBModels.COLUMN_KTP_IMAGE:         row.KtpImage,
+			kycVerificationDBModels.COLUMN_SELFIE_IMAGE:      row.SelfieImage,
+			kycVerificationDBModels.COLUMN_OCR_NIK:           row.OcrNIK,
+			kycVerificationDBModels.COLUMN_OCR_NAME:          row.OcrName,
+			kycVerificationDBModels.COLUMN_OCR_DATE_OF_BIRTH: row.OcrDateOfBirth,
+		}
+
+		if err := u.DBService.GetDB().Table(tableName).Where(fmt.Sprintf("%s = ?", kycVerificationDBModels.COLUMN_ID), row.ID).UpdateColumns(patch).Error; err != nil {
+			return afterID, rewritten, err
+		}
+
+		rewritten++
+	}
+
+	return afterID, rewritten, nil
+}
+
+func (u *KycVerificationRepository) needsRotation(row kycVerificationDBModels.KycVerification) bool {
+	for _, value := range []string{row.KtpImage, row.SelfieImage, row.OcrNIK, row.OcrName, row.OcrDateOfBirth} {
+		if u.Cipher.NeedsRotation(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// crypt applies an Encrypt or Decrypt of the cipher to every encrypted field in place.
+func (u *KycVerificationRepository) crypt(kycVerification *kycVerificationDBModels.KycVerification, apply func(value string, field string) (string, error)) error {
+	fields := map[string]*string{
+		kycVerificationDBModels.COLUMN_KTP_IMAGE:         &kycVerification.KtpImage,
+		kycVerificationDBModels.COLUMN_SELFIE_IMAGE:      &kycVerification.SelfieImage,
+		kycVerificationDBModels.COLUMN_OCR_NIK:           &kycVerification.OcrNIK,
+		kycVerificationDBModels.COLUMN_OCR_NAME:          &kycVerification.OcrName,
+		kycVerificationDBModels.COLUMN_OCR_DATE_OF_BIRTH: &kycVerification.OcrDateOfBirth,
+	}
+
+	for column, value := range fields {
+		result, err := apply(*value, column)
+		if err != nil {
+			return err
+		}
+		*value = result
+	}
+
+	return nil
+}
+
+// encryptPatch encrypts the values of encrypted columns, keeping nil as NULL.
+func (u *KycVerificationRepository) encryptPatch(patch map[string]interface{}) (map[string]interface{}, error) {
+	encrypted := make(map[string]interface{}, len(patch))
+
+	for column, value := range patch {
+		plaintext, ok := value.(string)
+		if !encryptedColumns[column] || !ok {
+			encrypted[column] = value
+			continue
+		}
+
+		ciphertext, err := u.Cipher.Encrypt(plaintext, column)
+		if err != nil {
+			return nil, err
+		}
+		encrypted[column] = ciphertext
+	}
+
+	return encrypted, nil
+}
+
+// checkFilter rejects filters on encrypted columns, which cannot be matched in the database.
+func checkFilter(filter map[string]interface{}) error {
+	for column := range filter {
+		if encryptedColumns[column] {
+			return fmt.Errorf("cannot filter on encrypted column %s", column)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package limit_increase_request
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	limitIncreaseRequestDBModels "kredit-plus/app/db/dto/limit_increase_request"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/util"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with limit increase request data.
+type ILimitIncreaseRequestRepository interface {
+	Create(ctx context.Context, limitIncreaseRequest *limitIncreaseRequestDBModels.LimitIncreaseRequest) error
+	Get(ctx context.Context, filter map[string]interface{}) (limitIncreaseRequestDBModels.LimitIncreaseRequest, error)
+	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]limitIncreaseRequestDBModels.LimitIncreaseRequest, response.Pagination, error)
+	Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error
+	Transition(ctx context.Context, id int, from []string, patch map[string]interface{}) (bool, error)
+}
+
+type LimitIncreaseRequestRepository struct {
+	DBService *db.DBService
+}
+
+// Constructor for creating a new LimitIncreaseRequestRepository.
+func NewLimitIncreaseRequestRepository(dbService *db.DBService) ILimitIncreaseRequestRepository {
+	return &LimitIncreaseRequestRepository{
+		DBService: dbService,
+	}
+}
+
+const tableName = limitIncreaseRequestDBModels.TABLE_NAME
+
+// Create a new limitIncreaseRequest record.
+func (u *LimitIncreaseRequestRepository) Create(ctx context.Context, limitIncreaseRequest *limitIncreaseRequestDBModels.LimitIncreaseRequest) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(limitIncreaseRequest).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Retrieve a limitIncreaseRequest based on filter criteria.
+func (u *LimitIncreaseRequestRepository) Get(ctx context.Context, filter map[string]interface{}) (limitIncreaseRequestDBModels.LimitIncreaseRequest, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var limitIncreaseRequest limitIncreaseRequestDBModels.LimitIncreaseRequest
+
+	if err := tx.Where(filter).First(&limitIncreaseRequest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return limitIncreaseRequest, nil
+		}
+		return limitIncreaseRequest, err
+	}
+
+	return limitIncreaseRequest, nil
+}
+
+// List limitIncreaseRequests based on filtering and pagination criteria.
+func (u *LimitIncreaseRequestRepository) List(ctx context.Context, paginationRequest request.Pagination, filter map[string]interface{}) (record []limitIncreaseRequestDBModels.LimitIncreaseRequest, paginationResponse response.Pagination, err error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	tx, err = util.ApplyFilterCondition(tx, filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
+	if err := tx.Count(&paginationResponse.TotalCount).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return nil, paginationResponse, err
+	}
+
+	if !paginationRequest.GetAllData {
+		offset := (*paginationRequest.Page - 1) * *paginationRequest.Limit
+		tx = tx.Limit(*paginationRequest.Limit).Offset(offset)
+		paginationResponse.Page = *paginationRequest.Page
+		paginationResponse.PerPage = *paginationRequest.Limit
+		paginationResponse.TotalPages = (paginationResponse.TotalCount + *paginationRequest.Limit - 1) / *paginationRequest.Limit
+	}
+
+	tx = tx.Order(fmt.Sprintf("%s %s", paginationRequest.Sort, paginationRequest.Order))
+
+	if err := tx.Find(&record).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return record, paginationResponse, err
+	}
+
+	return record, paginationResponse, nil
+}
+
+// Update limitIncreaseRequest records based on filter criteria and a patch.
+func (u *LimitIncreaseRequestRepository) Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where(filter).Updates(patch).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var limitIncreaseRequest limitIncreaseRequestDBModels.LimitIncreaseRequest
+
+	if err := tx.Where(filter).First(&limitIncreaseRequest).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Transition applies the patch to the request if it still has one of the from statuses.
+// It reports false when the request was moved on in the meantime, so concurrent reviews
+// cannot both decide the same request.
+func (u *LimitIncreaseRequestRepository) Transition(ctx context.Context, id int, from []string, patch map[string]interface{}) (bool, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	result := tx.Where(fmt.Sprintf("%s = ? AND %s IN (?)", limitIncreaseRequestDBModels.COLUMN_ID, limitIncreaseRequestDBModels.COLUMN_STATUS), id, from).
+		Updates(patch)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,102 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	roleDBModels "kredit-plus/app/db/dto/role"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/util"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with role data.
+type IRoleRepository interface {
+	Create(ctx context.Context, role *roleDBModels.Role) error
+	Get(ctx context.Context, filter map[string]interface{}) (roleDBModels.Role, error)
+	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]roleDBModels.Role, response.Pagination, error)
+}
+
+type RoleRepository struct {
+	DBService *db.DBService
+}
+
+// Constructor for creating a new RoleRepository.
+func NewRoleRepository(dbService *db.DBService) IRoleRepository {
+	return &RoleRepository{
+		DBService: dbService,
+	}
+}
+
+const tableName = roleDBModels.TABLE_NAME
+
+// Create a new role record.
+func (u *RoleRepository) Create(ctx context.Context, role *roleDBModels.Role) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(role).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Retrieve a role based on filter criteria.
+func (u *RoleRepository) Get(ctx context.Context, filter map[string]interface{}) (roleDBModels.Role, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var role roleDBModels.Role
+
+	if err := tx.Where(filter).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return role, nil
+		}
+		return role, err
+	}
+
+	return role, nil
+}
+
+// List roles based on filtering and pagination criteria.
+func (u *RoleRepository) List(ctx context.Context, paginationRequest request.Pagination, filter map[string]interface{}) (record []roleDBModels.Role, paginationResponse response.Pagination, err error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	tx, err = util.ApplyFilterCondition(tx, filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
+	if err := tx.Count(&paginationResponse.TotalCount).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return nil, paginationResponse, err
+	}
+
+	if !paginationRequest.GetAllData {
+		offset := (*paginationRequest.Page - 1) * *paginationRequest.Limit
+		tx = tx.Limit(*paginationRequest.Limit).Offset(offset)
+		paginationResponse.Page = *paginationRequest.Page
+		paginationResponse.PerPage = *paginationRequest.Limit
+		paginationResponse.TotalPages = (paginationResponse.TotalCount + *paginationRequest.Limit - 1) / *paginationRequest.Limit
+	}
+
+	tx = tx.Order(fmt.Sprintf("%s %s", paginationRequest.Sort, paginationRequest.Order))
+
+	if err := tx.Find(&record).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return record, paginationResponse, err
+	}
+
+	return record, paginationResponse, nil
+}
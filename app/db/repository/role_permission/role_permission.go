@@ -0,0 +1,122 @@
+package role_permission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	rolePermissionDBModels "kredit-plus/app/db/dto/role_permission"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/util"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with role permission data.
+type IRolePermissionRepository interface {
+	Create(ctx context.Context, rolePermission *rolePermissionDBModels.RolePermission) error
+	Get(ctx context.Context, filter map[string]interface{}) (rolePermissionDBModels.RolePermission, error)
+	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]rolePermissionDBModels.RolePermission, response.Pagination, error)
+	Delete(ctx context.Context, filter map[string]interface{}) error
+}
+
+type RolePermissionRepository struct {
+	DBService *db.DBService
+}
+
+// Constructor for creating a new RolePermissionRepository.
+func NewRolePermissionRepository(dbService *db.DBService) IRolePermissionRepository {
+	return &RolePermissionRepository{
+		DBService: dbService,
+	}
+}
+
+const tableName = rolePermissionDBModels.TABLE_NAME
+
+// Create a new rolePermission record.
+func (u *RolePermissionRepository) Create(ctx context.Context, rolePermission *rolePermissionDBModels.RolePermission) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(rolePermission).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Retrieve a rolePermission based on filter criteria.
+func (u *RolePermissionRepository) Get(ctx context.Context, filter map[string]interface{}) (rolePermissionDBModels.RolePermission, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var rolePermission rolePermissionDBModels.RolePermission
+
+	if err := tx.Where(filter).First(&rolePermission).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return rolePermission, nil
+		}
+		return rolePermission, err
+	}
+
+	return rolePermission, nil
+}
+
+// List rolePermissions based on filtering and pagination criteria.
+func (u *RolePermissionRepository) List(ctx context.Context, paginationRequest request.Pagination, filter map[string]interface{}) (record []rolePermissionDBModels.RolePermission, paginationResponse response.Pagination, err error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	tx, err = util.ApplyFilterCondition(tx, filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
+	if err := tx.Count(&paginationResponse.TotalCount).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return nil, paginationResponse, err
+	}
+
+	if !paginationRequest.GetAllData {
+		offset := (*paginationRequest.Page - 1) * *paginationRequest.Limit
+		tx = tx.Limit(*paginationRequest.Limit).Offset(offset)
+		paginationResponse.Page = *paginationRequest.Page
+		paginationResponse.PerPage = *paginationRequest.Limit
+		paginationResponse.TotalPages = (paginationResponse.TotalCount + *paginationRequest.Limit - 1) / *paginationRequest.Limit
+	}
+
+	tx = tx.Order(fmt.Sprintf("%s %s", paginationRequest.Sort, paginationRequest.Order))
+
+	if err := tx.Find(&record).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return record, paginationResponse, err
+	}
+
+	return record, paginationResponse, nil
+}
+
+// Delete rolePermission records based on filter criteria.
+func (u *RolePermissionRepository) Delete(ctx context.Context, filter map[string]interface{}) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where(filter).Delete(&rolePermissionDBModels.RolePermission{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
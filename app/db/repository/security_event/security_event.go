@@ -0,0 +1,102 @@
+package security_event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	securityEventDBModels "kredit-plus/app/db/dto/security_event"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/util"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Interface methods for interacting with security event data.
+type ISecurityEventRepository interface {
+	Create(ctx context.Context, securityEvent *securityEventDBModels.SecurityEvent) error
+	Get(ctx context.Context, filter map[string]interface{}) (securityEventDBModels.SecurityEvent, error)
+	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]securityEventDBModels.SecurityEvent, response.Pagination, error)
+}
+
+type SecurityEventRepository struct {
+	DBService *db.DBService
+}
+
+// Constructor for creating a new SecurityEventRepository.
+func NewSecurityEventRepository(dbService *db.DBService) ISecurityEventRepository {
+	return &SecurityEventRepository{
+		DBService: dbService,
+	}
+}
+
+const tableName = securityEventDBModels.TABLE_NAME
+
+// Create a new securityEvent record.
+func (u *SecurityEventRepository) Create(ctx context.Context, securityEvent *securityEventDBModels.SecurityEvent) error {
+	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	if err := tx.Create(securityEvent).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Retrieve a securityEvent based on filter criteria.
+func (u *SecurityEventRepository) Get(ctx context.Context, filter map[string]interface{}) (securityEventDBModels.SecurityEvent, error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	var securityEvent securityEventDBModels.SecurityEvent
+
+	if err := tx.Where(filter).First(&securityEvent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return securityEvent, nil
+		}
+		return securityEvent, err
+	}
+
+	return securityEvent, nil
+}
+
+// List securityEvents based on filtering and pagination criteria.
+func (u *SecurityEventRepository) List(ctx context.Context, paginationRequest request.Pagination, filter map[string]interface{}) (record []securityEventDBModels.SecurityEvent, paginationResponse response.Pagination, err error) {
+	tx := u.DBService.GetDB().Table(tableName)
+	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
+
+	tx, err = util.ApplyFilterCondition(tx, filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
+	if err := tx.Count(&paginationResponse.TotalCount).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return nil, paginationResponse, err
+	}
+
+	if !paginationRequest.GetAllData {
+		offset := (*paginationRequest.Page - 1) * *paginationRequest.Limit
+		tx = tx.Limit(*paginationRequest.Limit).Offset(offset)
+		paginationResponse.Page = *paginationRequest.Page
+		paginationResponse.PerPage = *paginationRequest.Limit
+		paginationResponse.TotalPages = (paginationResponse.TotalCount + *paginationRequest.Limit - 1) / *paginationRequest.Limit
+	}
+
+	tx = tx.Order(fmt.Sprintf("%s %s", paginationRequest.Sort, paginationRequest.Order))
+
+	if err := tx.Find(&record).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, paginationResponse, nil
+		}
+		return record, paginationResponse, err
+	}
+
+	return record, paginationResponse, nil
+}
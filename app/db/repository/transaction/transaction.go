@@ -9,6 +9,7 @@ import (
 	transactions_DBModels "kredit-plus/app/db/dto/transaction"
 	"kredit-plus/app/service/dto/request"
 	"kredit-plus/app/service/dto/response"
+	"kredit-plus/app/service/scope"
 	"kredit-plus/app/service/util"
 
 	"github.com/jinzhu/gorm"
@@ -21,23 +22,66 @@ type ITransactionRepository interface {
 	List(ctx context.Context, pagination request.Pagination, filter map[string]interface{}) ([]transactions_DBModels.Transaction, response.Pagination, error)
 	Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error
 	Delete(ctx context.Context, filter map[string]interface{}) error
+	Scoped(s scope.Scope) ITransactionRepository
 }
 
 type TransactionRepository struct {
 	DBService *db.DBService
+	Scope     scope.Scope
 }
 
 // Constructor for creating a new TransactionRepository.
 func NewTransactionRepository(dbService *db.DBService) ITransactionRepository {
 	return &TransactionRepository{
 		DBService: dbService,
+		Scope:     scope.ALL,
 	}
 }
 
 var tableName = transactions_DBModels.TABLE_NAME
 
+// Scoped returns a repository whose queries only reach the transactions within the scope.
+// Records outside of it are treated as if they did not exist.
+func (u *TransactionRepository) Scoped(s scope.Scope) ITransactionRepository {
+	return &TransactionRepository{
+		DBService: u.DBService,
+		Scope:     s,
+	}
+}
+
+// table starts a query on the transactions within the scope of the repository.
+func (u *TransactionRepository) table() *gorm.DB {
+	tx := u.DBService.GetDB().Table(tableName)
+
+	if u.Scope.IsEmpty() {
+		return tx.Where("1 = 0")
+	}
+
+	if u.Scope.CustomerID != 0 {
+		tx = tx.Where(map[string]interface{}{transactions_DBModels.COLUMN_CUSTOMER_ID: u.Scope.CustomerID})
+	}
+
+	if u.Scope.SalesChannel != "" {
+		tx = tx.Where(map[string]interface{}{transactions_DBModels.COLUMN_SALES_CHANNEL: u.Scope.SalesChannel})
+	}
+
+	return tx
+}
+
 // Create a new transaction record.
 func (u *TransactionRepository) Create(ctx context.Context, transaction *transactions_DBModels.Transaction) error {
+	if u.Scope.IsEmpty() {
+		return scope.ErrEmptyScope
+	}
+
+	if u.Scope.CustomerID != 0 {
+		transaction.CustomerID = u.Scope.CustomerID
+	}
+
+	if u.Scope.SalesChannel != "" {
+		transaction.SalesChannel = u.Scope.SalesChannel
+	}
+
 	tx := u.DBService.GetDB().Table(tableName).Begin()
 	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
 
@@ -51,7 +95,7 @@ func (u *TransactionRepository) Create(ctx context.Context, transaction *transac
 
 // Retrieve a transaction based on filter criteria.
 func (u *TransactionRepository) Get(ctx context.Context, filter map[string]interface{}) (transactions_DBModels.Transaction, error) {
-	tx := u.DBService.GetDB().Table(tableName)
+	tx := u.table()
 	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
 
 	var transaction transactions_DBModels.Transaction
@@ -68,7 +112,7 @@ func (u *TransactionRepository) Get(ctx context.Context, filter map[string]inter
 
 // List transactions based on filtering and pagination criteria.
 func (u *TransactionRepository) List(ctx context.Context, paginationRequest request.Pagination, filter map[string]interface{}) (record []transactions_DBModels.Transaction, paginationResponse response.Pagination, err error) {
-	tx := u.DBService.GetDB().Table(tableName)
+	tx := u.table()
 	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
 
 	tx, err = util.ApplyFilterCondition(tx, filter)
@@ -105,7 +149,7 @@ func (u *TransactionRepository) List(ctx context.Context, paginationRequest requ
 
 // Update transaction records based on filter criteria and a patch.
 func (u *TransactionRepository) Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}) error {
-	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx := u.table().Begin()
 	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
 
 	defer func() {
@@ -135,7 +179,7 @@ func (u *TransactionRepository) Update(ctx context.Context, filter map[string]in
 
 // Delete transaction records based on filter criteria.
 func (u *TransactionRepository) Delete(ctx context.Context, filter map[string]interface{}) error {
-	tx := u.DBService.GetDB().Table(tableName).Begin()
+	tx := u.table().Begin()
 	tx.LogMode(constants.Config.DatabaseConfig.DB_LOG_MODE)
 
 	defer func() {
@@ -0,0 +1,241 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	transactions_DBModels "kredit-plus/app/db/dto/transaction"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/scope"
+	"kredit-plus/config"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+// newTestRepository returns a repository on an in-memory database holding one transaction
+// of customer 1 sold through channel A and one of customer 2 sold through channel B.
+func newTestRepository(t *testing.T) (ITransactionRepository, transactions_DBModels.Transaction, transactions_DBModels.Transaction) {
+	t.Helper()
+
+	constants.Config = &config.ServiceConfig{}
+
+	conn, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	// Every connection to :memory: opens a database of its own
+	conn.DB().SetMaxOpenConns(1)
+
+	err = conn.Exec(`CREATE TABLE transactions (
+		id integer PRIMARY KEY AUTOINCREMENT,
+		uuid varchar(36) NOT NULL,
+		customer_id integer NOT NULL,
+		asset_id integer,
+		contract_number varchar(255),
+		otr_amount real,
+		admin_fee real,
+		installment_amount real,
+		installment_period integer,
+		interest_amount real,
+		sales_channel varchar(255),
+		status varchar(50),
+		created_at datetime,
+		updated_at datetime
+	)`).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repository := NewTransactionRepository(db.New(conn))
+
+	own := newTestTransaction(1, "A")
+	other := newTestTransaction(2, "B")
+
+	for _, transaction := range []*transactions_DBModels.Transaction{&own, &other} {
+		if err := repository.Create(context.Background(), transaction); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return repository, own, other
+}
+
+func newTestTransaction(customerID int, salesChannel string) transactions_DBModels.Transaction {
+	now := time.Now()
+
+	return transactions_DBModels.Transaction{
+		UUID:              uuid.New(),
+		CustomerID:        customerID,
+		ContractNumber:    uuid.NewString(),
+		OTRAmount:         1000,
+		AdminFee:          10,
+		InstallmentAmount: 100,
+		InstallmentPeriod: 12,
+		SalesChannel:      salesChannel,
+		Status:            transactions_DBModels.STATUS_ACTIVE,
+		CreatedAt:         now,
+		UpdatedAt:         &now,
+	}
+}
+
+func TestScopedGet(t *testing.T) {
+	repository, own, other := newTestRepository(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		scope scope.Scope
+		uuid  uuid.UUID
+		found bool
+	}{
+		{"customer reads own transaction", scope.Scope{CustomerID: 1}, own.UUID, true},
+		{"customer reads transaction of another customer", scope.Scope{CustomerID: 1}, other.UUID, false},
+		{"partner reads transaction of own channel", scope.Scope{SalesChannel: "B"}, other.UUID, true},
+		{"partner reads transaction of another channel", scope.Scope{SalesChannel: "B"}, own.UUID, false},
+		{"admin reads any transaction", scope.ALL, other.UUID, true},
+		{"empty scope reads nothing", scope.Scope{}, own.UUID, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction, err := repository.Scoped(tt.scope).Get(ctx, map[string]interface{}{transactions_DBModels.COLUMN_UUID: tt.uuid})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if found := transaction.UUID == tt.uuid; found != tt.found {
+				t.Errorf("found = %t, want %t", found, tt.found)
+			}
+		})
+	}
+}
+
+func TestScopedList(t *testing.T) {
+	repository, own, _ := newTestRepository(t)
+
+	pagination := request.Pagination{}
+	pagination.Validate()
+
+	// A customer_id filter only narrows the scope down
+	transactions, _, err := repository.Scoped(scope.Scope{CustomerID: 1}).List(context.Background(), pagination, map[string]interface{}{transactions_DBModels.COLUMN_CUSTOMER_ID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(transactions) != 0 {
+		t.Errorf("listed %d transactions of another customer", len(transactions))
+	}
+
+	transactions, _, err = repository.Scoped(scope.Scope{CustomerID: 1}).List(context.Background(), pagination, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(transactions) != 1 || transactions[0].UUID != own.UUID {
+		t.Errorf("listed %v, want only the own transaction", transactions)
+	}
+}
+
+func TestScopedUpdate(t *testing.T) {
+	repository, _, other := newTestRepository(t)
+	ctx := context.Background()
+	filter := map[string]interface{}{transactions_DBModels.COLUMN_UUID: other.UUID}
+
+	patch := map[string]interface{}{transactions_DBModels.COLUMN_STATUS: transactions_DBModels.STATUS_CANCELLED}
+
+	if err := repository.Scoped(scope.Scope{SalesChannel: "A"}).Update(ctx, filter, patch); err == nil {
+		t.Error("updated a transaction of another channel")
+	}
+
+	transaction, err := repository.Get(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if transaction.Status != transactions_DBModels.STATUS_ACTIVE {
+		t.Errorf("status = %s, want %s", transaction.Status, transactions_DBModels.STATUS_ACTIVE)
+	}
+}
+
+func TestScopedDelete(t *testing.T) {
+	repository, _, other := newTestRepository(t)
+	ctx := context.Background()
+	filter := map[string]interface{}{transactions_DBModels.COLUMN_UUID: other.UUID}
+
+	if err := repository.Scoped(scope.Scope{SalesChannel: "A"}).Delete(ctx, filter); err != nil {
+		t.Fatal(err)
+	}
+
+	transaction, err := repository.Get(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if transaction.UUID != other.UUID {
+		t.Error("deleted a transaction of another channel")
+	}
+}
+
+func TestEmptyScopeReachesNothing(t *testing.T) {
+	repository, own, _ := newTestRepository(t)
+	ctx := context.Background()
+	filter := map[string]interface{}{transactions_DBModels.COLUMN_UUID: own.UUID}
+
+	pagination := request.Pagination{}
+	pagination.Validate()
+
+	transactions, _, err := repository.Scoped(scope.Scope{}).List(ctx, pagination, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(transactions) != 0 {
+		t.Errorf("listed %d transactions within an empty scope", len(transactions))
+	}
+
+	patch := map[string]interface{}{transactions_DBModels.COLUMN_STATUS: transactions_DBModels.STATUS_CANCELLED}
+
+	if err := repository.Scoped(scope.Scope{}).Update(ctx, filter, patch); err == nil {
+		t.Error("updated a transaction within an empty scope")
+	}
+
+	if err := repository.Scoped(scope.Scope{}).Delete(ctx, filter); err != nil {
+		t.Fatal(err)
+	}
+
+	transaction, err := repository.Get(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if transaction.Status != transactions_DBModels.STATUS_ACTIVE {
+		t.Errorf("status = %s, want %s", transaction.Status, transactions_DBModels.STATUS_ACTIVE)
+	}
+
+	created := newTestTransaction(1, "A")
+
+	if err := repository.Scoped(scope.Scope{}).Create(ctx, &created); err == nil {
+		t.Error("created a transaction within an empty scope")
+	}
+}
+
+func TestScopedCreate(t *testing.T) {
+	repository, _, _ := newTestRepository(t)
+
+	transaction := newTestTransaction(2, "B")
+
+	if err := repository.Scoped(scope.Scope{SalesChannel: "A"}).Create(context.Background(), &transaction); err != nil {
+		t.Fatal(err)
+	}
+
+	if transaction.SalesChannel != "A" {
+		t.Errorf("sales channel = %s, want the channel of the scope", transaction.SalesChannel)
+	}
+}
@@ -0,0 +1,80 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerStatusHistoryDBModels "kredit-plus/app/db/dto/customer_status_history"
+	customerTokenDBModels "kredit-plus/app/db/dto/customer_token"
+	customerDB "kredit-plus/app/db/repository/customer"
+	customerStatusHistoryDB "kredit-plus/app/db/repository/customer_status_history"
+	customerTokenDB "kredit-plus/app/db/repository/customer_token"
+	"kredit-plus/app/service/correlation"
+)
+
+// IAccountService changes the status of customer accounts and records every change.
+type IAccountService interface {
+	ChangeStatus(ctx context.Context, customer customerDBModels.Customer, status string, reason string, actor string) (customerDBModels.Customer, error)
+}
+
+type AccountService struct {
+	CustomerDBClient              customerDB.ICustomerRepository
+	CustomerTokenDBClient         customerTokenDB.ICustomerTokenRepository
+	CustomerStatusHistoryDBClient customerStatusHistoryDB.ICustomerStatusHistoryRepository
+}
+
+func NewAccountService(CustomerClient customerDB.ICustomerRepository, CustomerTokenClient customerTokenDB.ICustomerTokenRepository, CustomerStatusHistoryClient customerStatusHistoryDB.ICustomerStatusHistoryRepository) *AccountService {
+	return &AccountService{
+		CustomerDBClient:              CustomerClient,
+		CustomerTokenDBClient:         CustomerTokenClient,
+		CustomerStatusHistoryDBClient: CustomerStatusHistoryClient,
+	}
+}
+
+// ChangeStatus moves the account to the given status. Leaving the active status
+// revokes every token of the customer so existing sessions end immediately.
+func (s *AccountService) ChangeStatus(ctx context.Context, customer customerDBModels.Customer, status string, reason string, actor string) (customerDBModels.Customer, error) {
+	now := time.Now()
+
+	filter := map[string]interface{}{
+		customerDBModels.COLUMN_ID: customer.ID,
+	}
+
+	patcher := map[string]interface{}{
+		customerDBModels.COLUMN_STATUS:            status,
+		customerDBModels.COLUMN_STATUS_REASON:     reason,
+		customerDBModels.COLUMN_STATUS_CHANGED_AT: now,
+		customerDBModels.COLUMN_UPDATED_AT:        now,
+	}
+
+	if err := s.CustomerDBClient.Update(ctx, filter, patcher); err != nil {
+		return customer, err
+	}
+
+	if status != customerDBModels.STATUS_ACTIVE {
+		if err := s.CustomerTokenDBClient.Delete(ctx, map[string]interface{}{customerTokenDBModels.COLUMN_CUSTOMER_ID: customer.ID}); err != nil {
+			return customer, err
+		}
+	}
+
+	history := customerStatusHistoryDBModels.CustomerStatusHistory{
+		CustomerID:    customer.ID,
+		OldStatus:     customer.Status,
+		NewStatus:     status,
+		Actor:         actor,
+		Reason:        reason,
+		CorrelationID: correlation.ContextCorrelationId(ctx),
+		CreatedAt:     now,
+	}
+
+	if err := history.Validate(); err != nil {
+		return customer, err
+	}
+
+	if err := s.CustomerStatusHistoryDBClient.Create(ctx, &history); err != nil {
+		return customer, err
+	}
+
+	return s.CustomerDBClient.Get(ctx, filter)
+}
@@ -0,0 +1,265 @@
+package credit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"kredit-plus/app/constants"
+	creditScoreDBModels "kredit-plus/app/db/dto/credit_score"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerLimitDBModels "kredit-plus/app/db/dto/customer_limit"
+	customerProfileDBModels "kredit-plus/app/db/dto/customer_profile"
+	creditScoreDB "kredit-plus/app/db/repository/credit_score"
+	customerDB "kredit-plus/app/db/repository/customer"
+	customerLimitDB "kredit-plus/app/db/repository/customer_limit"
+	duplicateMatchDB "kredit-plus/app/db/repository/duplicate_match"
+	limitService "kredit-plus/app/service/limit"
+	"kredit-plus/app/service/scoring"
+
+	"github.com/jinzhu/gorm/dialects/postgres"
+)
+
+var (
+	// ErrNotVerified is returned when limits are applied to a customer whose identity is not verified.
+	ErrNotVerified = errors.New(constants.KYC_NOT_VERIFIED)
+	// ErrDuplicateReview is returned when a limit would be granted or raised while the
+	// customer has possible duplicate identities waiting for review.
+	ErrDuplicateReview = errors.New(constants.DUPLICATE_REVIEW_PENDING)
+)
+
+// ICreditService scores customers and turns the decision into customer limits.
+type ICreditService interface {
+	Score(ctx context.Context, profile customerProfileDBModels.CustomerProfile) (scoring.Decision, creditScoreDBModels.CreditScore, error)
+	ApplyLimits(ctx context.Context, customerID int, limits map[int]float32, actor string, reason string) error
+	AssignLimits(ctx context.Context, profile customerProfileDBModels.CustomerProfile, actor string) error
+	RescoreLimits(ctx context.Context, profile customerProfileDBModels.CustomerProfile, actor string) error
+	GrantedLimit(ctx context.Context, customerID int, tenor int) (float32, error)
+	Tenors() []int
+	CapLimit(limit float32) float32
+}
+
+type CreditService struct {
+	ScoringEngine         scoring.IScoringEngine
+	CreditScoreDBClient   creditScoreDB.ICreditScoreRepository
+	CustomerDBClient      customerDB.ICustomerRepository
+	CustomerLimitDBClient customerLimitDB.ICustomerLimitRepository
+	LimitService          limitService.ILimitService
+
+	DuplicateMatchDBClient duplicateMatchDB.IDuplicateMatchRepository
+}
+
+func NewCreditService(ScoringEngine scoring.IScoringEngine, CreditScoreClient creditScoreDB.ICreditScoreRepository, CustomerClient customerDB.ICustomerRepository, CustomerLimitClient customerLimitDB.ICustomerLimitRepository, LimitService limitService.ILimitService, DuplicateMatchClient duplicateMatchDB.IDuplicateMatchRepository) *CreditService {
+	return &CreditService{
+		ScoringEngine:         ScoringEngine,
+		CreditScoreDBClient:   CreditScoreClient,
+		CustomerDBClient:      CustomerClient,
+		CustomerLimitDBClient: CustomerLimitClient,
+		LimitService:          LimitService,
+
+		DuplicateMatchDBClient: DuplicateMatchClient,
+	}
+}
+
+// Score evaluates the profile and stores the score with its explanation and the
+// version of the profile it was based on for audit.
+func (s *CreditService) Score(ctx context.Context, profile customerProfileDBModels.CustomerProfile) (scoring.Decision, creditScoreDBModels.CreditScore, error) {
+	decision, err := s.ScoringEngine.Evaluate(ctx, profile)
+	if err != nil {
+		return decision, creditScoreDBModels.CreditScore{}, err
+	}
+
+	explanation, err := json.Marshal(decision)
+	if err != nil {
+		return decision, creditScoreDBModels.CreditScore{}, err
+	}
+
+	creditScore := creditScoreDBModels.CreditScore{
+		CustomerID:     profile.CustomerID,
+		Scorecard:      decision.Scorecard,
+		Score:          decision.Score,
+		Grade:          decision.Grade,
+		Explanation:    postgres.Jsonb{RawMessage: explanation},
+		ProfileVersion: profile.Version,
+		CreatedAt:      time.Now(),
+	}
+
+	if err := creditScore.Validate(); err != nil {
+		return decision, creditScore, err
+	}
+
+	if err := s.CreditScoreDBClient.Create(ctx, &creditScore); err != nil {
+		return decision, creditScore, err
+	}
+
+	return decision, creditScore, nil
+}
+
+// ApplyLimits grants the given limit for every tenor and starts a new validity period for it.
+// The limit amount stored is what remains of the granted limit after the installments of
+// active transactions, the way checkout debits it, so granting never gives back used credit.
+// Limits are only granted to customers whose identity has been verified. While possible
+// duplicates of the customer wait for review, limits are only renewed or lowered; tenors
+// that would get a new or higher limit are left as they are and ErrDuplicateReview is returned.
+func (s *CreditService) ApplyLimits(ctx context.Context, customerID int, limits map[int]float32, actor string, reason string) error {
+	customer, err := s.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_ID: customerID})
+	if err != nil {
+		return err
+	}
+
+	if !customer.IsKycVerified() {
+		return ErrNotVerified
+	}
+
+	blocking, err := s.DuplicateMatchDBClient.CountBlocking(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	granted, err := s.granted(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	withheld := false
+
+	for tenor, limitAmount := range limits {
+		filter := map[string]interface{}{
+			customerLimitDBModels.COLUMN_CUSTOMER_ID: customerID,
+			customerLimitDBModels.COLUMN_TENOR:       tenor,
+		}
+
+		customerLimit, err := s.CustomerLimitDBClient.Get(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		utilization := granted[tenor]
+
+		if blocking > 0 && limitAmount > utilization.Granted() {
+			withheld = true
+			continue
+		}
+
+		// A zero limit only needs to be recorded when it replaces an existing one
+		if customerLimit.ID == 0 && limitAmount == 0 {
+			continue
+		}
+
+		now := time.Now()
+		validUntil := limitService.ValidUntil(now)
+
+		// The amount in use is subtracted in the database, under a lock on the limit
+		customerLimit = customerLimitDBModels.CustomerLimit{
+			CustomerID: customerID,
+			Tenor:      tenor,
+			ValidFrom:  now,
+			ValidUntil: &validUntil,
+			Status:     customerLimitDBModels.STATUS_ACTIVE,
+			CreatedAt:  now,
+			UpdatedAt:  &now,
+		}
+
+		if err := s.LimitService.Grant(ctx, &customerLimit, limitAmount, actor, reason); err != nil {
+			return err
+		}
+	}
+
+	if withheld {
+		return ErrDuplicateReview
+	}
+
+	return nil
+}
+
+// AssignLimits scores the profile and applies the resulting limit for every tenor in the limit matrix.
+// Limits withheld for a duplicate review are assigned again once the review is done.
+func (s *CreditService) AssignLimits(ctx context.Context, profile customerProfileDBModels.CustomerProfile, actor string) error {
+	decision, _, err := s.Score(ctx, profile)
+	if err != nil {
+		return err
+	}
+
+	err = s.ApplyLimits(ctx, profile.CustomerID, decision.Limits, actor, Reason(limitService.REASON_CREDIT_SCORING, decision))
+	if errors.Is(err, ErrDuplicateReview) {
+		return nil
+	}
+
+	return err
+}
+
+// RescoreLimits scores a changed profile and lowers the limits the new score no longer
+// supports. The salary of the profile is declared by the customer, so a rescore never
+// raises a limit; higher limits go through a limit increase request.
+func (s *CreditService) RescoreLimits(ctx context.Context, profile customerProfileDBModels.CustomerProfile, actor string) error {
+	decision, _, err := s.Score(ctx, profile)
+	if err != nil {
+		return err
+	}
+
+	granted, err := s.granted(ctx, profile.CustomerID)
+	if err != nil {
+		return err
+	}
+
+	limits := map[int]float32{}
+	for tenor, utilization := range granted {
+		// Granting would lift the suspension of a limit
+		if utilization.Status == customerLimitDBModels.STATUS_SUSPENDED {
+			continue
+		}
+
+		if decision.Limits[tenor] < utilization.Granted() {
+			limits[tenor] = decision.Limits[tenor]
+		}
+	}
+
+	if len(limits) == 0 {
+		return nil
+	}
+
+	return s.ApplyLimits(ctx, profile.CustomerID, limits, actor, Reason(limitService.REASON_CREDIT_SCORING, decision))
+}
+
+// granted returns the utilization of the limits of the customer by tenor.
+// GrantedLimit returns the limit granted to the customer for the tenor, used and unused.
+func (s *CreditService) GrantedLimit(ctx context.Context, customerID int, tenor int) (float32, error) {
+	granted, err := s.granted(ctx, customerID)
+	if err != nil {
+		return 0, err
+	}
+
+	utilization := granted[tenor]
+	return utilization.Granted(), nil
+}
+
+// Tenors returns the tenors limits are granted for.
+func (s *CreditService) Tenors() []int {
+	return s.ScoringEngine.Tenors()
+}
+
+// CapLimit lowers the limit to the maximum limit a customer can be granted.
+func (s *CreditService) CapLimit(limit float32) float32 {
+	return s.ScoringEngine.CapLimit(limit)
+}
+
+func (s *CreditService) granted(ctx context.Context, customerID int) (map[int]customerLimitDBModels.CustomerLimitUtilization, error) {
+	utilizations, err := s.CustomerLimitDBClient.Utilization(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	granted := map[int]customerLimitDBModels.CustomerLimitUtilization{}
+	for _, utilization := range utilizations {
+		granted[utilization.Tenor] = utilization
+	}
+
+	return granted, nil
+}
+
+// Reason describes a limit change caused by the given decision.
+func Reason(reason string, decision scoring.Decision) string {
+	return fmt.Sprintf("%s: grade %s with score %.2f", reason, decision.Grade, decision.Score)
+}
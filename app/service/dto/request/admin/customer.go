@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"errors"
+
+	customerDBModels "kredit-plus/app/db/dto/customer"
+)
+
+type CustomerStatusChange struct {
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+func (r *CustomerStatusChange) Validate() error {
+	if !customerDBModels.IsValidStatus(r.Status) {
+		return errors.New("status must be one of active, suspended, closed or blacklisted")
+	}
+
+	if r.Status != customerDBModels.STATUS_ACTIVE && r.Reason == "" {
+		return errors.New("reason is required when the account is not active")
+	}
+
+	return nil
+}
+
+type CustomerErasure struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+func (r *CustomerErasure) Validate() error {
+	if r.Reason == "" {
+		return errors.New("reason is required")
+	}
+
+	return nil
+}
+
+type CustomerRoleChange struct {
+	Role         string `json:"role" binding:"required"`
+	SalesChannel string `json:"sales_channel"`
+}
+
+func (r *CustomerRoleChange) Validate() error {
+	if r.Role == "" {
+		return errors.New("role is required")
+	}
+
+	return nil
+}
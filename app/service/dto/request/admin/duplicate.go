@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"errors"
+
+	duplicateMatchDBModels "kredit-plus/app/db/dto/duplicate_match"
+)
+
+type DuplicateDecision struct {
+	Status string `json:"status" binding:"required"`
+	Notes  string `json:"notes"`
+}
+
+func (r *DuplicateDecision) Validate() error {
+	switch r.Status {
+	case duplicateMatchDBModels.STATUS_CONFIRMED:
+	case duplicateMatchDBModels.STATUS_DISMISSED:
+		if r.Notes == "" {
+			return errors.New("notes are required when dismissing a duplicate match")
+		}
+	default:
+		return errors.New("status must be one of confirmed or dismissed")
+	}
+
+	return nil
+}
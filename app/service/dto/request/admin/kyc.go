@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"errors"
+
+	kycVerificationDBModels "kredit-plus/app/db/dto/kyc_verification"
+)
+
+type KycDecision struct {
+	Status string `json:"status" binding:"required"`
+	Notes  string `json:"notes"`
+}
+
+func (r *KycDecision) Validate() error {
+	switch r.Status {
+	case kycVerificationDBModels.STATUS_VERIFIED:
+	case kycVerificationDBModels.STATUS_REJECTED:
+		if r.Notes == "" {
+			return errors.New("notes are required when rejecting a verification")
+		}
+	default:
+		return errors.New("status must be one of verified or rejected")
+	}
+
+	return nil
+}
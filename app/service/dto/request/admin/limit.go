@@ -0,0 +1,31 @@
+package admin
+
+import (
+	"errors"
+
+	limitIncreaseRequestDBModels "kredit-plus/app/db/dto/limit_increase_request"
+)
+
+type LimitIncreaseDecision struct {
+	Status         string   `json:"status" binding:"required"`
+	ApprovedAmount *float32 `json:"approved_amount"`
+	Notes          string   `json:"notes"`
+}
+
+func (r *LimitIncreaseDecision) Validate() error {
+	switch r.Status {
+	case limitIncreaseRequestDBModels.STATUS_UNDER_REVIEW, limitIncreaseRequestDBModels.STATUS_APPROVED:
+	case limitIncreaseRequestDBModels.STATUS_REJECTED:
+		if r.Notes == "" {
+			return errors.New("notes are required when rejecting a request")
+		}
+	default:
+		return errors.New("status must be one of under_review, approved or rejected")
+	}
+
+	if r.ApprovedAmount != nil && *r.ApprovedAmount <= 0 {
+		return errors.New("approved_amount must be greater than zero")
+	}
+
+	return nil
+}
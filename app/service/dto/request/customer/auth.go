@@ -6,6 +6,10 @@ type SignInRequest struct {
 	Email    string `json:"email"`
 	Phone    string `json:"phone"`
 	Password string `json:"password" binding:"required"`
+
+	// Name and Device describe the session started by the sign in, e.g. "Work phone"
+	Name   string `json:"name"`
+	Device string `json:"device"`
 }
 
 func (s *SignInRequest) Validate() error {
@@ -26,6 +30,14 @@ func (s *SignInRequest) Validate() error {
 		return errors.New("exactly one of email, phone, or user should be provided")
 	}
 
+	if len(s.Name) > 100 {
+		return errors.New("name must be at most 100 characters")
+	}
+
+	if len(s.Device) > 255 {
+		return errors.New("device must be at most 255 characters")
+	}
+
 	return nil
 }
 
@@ -40,3 +52,39 @@ func (r *RefreshTokenRequest) Validate() error {
 
 	return nil
 }
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+func (r *VerifyEmailRequest) Validate() error {
+	if r.Token == "" {
+		return errors.New("token is required")
+	}
+
+	return nil
+}
+
+type VerifyPhoneRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+func (r *VerifyPhoneRequest) Validate() error {
+	if r.Code == "" {
+		return errors.New("code is required")
+	}
+
+	return nil
+}
+
+type DataErasureRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+func (r *DataErasureRequest) Validate() error {
+	if r.Password == "" {
+		return errors.New("password is required")
+	}
+
+	return nil
+}
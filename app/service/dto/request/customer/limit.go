@@ -0,0 +1,49 @@
+package customer
+
+import (
+	"errors"
+	"fmt"
+)
+
+type LimitIncreaseRequest struct {
+	Tenor           int      `json:"tenor" binding:"required"`
+	RequestedAmount float32  `json:"requested_amount" binding:"required"`
+	Documents       []string `json:"documents"`
+	Reason          string   `json:"reason"`
+}
+
+// Validate checks the request, the tenor must be one limits are granted for.
+func (r *LimitIncreaseRequest) Validate(tenors []int) error {
+	if r.Tenor <= 0 {
+		return errors.New("tenor is required")
+	}
+
+	if !containsTenor(tenors, r.Tenor) {
+		return fmt.Errorf("tenor must be one of %v", tenors)
+	}
+
+	if r.RequestedAmount <= 0 {
+		return errors.New("requested_amount must be greater than zero")
+	}
+
+	if len(r.Documents) == 0 {
+		return errors.New("at least one supporting document is required")
+	}
+
+	for _, document := range r.Documents {
+		if document == "" {
+			return errors.New("documents must not be empty")
+		}
+	}
+
+	return nil
+}
+
+func containsTenor(tenors []int, tenor int) bool {
+	for _, t := range tenors {
+		if t == tenor {
+			return true
+		}
+	}
+	return false
+}
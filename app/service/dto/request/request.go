@@ -1,12 +1,69 @@
 package request
 
 import (
+	"errors"
 	"kredit-plus/app/service/util"
 )
 
+// ForgotPassword asks for a reset token, sent by email or by SMS depending on
+// which of the two is given.
+type ForgotPassword struct {
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+func (r *ForgotPassword) Validate() error {
+	if (r.Email == "") == (r.Phone == "") {
+		return errors.New("exactly one of email or phone should be provided")
+	}
+
+	return nil
+}
+
 type ResetPassword struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+func (r *ResetPassword) Validate() error {
+	if r.Token == "" {
+		return errors.New("token is required")
+	}
+	if r.Password == "" {
+		return errors.New("password is required")
+	}
+
+	return nil
+}
+
+type ChangePassword struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+func (r *ChangePassword) Validate() error {
+	if r.CurrentPassword == "" {
+		return errors.New("current_password is required")
+	}
+	if r.NewPassword == "" {
+		return errors.New("new_password is required")
+	}
+
+	return nil
+}
+
+// ProfileVersionDiff selects the two profile versions to compare.
+type ProfileVersionDiff struct {
+	From int `form:"from" binding:"required"`
+	To   int `form:"to" binding:"required"`
+}
+
+func (r *ProfileVersionDiff) Validate() error {
+	if r.From < 1 || r.To < 1 {
+		return errors.New("from and to should be version numbers")
+	}
+
+	return nil
 }
 
 type Pagination struct {
@@ -0,0 +1,11 @@
+package admin
+
+import (
+	roleDBModels "kredit-plus/app/db/dto/role"
+)
+
+// Role is a role with the permissions granted to it.
+type Role struct {
+	roleDBModels.Role
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,16 @@
+package customer
+
+import (
+	kycVerificationDBModels "kredit-plus/app/db/dto/kyc_verification"
+	"time"
+)
+
+type KycImageURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type KycStatus struct {
+	Status       string                                   `json:"kyc_status"`
+	Verification *kycVerificationDBModels.KycVerification `json:"verification"`
+}
@@ -0,0 +1,55 @@
+package customer
+
+import (
+	customerLimitDBModels "kredit-plus/app/db/dto/customer_limit"
+	"time"
+)
+
+type LimitUtilization struct {
+	Tenor      int        `json:"tenor,omitempty"`
+	Status     string     `json:"status,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+	Granted    float32    `json:"granted"`
+	InUse      float32    `json:"in_use"`
+	OnHold     float32    `json:"on_hold"`
+	Available  float32    `json:"available"`
+}
+
+type LimitSummary struct {
+	Total  LimitUtilization   `json:"total"`
+	Tenors []LimitUtilization `json:"tenors"`
+}
+
+// NewLimitSummary builds the summary from the per-tenor utilization. Checkout debits
+// the limit, so the granted amount is the remaining limit plus what is in use.
+// Expired and suspended limits have nothing available.
+func NewLimitSummary(utilizations []customerLimitDBModels.CustomerLimitUtilization, at time.Time) LimitSummary {
+	summary := LimitSummary{
+		Tenors: []LimitUtilization{},
+	}
+
+	for _, utilization := range utilizations {
+		available := utilization.LimitAmount - utilization.OnHoldAmount
+		if available < 0 || !utilization.IsUsable(at) {
+			available = 0
+		}
+
+		tenor := LimitUtilization{
+			Tenor:      utilization.Tenor,
+			Status:     utilization.Status,
+			ValidUntil: utilization.ValidUntil,
+			Granted:    utilization.Granted(),
+			InUse:      utilization.InUseAmount,
+			OnHold:     utilization.OnHoldAmount,
+			Available:  available,
+		}
+
+		summary.Total.Granted += tenor.Granted
+		summary.Total.InUse += tenor.InUse
+		summary.Total.OnHold += tenor.OnHold
+		summary.Total.Available += tenor.Available
+		summary.Tenors = append(summary.Tenors, tenor)
+	}
+
+	return summary
+}
@@ -0,0 +1,38 @@
+package customer
+
+import (
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerAddressDBModels "kredit-plus/app/db/dto/customer_address"
+	customerEmergencyContactDBModels "kredit-plus/app/db/dto/customer_emergency_contact"
+	customerEmploymentDBModels "kredit-plus/app/db/dto/customer_employment"
+	customerLimitDBModels "kredit-plus/app/db/dto/customer_limit"
+	customerProfileDBModels "kredit-plus/app/db/dto/customer_profile"
+	kycVerificationDBModels "kredit-plus/app/db/dto/kyc_verification"
+	transactionDBModels "kredit-plus/app/db/dto/transaction"
+	"time"
+)
+
+// DataExport holds the personal data kept about a customer.
+type DataExport struct {
+	Customer          customerDBModels.Customer                                   `json:"customer"`
+	Profile           *customerProfileDBModels.CustomerProfile                    `json:"profile"`
+	ProfileVersions   []ProfileVersion                                            `json:"profile_versions"`
+	Addresses         []customerAddressDBModels.CustomerAddress                   `json:"addresses"`
+	Employment        *customerEmploymentDBModels.CustomerEmployment              `json:"employment"`
+	EmergencyContacts []customerEmergencyContactDBModels.CustomerEmergencyContact `json:"emergency_contacts"`
+	Limits            []customerLimitDBModels.CustomerLimit                       `json:"limits"`
+	Sessions          []Session                                                   `json:"sessions"`
+	Transactions      []transactionDBModels.Transaction                           `json:"transactions"`
+	KycVerifications  []kycVerificationDBModels.KycVerification                   `json:"kyc_verifications"`
+	ExportedAt        time.Time                                                   `json:"exported_at"`
+}
+
+// Session describes a customer token without the token values themselves.
+type Session struct {
+	ID                    int       `json:"id"`
+	UserAgent             string    `json:"user_agent"`
+	IPAddress             string    `json:"ip_address"`
+	AccessTokenExpiredAt  time.Time `json:"access_token_expired_at"`
+	RefreshTokenExpiredAt time.Time `json:"refresh_token_expired_at"`
+	CreatedAt             time.Time `json:"created_at"`
+}
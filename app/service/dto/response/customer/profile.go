@@ -2,9 +2,13 @@ package customer
 
 import (
 	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerAddressDBModels "kredit-plus/app/db/dto/customer_address"
+	customerEmergencyContactDBModels "kredit-plus/app/db/dto/customer_emergency_contact"
+	customerEmploymentDBModels "kredit-plus/app/db/dto/customer_employment"
 	customerLimitDBModels "kredit-plus/app/db/dto/customer_limit"
 	customerProfileDBModels "kredit-plus/app/db/dto/customer_profile"
 	customerTokenDBModels "kredit-plus/app/db/dto/customer_token"
+	"time"
 )
 
 type CustomerProfileDetail struct {
@@ -12,4 +16,32 @@ type CustomerProfileDetail struct {
 	Profile  customerProfileDBModels.CustomerProfile `json:"profile"`
 	Token    customerTokenDBModels.CustomerToken     `json:"token"`
 	Limit    []customerLimitDBModels.CustomerLimit   `json:"limit"`
+
+	Addresses         []customerAddressDBModels.CustomerAddress                   `json:"addresses"`
+	Employment        *customerEmploymentDBModels.CustomerEmployment              `json:"employment"`
+	EmergencyContacts []customerEmergencyContactDBModels.CustomerEmergencyContact `json:"emergency_contacts"`
+}
+
+// ProfileVersion is a recorded version of the customer profile. Profile is empty for
+// a deleted or erased profile.
+type ProfileVersion struct {
+	Version       int                                      `json:"version"`
+	Action        string                                   `json:"action"`
+	ChangedFields []string                                 `json:"changed_fields"`
+	Actor         string                                   `json:"actor"`
+	CreatedAt     time.Time                                `json:"created_at"`
+	Profile       *customerProfileDBModels.CustomerProfile `json:"profile"`
+}
+
+// ProfileDiff lists the fields that differ between two profile versions.
+type ProfileDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
 }
@@ -0,0 +1,438 @@
+package duplicate
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+	"strconv"
+	"time"
+
+	"kredit-plus/app/constants"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerProfileDBModels "kredit-plus/app/db/dto/customer_profile"
+	duplicateMatchDBModels "kredit-plus/app/db/dto/duplicate_match"
+	customerDB "kredit-plus/app/db/repository/customer"
+	customerProfileDB "kredit-plus/app/db/repository/customer_profile"
+	duplicateMatchDB "kredit-plus/app/db/repository/duplicate_match"
+	"kredit-plus/app/service/credit"
+	"kredit-plus/app/service/dto/request"
+	limitService "kredit-plus/app/service/limit"
+	"kredit-plus/config"
+)
+
+var (
+	// ErrNIKRegistered is returned when the NIK belongs to the profile of another customer,
+	// by CheckNIK and by the profile repository when a concurrent write took the NIK first.
+	ErrNIKRegistered = customerProfileDB.ErrNIKRegistered
+	// ErrEmailRegistered is returned when the email address belongs to another customer.
+	ErrEmailRegistered = errors.New(constants.EMAIL_ALREADY_REGISTERED)
+	// ErrPhoneRegistered is returned when the phone number belongs to another customer.
+	ErrPhoneRegistered = errors.New(constants.PHONE_ALREADY_REGISTERED)
+	// ErrNotPending is returned when a match that has already been reviewed is reviewed again.
+	ErrNotPending = errors.New(constants.DUPLICATE_NOT_PENDING)
+)
+
+// IDuplicateService keeps a customer from holding more than one account.
+type IDuplicateService interface {
+	CheckContact(ctx context.Context, customerID int, email string, phone string) error
+	CheckNIK(ctx context.Context, customerID int, nik string) error
+	Flag(ctx context.Context, profile customerProfileDBModels.CustomerProfile) (int, error)
+	Scan(ctx context.Context) (int, error)
+	Blocked(ctx context.Context, customerID int) (bool, error)
+	Review(ctx context.Context, match duplicateMatchDBModels.DuplicateMatch, status string, notes string, reviewer string) (duplicateMatchDBModels.DuplicateMatch, error)
+}
+
+type DuplicateService struct {
+	CustomerDBClient        customerDB.ICustomerRepository
+	CustomerProfileDBClient customerProfileDB.ICustomerProfileRepository
+	DuplicateMatchDBClient  duplicateMatchDB.IDuplicateMatchRepository
+	CreditService           credit.ICreditService
+	Config                  config.DuplicateConfig
+}
+
+func NewDuplicateService(CustomerClient customerDB.ICustomerRepository, CustomerProfileClient customerProfileDB.ICustomerProfileRepository, DuplicateMatchClient duplicateMatchDB.IDuplicateMatchRepository, CreditService credit.ICreditService) *DuplicateService {
+	return &DuplicateService{
+		CustomerDBClient:        CustomerClient,
+		CustomerProfileDBClient: CustomerProfileClient,
+		DuplicateMatchDBClient:  DuplicateMatchClient,
+		CreditService:           CreditService,
+		Config:                  constants.Config.DuplicateConfig,
+	}
+}
+
+// CheckContact returns ErrEmailRegistered or ErrPhoneRegistered when the email address or
+// phone number is already used by a customer other than customerID. Empty values are not checked.
+func (s *DuplicateService) CheckContact(ctx context.Context, customerID int, email string, phone string) error {
+	if email != "" {
+		customer, err := s.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_EMAIL: email})
+		if err != nil {
+			return err
+		}
+
+		if customer.ID != 0 && customer.ID != customerID {
+			return ErrEmailRegistered
+		}
+	}
+
+	if phone != "" {
+		customer, err := s.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_PHONE: phone})
+		if err != nil {
+			return err
+		}
+
+		if customer.ID != 0 && customer.ID != customerID {
+			return ErrPhoneRegistered
+		}
+	}
+
+	return nil
+}
+
+// CheckNIK returns ErrNIKRegistered when the NIK is already on the profile of another customer.
+// Profiles written before the NIK blind index existed are not found until they are re-encrypted;
+// the scan flags those for review instead.
+func (s *DuplicateService) CheckNIK(ctx context.Context, customerID int, nik string) error {
+	if nik == "" {
+		return nil
+	}
+
+	p := request.Pagination{
+		GetAllData: true,
+	}
+
+	p.Validate()
+
+	profiles, _, err := s.CustomerProfileDBClient.List(ctx, p, map[string]interface{}{customerProfileDBModels.COLUMN_NIK: nik})
+	if err != nil {
+		return err
+	}
+
+	for _, profile := range profiles {
+		if profile.CustomerID != customerID {
+			return ErrNIKRegistered
+		}
+	}
+
+	return nil
+}
+
+// Flag compares the profile with the profiles of other customers sharing its NIK, its selfie
+// hash or its name and date of birth, and queues every possible duplicate for review. It
+// returns the number of new matches. Similar but not equal names and selfies are left to
+// the scan.
+func (s *DuplicateService) Flag(ctx context.Context, profile customerProfileDBModels.CustomerProfile) (int, error) {
+	candidates, err := s.CustomerProfileDBClient.Candidates(ctx, profile)
+	if err != nil {
+		return 0, err
+	}
+
+	flagged := 0
+	for _, other := range candidates {
+		n, err := s.flag(ctx, s.compare(profile, other))
+		if err != nil {
+			return flagged, err
+		}
+		flagged += n
+	}
+
+	return flagged, nil
+}
+
+// Scan compares all profiles with each other and queues every possible duplicate that has
+// not been flagged before. It returns the number of new matches.
+func (s *DuplicateService) Scan(ctx context.Context) (int, error) {
+	profiles, err := s.profiles(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Only profiles sharing a NIK, a date of birth or a part of their selfie hash can match,
+	// so they are grouped first instead of comparing every pair
+	groups := map[string][]int{}
+	for i, profile := range profiles {
+		if profile.NIK != "" {
+			groups["nik:"+profile.NIK] = append(groups["nik:"+profile.NIK], i)
+		}
+
+		if dateOfBirth := profile.DateOfBirth.String(); dateOfBirth != "" {
+			groups["dob:"+dateOfBirth] = append(groups["dob:"+dateOfBirth], i)
+		}
+
+		if hash, ok := parseHash(profile.SelfieHash); ok {
+			for _, key := range hashBuckets(hash, s.Config.DUPLICATE_SELFIE_HASH_DISTANCE) {
+				groups[key] = append(groups[key], i)
+			}
+		}
+	}
+
+	compared := map[[2]int]bool{}
+	flagged := 0
+
+	for _, group := range groups {
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				a, b := profiles[group[i]], profiles[group[j]]
+
+				pair := [2]int{a.CustomerID, b.CustomerID}
+				if a.CustomerID > b.CustomerID {
+					pair = [2]int{b.CustomerID, a.CustomerID}
+				}
+
+				if a.CustomerID == b.CustomerID || compared[pair] {
+					continue
+				}
+				compared[pair] = true
+
+				n, err := s.flag(ctx, s.compare(a, b))
+				if err != nil {
+					return flagged, err
+				}
+				flagged += n
+			}
+		}
+	}
+
+	return flagged, nil
+}
+
+// Blocked reports whether new limits of the customer are on hold for a duplicate review.
+func (s *DuplicateService) Blocked(ctx context.Context, customerID int) (bool, error) {
+	count, err := s.DuplicateMatchDBClient.CountBlocking(ctx, customerID)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Review confirms or dismisses a pending match. Customers that are no longer blocked by a
+// review get the limits that were withheld from them.
+func (s *DuplicateService) Review(ctx context.Context, match duplicateMatchDBModels.DuplicateMatch, status string, notes string, reviewer string) (duplicateMatchDBModels.DuplicateMatch, error) {
+	if match.Status != duplicateMatchDBModels.STATUS_PENDING {
+		return match, ErrNotPending
+	}
+
+	now := time.Now()
+
+	filter := map[string]interface{}{
+		duplicateMatchDBModels.COLUMN_ID: match.ID,
+	}
+
+	patcher := map[string]interface{}{
+		duplicateMatchDBModels.COLUMN_STATUS:       status,
+		duplicateMatchDBModels.COLUMN_REVIEWER:     reviewer,
+		duplicateMatchDBModels.COLUMN_REVIEW_NOTES: notes,
+		duplicateMatchDBModels.COLUMN_REVIEWED_AT:  now,
+		duplicateMatchDBModels.COLUMN_UPDATED_AT:   now,
+	}
+
+	if err := s.DuplicateMatchDBClient.Update(ctx, filter, patcher); err != nil {
+		return match, err
+	}
+
+	match.Status = status
+	match.Reviewer = reviewer
+	match.ReviewNotes = notes
+	match.ReviewedAt = &now
+	match.UpdatedAt = &now
+
+	for _, customerID := range []int{match.CustomerID, match.MatchedCustomerID} {
+		if err := s.release(ctx, customerID); err != nil {
+			return match, err
+		}
+	}
+
+	return match, nil
+}
+
+// release assigns limits to a verified customer that is no longer blocked by a review.
+func (s *DuplicateService) release(ctx context.Context, customerID int) error {
+	blocked, err := s.Blocked(ctx, customerID)
+	if err != nil || blocked {
+		return err
+	}
+
+	customer, err := s.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_ID: customerID})
+	if err != nil {
+		return err
+	}
+
+	if !customer.IsKycVerified() {
+		return nil
+	}
+
+	profile, err := s.CustomerProfileDBClient.Get(ctx, map[string]interface{}{customerProfileDBModels.COLUMN_CUSTOMER_ID: customerID})
+	if err != nil || profile.ID == 0 {
+		return err
+	}
+
+	return s.CreditService.AssignLimits(ctx, profile, limitService.ACTOR_SYSTEM)
+}
+
+// profiles loads the profiles of all customers.
+func (s *DuplicateService) profiles(ctx context.Context) ([]customerProfileDBModels.CustomerProfile, error) {
+	p := request.Pagination{
+		GetAllData: true,
+	}
+
+	p.Validate()
+
+	profiles, _, err := s.CustomerProfileDBClient.List(ctx, p, map[string]interface{}{})
+	return profiles, err
+}
+
+// compare returns a match for every reason the two profiles may belong to the same person.
+func (s *DuplicateService) compare(a, b customerProfileDBModels.CustomerProfile) []duplicateMatchDBModels.DuplicateMatch {
+	if a.CustomerID > b.CustomerID {
+		a, b = b, a
+	}
+
+	var matches []duplicateMatchDBModels.DuplicateMatch
+
+	match := func(reason string, score float64) {
+		matches = append(matches, duplicateMatchDBModels.DuplicateMatch{
+			CustomerID:        a.CustomerID,
+			MatchedCustomerID: b.CustomerID,
+			Reason:            reason,
+			Score:             score,
+			Status:            duplicateMatchDBModels.STATUS_PENDING,
+		})
+	}
+
+	if a.NIK != "" && a.NIK == b.NIK {
+		match(duplicateMatchDBModels.REASON_NIK, 1)
+	}
+
+	if !a.DateOfBirth.IsZero() && a.DateOfBirth.Equal(b.DateOfBirth.Time) {
+		if score := similarity(a.Name(), b.Name()); score >= s.Config.DUPLICATE_NAME_SIMILARITY {
+			match(duplicateMatchDBModels.REASON_NAME_DATE_OF_BIRTH, score)
+		}
+	}
+
+	hashA, okA := parseHash(a.SelfieHash)
+	hashB, okB := parseHash(b.SelfieHash)
+	if okA && okB {
+		if distance := bits.OnesCount64(hashA ^ hashB); distance <= s.Config.DUPLICATE_SELFIE_HASH_DISTANCE {
+			match(duplicateMatchDBModels.REASON_SELFIE, 1-float64(distance)/64)
+		}
+	}
+
+	return matches
+}
+
+// flag queues the matches that have not been flagged before. A match that was already
+// reviewed is not flagged again.
+func (s *DuplicateService) flag(ctx context.Context, matches []duplicateMatchDBModels.DuplicateMatch) (int, error) {
+	flagged := 0
+
+	for _, match := range matches {
+		existing, err := s.DuplicateMatchDBClient.Get(ctx, map[string]interface{}{
+			duplicateMatchDBModels.COLUMN_CUSTOMER_ID:         match.CustomerID,
+			duplicateMatchDBModels.COLUMN_MATCHED_CUSTOMER_ID: match.MatchedCustomerID,
+			duplicateMatchDBModels.COLUMN_REASON:              match.Reason,
+		})
+		if err != nil {
+			return flagged, err
+		}
+
+		if existing.ID != 0 {
+			continue
+		}
+
+		now := time.Now()
+		match.CreatedAt = now
+		match.UpdatedAt = &now
+
+		if err := s.DuplicateMatchDBClient.Create(ctx, &match); err != nil {
+			return flagged, err
+		}
+		flagged++
+	}
+
+	return flagged, nil
+}
+
+// similarity is one minus the edit distance of both names relative to the longer one.
+func similarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+
+	ra, rb := []rune(a), []rune(b)
+
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = smallest(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func smallest(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
+
+func parseHash(value string) (uint64, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	hash, err := strconv.ParseUint(value, 16, 64)
+	return hash, err == nil
+}
+
+// hashBuckets splits the hash into distance+1 parts. Two hashes that differ in at most
+// distance bits have at least one part in common, so only hashes sharing a bucket need
+// to be compared.
+func hashBuckets(hash uint64, distance int) []string {
+	if distance < 0 {
+		distance = 0
+	}
+
+	parts := distance + 1
+	if parts > 64 {
+		parts = 64
+	}
+
+	buckets := make([]string, 0, parts)
+	for i := 0; i < parts; i++ {
+		from, to := i*64/parts, (i+1)*64/parts
+		part := (hash >> uint(from)) & (1<<uint(to-from) - 1)
+		buckets = append(buckets, "selfie:"+strconv.Itoa(i)+":"+strconv.FormatUint(part, 16))
+	}
+
+	return buckets
+}
@@ -0,0 +1,86 @@
+package duplicate
+
+import (
+	"context"
+	"time"
+
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	"kredit-plus/app/service/correlation"
+	"kredit-plus/app/service/logger"
+	"kredit-plus/config"
+)
+
+// LOCK_NAME is the advisory lock held while a scan runs.
+const LOCK_NAME = "duplicate-scan"
+
+// IDuplicateScanJob periodically looks for customers that may hold more than one account.
+type IDuplicateScanJob interface {
+	Start(ctx context.Context)
+	Run(ctx context.Context) error
+}
+
+type DuplicateScanJob struct {
+	DuplicateService IDuplicateService
+	Locker           db.ILocker
+	Config           config.DuplicateConfig
+}
+
+func NewDuplicateScanJob(DuplicateService IDuplicateService, Locker db.ILocker) *DuplicateScanJob {
+	return &DuplicateScanJob{
+		DuplicateService: DuplicateService,
+		Locker:           Locker,
+		Config:           constants.Config.DuplicateConfig,
+	}
+}
+
+// Start runs the scan every DUPLICATE_SCAN_INTERVAL_MINUTES until the context is done.
+func (j *DuplicateScanJob) Start(ctx context.Context) {
+	log := logger.Logger(ctx)
+
+	if j.Config.DUPLICATE_SCAN_INTERVAL_MINUTES <= 0 {
+		log.Info("duplicate scan job is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(j.Config.DUPLICATE_SCAN_INTERVAL_MINUTES) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runCtx := correlation.ContextFromCorrelation("")
+			if err := j.Run(runCtx); err != nil {
+				logger.Logger(runCtx).Errorf("duplicate scan failed: %v", err)
+			}
+		}
+	}
+}
+
+// Run scans all profiles and queues the possible duplicates found for review.
+// Every instance of the service starts the job, the scan runs in whichever takes the lock.
+func (j *DuplicateScanJob) Run(ctx context.Context) error {
+	log := logger.Logger(ctx)
+
+	unlock, locked, err := j.Locker.TryLock(ctx, LOCK_NAME)
+	if err != nil {
+		return err
+	}
+
+	if !locked {
+		log.Info("duplicate scan is running in another instance")
+		return nil
+	}
+	defer unlock()
+
+	flagged, err := j.DuplicateService.Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("duplicate scan flagged %d possible duplicates", flagged)
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package eligibility
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"kredit-plus/app/constants"
+	"kredit-plus/app/service/util"
+	"kredit-plus/config"
+)
+
+var (
+	// ErrNotEligible is wrapped by every age rule the customer breaks.
+	ErrNotEligible = errors.New(constants.AGE_NOT_ELIGIBLE)
+	// ErrDateOfBirthRequired is returned when the age cannot be checked.
+	ErrDateOfBirthRequired = errors.New(constants.DATE_OF_BIRTH_REQUIRED)
+)
+
+// Policy describes the ages at which customers may take credit.
+type Policy struct {
+	MinAge int
+	MaxAge int
+}
+
+func NewPolicy(cfg config.EligibilityConfig) Policy {
+	return Policy{
+		MinAge: cfg.ELIGIBILITY_MIN_AGE,
+		MaxAge: cfg.ELIGIBILITY_MAX_AGE,
+	}
+}
+
+// Check returns the first age rule a customer born on dateOfBirth breaks at the given time.
+func (p Policy) Check(dateOfBirth util.Date, at time.Time) error {
+	if dateOfBirth.IsZero() {
+		return ErrDateOfBirthRequired
+	}
+
+	if dateOfBirth.After(at) {
+		return fmt.Errorf("%w: date of birth is in the future", ErrNotEligible)
+	}
+
+	age := util.Age(dateOfBirth.Time, at)
+
+	if p.MinAge > 0 && age < p.MinAge {
+		return fmt.Errorf("%w: customer must be at least %d years old", ErrNotEligible, p.MinAge)
+	}
+
+	if p.MaxAge > 0 && age > p.MaxAge {
+		return fmt.Errorf("%w: customer must be at most %d years old", ErrNotEligible, p.MaxAge)
+	}
+
+	return nil
+}
+
+// CheckTenor is Check with the additional rule that the customer is not older than
+// MaxAge when the last installment of a tenor of the given number of months is due.
+func (p Policy) CheckTenor(dateOfBirth util.Date, at time.Time, tenor int) error {
+	if err := p.Check(dateOfBirth, at); err != nil {
+		return err
+	}
+
+	if p.MaxAge > 0 && util.Age(dateOfBirth.Time, at.AddDate(0, tenor, 0)) > p.MaxAge {
+		return fmt.Errorf("%w: customer must be at most %d years old at the end of a %d month tenor", ErrNotEligible, p.MaxAge, tenor)
+	}
+
+	return nil
+}
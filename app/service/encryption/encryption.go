@@ -0,0 +1,155 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"kredit-plus/config"
+)
+
+// PREFIX marks an encrypted value: enc:<key id>:<base64 nonce and ciphertext>.
+// Values without it are plaintext written before encryption was enabled.
+const PREFIX = "enc:"
+
+var ErrUnknownKey = errors.New("value is encrypted with an unknown key")
+
+// Cipher encrypts personal data with AES-256-GCM. Every value carries the ID of its
+// key, so old keys can keep decrypting while new values use the current key.
+type Cipher struct {
+	keys         map[string]cipher.AEAD
+	currentKeyID string
+	indexKey     []byte
+}
+
+// NewCipherFromConfig loads the keys from the configured provider.
+func NewCipherFromConfig(cfg config.EncryptionConfig) (*Cipher, error) {
+	provider, err := NewKeyProviderFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := provider.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	indexKey, err := decodeKey(cfg.ENCRYPTION_BLIND_INDEX_KEY)
+	if err != nil {
+		return nil, fmt.Errorf("blind index key: %w", err)
+	}
+
+	return NewCipher(keys, cfg.ENCRYPTION_CURRENT_KEY_ID, indexKey)
+}
+
+func NewCipher(keys map[string][]byte, currentKeyID string, indexKey []byte) (*Cipher, error) {
+	if _, ok := keys[currentKeyID]; !ok {
+		return nil, fmt.Errorf("current key %q is not configured", currentKeyID)
+	}
+
+	c := &Cipher{
+		keys:         map[string]cipher.AEAD{},
+		currentKeyID: currentKeyID,
+		indexKey:     indexKey,
+	}
+
+	for id, key := range keys {
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("key id %q may not contain a colon", id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		c.keys[id] = aead
+	}
+
+	return c, nil
+}
+
+// Encrypt encrypts the value with the current key. The field name is authenticated
+// with the value, so a ciphertext cannot be copied into another column.
+func (c *Cipher) Encrypt(plaintext string, field string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	aead := c.keys[c.currentKeyID]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(field))
+
+	return PREFIX + c.currentKeyID + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plaintext of a value from Encrypt. Plaintext values are returned as they are.
+func (c *Cipher) Decrypt(value string, field string) (string, error) {
+	if !strings.HasPrefix(value, PREFIX) {
+		return value, nil
+	}
+
+	id, encoded, ok := strings.Cut(strings.TrimPrefix(value, PREFIX), ":")
+	if !ok {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	aead, ok := c.keys[id]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(field))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether the value is plaintext or encrypted with a key other than the current one.
+func (c *Cipher) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	return !strings.HasPrefix(value, PREFIX+c.currentKeyID+":")
+}
+
+// BlindIndex returns a keyed hash of the value that allows exact match lookups
+// without decrypting. It does not change when the encryption keys are rotated.
+func (c *Cipher) BlindIndex(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
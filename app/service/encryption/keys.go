@@ -0,0 +1,149 @@
+package encryption
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"kredit-plus/config"
+)
+
+const (
+	PROVIDER_CONFIG = "config"
+	PROVIDER_FILE   = "file"
+
+	KEY_SIZE = 32
+
+	// KEY_PLACEHOLDER stands in for the keys in the example configuration. It is rejected,
+	// so data is never encrypted with a key everyone who read the example knows.
+	KEY_PLACEHOLDER = "CHANGE_ME"
+
+	// MIN_SECRET_LENGTH is the shortest HMAC secret accepted, e.g. the 44 characters of
+	// "openssl rand -base64 32" are enough.
+	MIN_SECRET_LENGTH = 32
+)
+
+// CheckSecret rejects an HMAC secret that is empty, still the placeholder or too short.
+// Anyone who knows the secret can forge what it signs.
+func CheckSecret(secret string) error {
+	secret = strings.TrimSpace(secret)
+
+	if secret == "" || secret == KEY_PLACEHOLDER {
+		return fmt.Errorf("secret is not set or still the placeholder %s, generate one with: openssl rand -base64 %d", KEY_PLACEHOLDER, KEY_SIZE)
+	}
+
+	if len(secret) < MIN_SECRET_LENGTH {
+		return fmt.Errorf("secret must be at least %d characters", MIN_SECRET_LENGTH)
+	}
+
+	return nil
+}
+
+// KeyProvider returns the data encryption keys by key ID.
+type KeyProvider interface {
+	Keys() (map[string][]byte, error)
+}
+
+// NewKeyProviderFromConfig returns the key provider named by ENCRYPTION_KEY_PROVIDER.
+func NewKeyProviderFromConfig(cfg config.EncryptionConfig) (KeyProvider, error) {
+	switch cfg.ENCRYPTION_KEY_PROVIDER {
+	case PROVIDER_CONFIG:
+		return NewConfigKeyProvider(cfg.ENCRYPTION_KEYS), nil
+	case PROVIDER_FILE:
+		return NewFileKeyProvider(cfg.ENCRYPTION_KEY_FILE), nil
+	default:
+		return nil, fmt.Errorf("unknown key provider %q", cfg.ENCRYPTION_KEY_PROVIDER)
+	}
+}
+
+// ConfigKeyProvider reads keys from ENCRYPTION_KEYS, a list of "id:base64 key" entries.
+type ConfigKeyProvider struct {
+	entries []string
+}
+
+func NewConfigKeyProvider(entries []string) *ConfigKeyProvider {
+	return &ConfigKeyProvider{entries: entries}
+}
+
+func (p *ConfigKeyProvider) Keys() (map[string][]byte, error) {
+	return parseKeys(p.entries)
+}
+
+// FileKeyProvider reads keys from a file with one "id:base64 key" entry per line,
+// e.g. a mounted secret. Empty lines and lines starting with # are ignored.
+type FileKeyProvider struct {
+	path string
+}
+
+func NewFileKeyProvider(path string) *FileKeyProvider {
+	return &FileKeyProvider{path: path}
+}
+
+func (p *FileKeyProvider) Keys() (map[string][]byte, error) {
+	file, err := os.Open(p.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries := []string{}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return parseKeys(entries)
+}
+
+func parseKeys(entries []string) (map[string][]byte, error) {
+	keys := map[string][]byte{}
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("key entry must have the form id:key")
+		}
+
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+
+		keys[id] = key
+	}
+
+	return keys, nil
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	if strings.TrimSpace(encoded) == KEY_PLACEHOLDER {
+		return nil, fmt.Errorf("key is still the placeholder %s, generate one with: openssl rand -base64 %d", KEY_PLACEHOLDER, KEY_SIZE)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) != KEY_SIZE {
+		return nil, fmt.Errorf("key must be %d bytes", KEY_SIZE)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,166 @@
+package kyc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"kredit-plus/app/constants"
+	"kredit-plus/config"
+)
+
+const (
+	CONTENT_TYPE_JPEG = "image/jpeg"
+	CONTENT_TYPE_PNG  = "image/png"
+
+	JPEG_QUALITY = 90
+)
+
+var extensions = map[string]string{
+	CONTENT_TYPE_JPEG: ".jpg",
+	CONTENT_TYPE_PNG:  ".png",
+}
+
+// Image is an uploaded KYC image after validation, re-encoded without metadata.
+// Hash is its perceptual hash, see DifferenceHash.
+type Image struct {
+	Data        []byte
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+	Hash        string
+}
+
+// ImageRules limits the size and dimensions of uploaded KYC images.
+type ImageRules struct {
+	MaxSize   int64
+	MinWidth  int
+	MinHeight int
+	MaxWidth  int
+	MaxHeight int
+}
+
+func NewImageRules(cfg config.KycConfig) ImageRules {
+	return ImageRules{
+		MaxSize:   cfg.KYC_MAX_IMAGE_SIZE,
+		MinWidth:  cfg.KYC_MIN_IMAGE_WIDTH,
+		MinHeight: cfg.KYC_MIN_IMAGE_HEIGHT,
+		MaxWidth:  cfg.KYC_MAX_IMAGE_WIDTH,
+		MaxHeight: cfg.KYC_MAX_IMAGE_HEIGHT,
+	}
+}
+
+// Process validates the content type, size and dimensions of an uploaded image and
+// re-encodes it. Re-encoding drops EXIF and every other metadata block of the original.
+func (r ImageRules) Process(reader io.Reader) (Image, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, r.MaxSize+1))
+	if err != nil {
+		return Image{}, err
+	}
+
+	if int64(len(data)) > r.MaxSize {
+		return Image{}, errors.New(constants.IMAGE_TOO_LARGE)
+	}
+
+	contentType := http.DetectContentType(data)
+	extension, ok := extensions[contentType]
+	if !ok {
+		return Image{}, errors.New(constants.INVALID_IMAGE_TYPE)
+	}
+
+	// Check the dimensions from the header before decoding the whole image
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, errors.New(constants.INVALID_IMAGE_TYPE)
+	}
+
+	if cfg.Width < r.MinWidth || cfg.Height < r.MinHeight || cfg.Width > r.MaxWidth || cfg.Height > r.MaxHeight {
+		return Image{}, errors.New(constants.INVALID_IMAGE_DIMENSIONS)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, errors.New(constants.INVALID_IMAGE_TYPE)
+	}
+
+	var out bytes.Buffer
+	switch contentType {
+	case CONTENT_TYPE_JPEG:
+		err = jpeg.Encode(&out, img, &jpeg.Options{Quality: JPEG_QUALITY})
+	case CONTENT_TYPE_PNG:
+		err = png.Encode(&out, img)
+	}
+	if err != nil {
+		return Image{}, err
+	}
+
+	return Image{
+		Data:        out.Bytes(),
+		ContentType: contentType,
+		Extension:   extension,
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+		Hash:        fmt.Sprintf("%016x", DifferenceHash(img)),
+	}, nil
+}
+
+// DifferenceHash returns a 64 bit perceptual hash of the image: the image is reduced
+// to 9x8 gray values and every bit tells whether a value is brighter than its right
+// neighbour. Re-encoded, resized or slightly edited copies of an image get hashes
+// that differ in only a few bits.
+func DifferenceHash(img image.Image) uint64 {
+	const width, height = 9, 8
+
+	bounds := img.Bounds()
+	var gray [height][width]float64
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := bounds.Min.Y + (y+1)*bounds.Dy()/height
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := bounds.Min.X + (x+1)*bounds.Dx()/width
+
+			var sum float64
+			var count int
+			for py := y0; py < y1; py++ {
+				for px := x0; px < x1; px++ {
+					r, g, b, _ := img.At(px, py).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+					count++
+				}
+			}
+			if count > 0 {
+				gray[y][x] = sum / float64(count)
+			}
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width-1; x++ {
+			hash <<= 1
+			if gray[y][x] > gray[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash
+}
+
+// ContentType returns the content type of a stored image from its key.
+func ContentType(key string) string {
+	for contentType, extension := range extensions {
+		if len(key) >= len(extension) && key[len(key)-len(extension):] == extension {
+			return contentType
+		}
+	}
+	return "application/octet-stream"
+}
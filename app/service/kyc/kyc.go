@@ -0,0 +1,23 @@
+package kyc
+
+import "fmt"
+
+const (
+	TYPE_KTP    = "ktp"
+	TYPE_SELFIE = "selfie"
+	// TYPE_INCOME_PROOF is a payslip or employment letter backing the employment record
+	TYPE_INCOME_PROOF = "income_proof"
+
+	KEY_PREFIX = "kyc/"
+)
+
+// IsStorageKey reports whether an image value is a storage key rather than a
+// URL stored before uploads went through the blob store.
+func IsStorageKey(value string) bool {
+	return len(value) > len(KEY_PREFIX) && value[:len(KEY_PREFIX)] == KEY_PREFIX
+}
+
+// Key returns a new storage key for a KYC image of a customer.
+func Key(customerUUID string, kycType string, id string, extension string) string {
+	return fmt.Sprintf("%s%s/%s-%s%s", KEY_PREFIX, customerUUID, kycType, id, extension)
+}
@@ -0,0 +1,290 @@
+package kyc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"kredit-plus/app/constants"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerProfileDBModels "kredit-plus/app/db/dto/customer_profile"
+	kycVerificationDBModels "kredit-plus/app/db/dto/kyc_verification"
+	customerDB "kredit-plus/app/db/repository/customer"
+	customerProfileDB "kredit-plus/app/db/repository/customer_profile"
+	kycVerificationDB "kredit-plus/app/db/repository/kyc_verification"
+	"kredit-plus/app/service/credit"
+	limitService "kredit-plus/app/service/limit"
+	"kredit-plus/app/service/scoring"
+	"kredit-plus/app/service/storage"
+	"kredit-plus/app/service/util"
+)
+
+// ErrNotPending is returned when a verification that has already been reviewed is reviewed again.
+var ErrNotPending = errors.New(constants.KYC_NOT_PENDING)
+
+const (
+	REASON_AUTO_VERIFIED = "identity and face matched automatically"
+	REASON_MANUAL_REVIEW = "queued for manual review"
+	REASON_SUPERSEDED    = "superseded by a change to the profile"
+)
+
+// IKycService verifies the identity of customers and keeps their KYC status up to date.
+type IKycService interface {
+	Verify(ctx context.Context, customer customerDBModels.Customer, profile customerProfileDBModels.CustomerProfile) (kycVerificationDBModels.KycVerification, error)
+	Review(ctx context.Context, verification kycVerificationDBModels.KycVerification, status string, notes string, reviewer string) (kycVerificationDBModels.KycVerification, error)
+	Refresh(ctx context.Context, customer customerDBModels.Customer, previous customerProfileDBModels.CustomerProfile, profile customerProfileDBModels.CustomerProfile) error
+}
+
+type KycService struct {
+	Provider                Provider
+	BlobStore               storage.BlobStore
+	KycVerificationDBClient kycVerificationDB.IKycVerificationRepository
+	CustomerDBClient        customerDB.ICustomerRepository
+	CustomerProfileDBClient customerProfileDB.ICustomerProfileRepository
+	CreditService           credit.ICreditService
+	FaceMatchThreshold      float64
+}
+
+func NewKycService(Provider Provider, BlobStore storage.BlobStore, KycVerificationClient kycVerificationDB.IKycVerificationRepository, CustomerClient customerDB.ICustomerRepository, CustomerProfileClient customerProfileDB.ICustomerProfileRepository, CreditService credit.ICreditService, FaceMatchThreshold float64) *KycService {
+	return &KycService{
+		Provider:                Provider,
+		BlobStore:               BlobStore,
+		KycVerificationDBClient: KycVerificationClient,
+		CustomerDBClient:        CustomerClient,
+		CustomerProfileDBClient: CustomerProfileClient,
+		CreditService:           CreditService,
+		FaceMatchThreshold:      FaceMatchThreshold,
+	}
+}
+
+// Verify runs OCR and face matching on the uploaded KYC images of the profile. A
+// customer whose identity and face both match is verified and gets credit limits;
+// anything else waits in the manual review queue.
+func (s *KycService) Verify(ctx context.Context, customer customerDBModels.Customer, profile customerProfileDBModels.CustomerProfile) (kycVerificationDBModels.KycVerification, error) {
+	ktpImage, err := s.read(ctx, profile.KtpImage)
+	if err != nil {
+		return kycVerificationDBModels.KycVerification{}, err
+	}
+
+	selfieImage, err := s.read(ctx, profile.SelfieImage)
+	if err != nil {
+		return kycVerificationDBModels.KycVerification{}, err
+	}
+
+	name := profile.LegalName
+	if name == "" {
+		name = profile.FullName
+	}
+
+	ocr, err := s.Provider.ExtractKTP(ctx, ktpImage, Claim{NIK: profile.NIK, Name: name, DateOfBirth: profile.DateOfBirth.String()})
+	if err != nil {
+		return kycVerificationDBModels.KycVerification{}, err
+	}
+
+	score, err := s.Provider.MatchFace(ctx, ktpImage, selfieImage)
+	if err != nil {
+		return kycVerificationDBModels.KycVerification{}, err
+	}
+
+	now := time.Now()
+
+	verification := kycVerificationDBModels.KycVerification{
+		CustomerID:         customer.ID,
+		Provider:           s.Provider.Name(),
+		KtpImage:           profile.KtpImage,
+		SelfieImage:        profile.SelfieImage,
+		OcrNIK:             ocr.NIK,
+		OcrName:            ocr.Name,
+		OcrDateOfBirth:     ocr.DateOfBirth,
+		NIKMatched:         ocr.NIK != "" && ocr.NIK == profile.NIK,
+		NameMatched:        normalizeName(ocr.Name) != "" && normalizeName(ocr.Name) == normalizeName(name),
+		DateOfBirthMatched: sameDate(ocr.DateOfBirth, profile.DateOfBirth.String()),
+		FaceMatchScore:     score,
+		CreatedAt:          now,
+		UpdatedAt:          &now,
+	}
+
+	mismatches := []string{}
+	if !verification.NIKMatched {
+		mismatches = append(mismatches, "NIK does not match the KTP")
+	}
+	if !verification.NameMatched {
+		mismatches = append(mismatches, "name does not match the KTP")
+	}
+	if !verification.DateOfBirthMatched {
+		mismatches = append(mismatches, "date of birth does not match the KTP")
+	}
+	if score < s.FaceMatchThreshold {
+		mismatches = append(mismatches, fmt.Sprintf("face match score %.4f is below %.4f", score, s.FaceMatchThreshold))
+	}
+
+	verification.Status = kycVerificationDBModels.STATUS_VERIFIED
+	verification.Reason = REASON_AUTO_VERIFIED
+	if len(mismatches) > 0 {
+		verification.Status = kycVerificationDBModels.STATUS_PENDING
+		verification.Reason = fmt.Sprintf("%s: %s", REASON_MANUAL_REVIEW, strings.Join(mismatches, "; "))
+	}
+
+	if err := verification.Validate(); err != nil {
+		return verification, err
+	}
+
+	if err := s.KycVerificationDBClient.Create(ctx, &verification); err != nil {
+		return verification, err
+	}
+
+	if err := s.apply(ctx, customer.ID, verification.Status, profile); err != nil {
+		return verification, err
+	}
+
+	return verification, nil
+}
+
+// Review records the decision of an admin on a verification in the manual review queue.
+// It returns ErrNotPending when the verification was reviewed or withdrawn in the meantime.
+func (s *KycService) Review(ctx context.Context, verification kycVerificationDBModels.KycVerification, status string, notes string, reviewer string) (kycVerificationDBModels.KycVerification, error) {
+	now := time.Now()
+
+	filter := map[string]interface{}{
+		kycVerificationDBModels.COLUMN_ID: verification.ID,
+	}
+
+	patcher := map[string]interface{}{
+		kycVerificationDBModels.COLUMN_STATUS:       status,
+		kycVerificationDBModels.COLUMN_REVIEWER:     reviewer,
+		kycVerificationDBModels.COLUMN_REVIEW_NOTES: notes,
+		kycVerificationDBModels.COLUMN_REVIEWED_AT:  now,
+		kycVerificationDBModels.COLUMN_UPDATED_AT:   now,
+	}
+
+	reviewed, err := s.KycVerificationDBClient.Transition(ctx, verification.ID, kycVerificationDBModels.STATUS_PENDING, patcher)
+	if err != nil {
+		return verification, err
+	}
+
+	if !reviewed {
+		return verification, ErrNotPending
+	}
+
+	profile, err := s.CustomerProfileDBClient.Get(ctx, map[string]interface{}{customerProfileDBModels.COLUMN_CUSTOMER_ID: verification.CustomerID})
+	if err != nil {
+		return verification, err
+	}
+
+	if err := s.apply(ctx, verification.CustomerID, status, profile); err != nil {
+		return verification, err
+	}
+
+	return s.KycVerificationDBClient.Get(ctx, filter)
+}
+
+// Refresh keeps the KYC status in line with a changed profile. A change to the verified
+// identity or to a KYC image sends the customer back to unverified and withdraws the
+// pending verification; a change to another scoring input rescores a verified customer.
+func (s *KycService) Refresh(ctx context.Context, customer customerDBModels.Customer, previous customerProfileDBModels.CustomerProfile, profile customerProfileDBModels.CustomerProfile) error {
+	if !IdentityChanged(previous, profile) {
+		if customer.IsKycVerified() && scoring.InputsChanged(previous, profile) {
+			return s.CreditService.RescoreLimits(ctx, profile, limitService.ACTOR_SYSTEM)
+		}
+		return nil
+	}
+
+	if customer.KycStatus == customerDBModels.KYC_STATUS_PENDING {
+		pending, err := s.KycVerificationDBClient.Get(ctx, map[string]interface{}{
+			kycVerificationDBModels.COLUMN_CUSTOMER_ID: customer.ID,
+			kycVerificationDBModels.COLUMN_STATUS:      kycVerificationDBModels.STATUS_PENDING,
+		})
+		if err != nil {
+			return err
+		}
+
+		if pending.ID != 0 {
+			now := time.Now()
+
+			patcher := map[string]interface{}{
+				kycVerificationDBModels.COLUMN_STATUS:      kycVerificationDBModels.STATUS_REJECTED,
+				kycVerificationDBModels.COLUMN_REASON:      REASON_SUPERSEDED,
+				kycVerificationDBModels.COLUMN_REVIEWER:    limitService.ACTOR_SYSTEM,
+				kycVerificationDBModels.COLUMN_REVIEWED_AT: now,
+				kycVerificationDBModels.COLUMN_UPDATED_AT:  now,
+			}
+
+			if err := s.KycVerificationDBClient.Update(ctx, map[string]interface{}{kycVerificationDBModels.COLUMN_ID: pending.ID}, patcher); err != nil {
+				return err
+			}
+		}
+	}
+
+	if customer.KycStatus == customerDBModels.KYC_STATUS_UNVERIFIED {
+		return nil
+	}
+
+	return s.apply(ctx, customer.ID, customerDBModels.KYC_STATUS_UNVERIFIED, profile)
+}
+
+// IdentityChanged reports whether the attributes checked during verification differ between the profiles.
+func IdentityChanged(previous customerProfileDBModels.CustomerProfile, profile customerProfileDBModels.CustomerProfile) bool {
+	return previous.NIK != profile.NIK ||
+		previous.LegalName != profile.LegalName ||
+		(profile.LegalName == "" && previous.FullName != profile.FullName) ||
+		!previous.DateOfBirth.Equal(profile.DateOfBirth.Time) ||
+		previous.KtpImage != profile.KtpImage ||
+		previous.SelfieImage != profile.SelfieImage
+}
+
+// apply copies the verification status to the customer and assigns limits once verified.
+func (s *KycService) apply(ctx context.Context, customerID int, status string, profile customerProfileDBModels.CustomerProfile) error {
+	patcher := map[string]interface{}{
+		customerDBModels.COLUMN_KYC_STATUS: status,
+		customerDBModels.COLUMN_UPDATED_AT: time.Now(),
+	}
+
+	if err := s.CustomerDBClient.Update(ctx, map[string]interface{}{customerDBModels.COLUMN_ID: customerID}, patcher); err != nil {
+		return err
+	}
+
+	if status != kycVerificationDBModels.STATUS_VERIFIED || profile.ID == 0 {
+		return nil
+	}
+
+	return s.CreditService.AssignLimits(ctx, profile, limitService.ACTOR_SYSTEM)
+}
+
+func (s *KycService) read(ctx context.Context, key string) ([]byte, error) {
+	blob, err := s.BlobStore.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+
+	return io.ReadAll(blob)
+}
+
+func normalizeName(name string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(name), " "))
+}
+
+// sameDate compares two dates of birth, accepting the DD-MM-YYYY format printed on the KTP.
+func sameDate(a string, b string) bool {
+	parse := func(value string) (time.Time, error) {
+		if t, err := time.Parse("02-01-2006", value); err == nil {
+			return t, nil
+		}
+		return util.ParseTime(value)
+	}
+
+	dateA, err := parse(a)
+	if err != nil {
+		return false
+	}
+
+	dateB, err := parse(b)
+	if err != nil {
+		return false
+	}
+
+	return dateA.Format("2006-01-02") == dateB.Format("2006-01-02")
+}
@@ -0,0 +1,137 @@
+package limit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"kredit-plus/app/constants"
+	customerLimitDBModels "kredit-plus/app/db/dto/customer_limit"
+	customerLimitHistoryDBModels "kredit-plus/app/db/dto/customer_limit_history"
+	customerLimitDB "kredit-plus/app/db/repository/customer_limit"
+	"kredit-plus/app/service/correlation"
+)
+
+const (
+	ACTOR_SYSTEM          = "system"
+	ACTOR_ADMIN_PREFIX    = "admin:"
+	ACTOR_CUSTOMER_PREFIX = "customer:"
+
+	REASON_CREDIT_SCORING = "limit derived from credit score"
+	REASON_CHECKOUT       = "checkout"
+	REASON_LIMIT_REVIEW   = "periodic limit review"
+	REASON_LIMIT_INCREASE = "limit increase request approved"
+)
+
+// ValidUntil returns the end of the validity of a limit granted at the given time.
+func ValidUntil(from time.Time) time.Time {
+	return from.AddDate(0, constants.Config.LimitConfig.LIMIT_VALIDITY_MONTHS, 0)
+}
+
+// CustomerActor returns the actor recorded for changes made by a customer.
+func CustomerActor(customerUUID interface{}) string {
+	if s, ok := customerUUID.(string); ok {
+		return ACTOR_CUSTOMER_PREFIX + s
+	}
+	return ACTOR_CUSTOMER_PREFIX
+}
+
+// AdminActor returns the actor recorded for changes made by an admin.
+func AdminActor(adminUUID interface{}) string {
+	if s, ok := adminUUID.(string); ok {
+		return ACTOR_ADMIN_PREFIX + s
+	}
+	return ACTOR_ADMIN_PREFIX
+}
+
+// ErrInsufficientLimit is returned when a debit is larger than the remaining limit.
+var ErrInsufficientLimit = errors.New(constants.INSUFFICIENT_LIMIT)
+
+// ILimitService changes customer limits and records every change in the limit history,
+// in the same database transaction as the change.
+type ILimitService interface {
+	Create(ctx context.Context, customerLimit *customerLimitDBModels.CustomerLimit, actor string, reason string) error
+	Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}, actor string, reason string) error
+	Delete(ctx context.Context, filter map[string]interface{}, actor string, reason string) error
+	Debit(ctx context.Context, customerLimit customerLimitDBModels.CustomerLimit, amount float32, actor string, reason string) error
+	Grant(ctx context.Context, customerLimit *customerLimitDBModels.CustomerLimit, grantedAmount float32, actor string, reason string) error
+}
+
+type LimitService struct {
+	CustomerLimitDBClient customerLimitDB.ICustomerLimitRepository
+}
+
+func NewLimitService(CustomerLimitClient customerLimitDB.ICustomerLimitRepository) *LimitService {
+	return &LimitService{
+		CustomerLimitDBClient: CustomerLimitClient,
+	}
+}
+
+// Create stores a new limit. Limits without validity are valid from now for the configured period.
+func (s *LimitService) Create(ctx context.Context, customerLimit *customerLimitDBModels.CustomerLimit, actor string, reason string) error {
+	s.defaults(customerLimit)
+
+	return s.CustomerLimitDBClient.CreateWithHistory(ctx, customerLimit, history(ctx, customerLimitHistoryDBModels.ACTION_CREATE, actor, reason))
+}
+
+func (s *LimitService) Update(ctx context.Context, filter map[string]interface{}, patch map[string]interface{}, actor string, reason string) error {
+	return s.CustomerLimitDBClient.UpdateWithHistory(ctx, filter, patch, history(ctx, customerLimitHistoryDBModels.ACTION_UPDATE, actor, reason))
+}
+
+func (s *LimitService) Delete(ctx context.Context, filter map[string]interface{}, actor string, reason string) error {
+	return s.CustomerLimitDBClient.DeleteWithHistory(ctx, filter, history(ctx, customerLimitHistoryDBModels.ACTION_DELETE, actor, reason))
+}
+
+// Debit lowers the limit by amount, e.g. when a checkout uses part of it. The limit is
+// checked and lowered in the database, the amount of customerLimit may be stale.
+func (s *LimitService) Debit(ctx context.Context, customerLimit customerLimitDBModels.CustomerLimit, amount float32, actor string, reason string) error {
+	if customerLimit.ID == 0 {
+		return errors.New(constants.RESOURCE_NOT_FOUND)
+	}
+
+	debited, err := s.CustomerLimitDBClient.Debit(ctx, customerLimit.ID, amount, history(ctx, customerLimitHistoryDBModels.ACTION_DEBIT, actor, reason))
+	if err != nil {
+		return err
+	}
+
+	if !debited {
+		return ErrInsufficientLimit
+	}
+
+	return nil
+}
+
+// Grant sets the limit of the tenor to the granted amount less the amount in use, creating
+// it when the customer has none. Limits without validity are valid from now for the
+// configured period.
+func (s *LimitService) Grant(ctx context.Context, customerLimit *customerLimitDBModels.CustomerLimit, grantedAmount float32, actor string, reason string) error {
+	s.defaults(customerLimit)
+
+	// The repository records a create or an update, depending on whether the limit exists
+	return s.CustomerLimitDBClient.Grant(ctx, customerLimit, grantedAmount, history(ctx, "", actor, reason))
+}
+
+func (s *LimitService) defaults(customerLimit *customerLimitDBModels.CustomerLimit) {
+	if customerLimit.ValidFrom.IsZero() {
+		customerLimit.ValidFrom = time.Now()
+	}
+
+	if customerLimit.ValidUntil == nil {
+		validUntil := ValidUntil(customerLimit.ValidFrom)
+		customerLimit.ValidUntil = &validUntil
+	}
+
+	if customerLimit.Status == "" {
+		customerLimit.Status = customerLimitDBModels.STATUS_ACTIVE
+	}
+}
+
+// history returns the history of a change, the repository fills in the limit and its amounts.
+func history(ctx context.Context, action string, actor string, reason string) customerLimitHistoryDBModels.CustomerLimitHistory {
+	return customerLimitHistoryDBModels.CustomerLimitHistory{
+		Action:        action,
+		Actor:         actor,
+		Reason:        reason,
+		CorrelationID: correlation.ContextCorrelationId(ctx),
+	}
+}
@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"kredit-plus/app/service/logger"
+
+	"github.com/google/uuid"
+)
+
+// FileMailer writes every message as an .eml file instead of sending it.
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func NewFileMailer(dir string, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileMailer{Dir: dir, From: from}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, message Message) error {
+	now := time.Now()
+
+	data, err := encode(m.From, message, now)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102150405"), uuid.NewString())
+	path := filepath.Join(m.Dir, name)
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+
+	logger.Logger(ctx).Infof("mail to %s written to %s", message.To, path)
+
+	return nil
+}
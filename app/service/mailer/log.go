@@ -0,0 +1,21 @@
+package mailer
+
+import (
+	"context"
+
+	"kredit-plus/app/service/logger"
+)
+
+// LogMailer writes every message to the application log instead of sending it.
+type LogMailer struct {
+	From string
+}
+
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{From: from}
+}
+
+func (m *LogMailer) Send(ctx context.Context, message Message) error {
+	logger.Logger(ctx).Infof("mail from %s to %s: %s\n%s", m.From, message.To, message.Subject, message.Body)
+	return nil
+}
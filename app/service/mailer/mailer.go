@@ -0,0 +1,68 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"kredit-plus/config"
+)
+
+const (
+	DRIVER_SMTP = "smtp"
+	DRIVER_FILE = "file"
+	DRIVER_LOG  = "log"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email messages.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// NewMailerFromConfig returns the mailer named by MAIL_DRIVER.
+func NewMailerFromConfig(cfg config.MailConfig) (Mailer, error) {
+	switch cfg.MAIL_DRIVER {
+	case DRIVER_SMTP:
+		return NewSMTPMailer(cfg.SMTP_HOST, cfg.SMTP_PORT, cfg.SMTP_USERNAME, cfg.SMTP_PASSWORD, cfg.MAIL_FROM), nil
+	case DRIVER_FILE:
+		return NewFileMailer(cfg.MAIL_FILE_DIR, cfg.MAIL_FROM)
+	case DRIVER_LOG:
+		return NewLogMailer(cfg.MAIL_FROM), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.MAIL_DRIVER)
+	}
+}
+
+// encode renders the message in RFC 5322 format. Header values may not contain line
+// breaks, which would let a caller inject extra headers.
+func encode(from string, message Message, now time.Time) ([]byte, error) {
+	for _, value := range []string{from, message.To, message.Subject} {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, errors.New("mail header contains a line break")
+		}
+	}
+
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", message.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+
+	return b.Bytes(), nil
+}
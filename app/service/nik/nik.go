@@ -0,0 +1,115 @@
+package nik
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"kredit-plus/app/constants"
+)
+
+const (
+	LENGTH = 16
+
+	GENDER_MALE   = "male"
+	GENDER_FEMALE = "female"
+
+	// FEMALE_DAY_OFFSET is added to the day of birth of women
+	FEMALE_DAY_OFFSET = 40
+)
+
+// NIK is a parsed Nomor Induk Kependudukan:
+// PPRRDD (province, regency, district) DDMMYY (date of birth) SSSS (serial).
+type NIK struct {
+	Value        string    `json:"value"`
+	ProvinceCode string    `json:"province_code"`
+	RegencyCode  string    `json:"regency_code"`
+	DistrictCode string    `json:"district_code"`
+	Province     string    `json:"province,omitempty"`
+	Regency      string    `json:"regency,omitempty"`
+	District     string    `json:"district,omitempty"`
+	DateOfBirth  time.Time `json:"date_of_birth"`
+	Gender       string    `json:"gender"`
+	Serial       string    `json:"serial"`
+}
+
+// Parse checks the structure and region codes of a NIK and decodes its attributes.
+// The two digit birth year is placed in the most recent century that is not in the future.
+func Parse(value string) (NIK, error) {
+	return parse(value, time.Now(), regions)
+}
+
+func parse(value string, now time.Time, regions regionTable) (NIK, error) {
+	if len(value) != LENGTH {
+		return NIK{}, errors.New(constants.INVALID_NIK)
+	}
+
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return NIK{}, errors.New(constants.INVALID_NIK)
+		}
+	}
+
+	n := NIK{
+		Value:        value,
+		ProvinceCode: value[0:2],
+		RegencyCode:  value[0:4],
+		DistrictCode: value[0:6],
+		Serial:       value[12:16],
+	}
+
+	var ok bool
+	if n.Province, ok = regions.lookup(n.ProvinceCode); !ok {
+		return NIK{}, errors.New(constants.INVALID_NIK_REGION)
+	}
+
+	if value[2:4] == "00" || value[4:6] == "00" {
+		return NIK{}, errors.New(constants.INVALID_NIK_REGION)
+	}
+
+	if n.Regency, ok = regions.lookup(n.RegencyCode); !ok {
+		return NIK{}, errors.New(constants.INVALID_NIK_REGION)
+	}
+
+	if n.District, ok = regions.lookup(n.DistrictCode); !ok {
+		return NIK{}, errors.New(constants.INVALID_NIK_REGION)
+	}
+
+	if value[12:16] == "0000" {
+		return NIK{}, errors.New(constants.INVALID_NIK)
+	}
+
+	day, _ := strconv.Atoi(value[6:8])
+	month, _ := strconv.Atoi(value[8:10])
+	year, _ := strconv.Atoi(value[10:12])
+
+	n.Gender = GENDER_MALE
+	if day > FEMALE_DAY_OFFSET {
+		n.Gender = GENDER_FEMALE
+		day -= FEMALE_DAY_OFFSET
+	}
+
+	year += now.Year() / 100 * 100
+	if year > now.Year() {
+		year -= 100
+	}
+
+	dateOfBirth := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+
+	// time.Date normalizes out of range values, e.g. 31 February, so compare the parts back
+	if month < 1 || month > 12 || day < 1 || dateOfBirth.Day() != day || int(dateOfBirth.Month()) != month {
+		return NIK{}, errors.New(constants.INVALID_NIK_DATE_OF_BIRTH)
+	}
+
+	n.DateOfBirth = dateOfBirth
+
+	return n, nil
+}
+
+// MatchesDateOfBirth reports whether the NIK encodes the given date of birth.
+// Only the last two digits of the year are encoded, so the century is not compared.
+func (n NIK) MatchesDateOfBirth(dateOfBirth time.Time) bool {
+	return n.DateOfBirth.Day() == dateOfBirth.Day() &&
+		n.DateOfBirth.Month() == dateOfBirth.Month() &&
+		n.DateOfBirth.Year()%100 == dateOfBirth.Year()%100
+}
@@ -0,0 +1,146 @@
+package nik
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"kredit-plus/app/constants"
+)
+
+const testRegionsCSV = `31,DKI JAKARTA
+3171,KOTA JAKARTA SELATAN
+317101,JAGAKARSA
+`
+
+func testRegions(t *testing.T) regionTable {
+	t.Helper()
+
+	table, err := loadRegions(testRegionsCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+func TestParseDecodesDateOfBirthAndGender(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		value       string
+		dateOfBirth time.Time
+		gender      string
+	}{
+		{"man", "3171011505900001", time.Date(1990, time.May, 15, 0, 0, 0, 0, time.UTC), GENDER_MALE},
+		{"woman has 40 added to the day", "3171015505900001", time.Date(1990, time.May, 15, 0, 0, 0, 0, time.UTC), GENDER_FEMALE},
+		{"woman born on the first", "3171014101000001", time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC), GENDER_FEMALE},
+		{"woman born on the 31st", "3171017112990001", time.Date(1999, time.December, 31, 0, 0, 0, 0, time.UTC), GENDER_FEMALE},
+		{"man born on the 31st", "3171013101050001", time.Date(2005, time.January, 31, 0, 0, 0, 0, time.UTC), GENDER_MALE},
+		{"year not in the future is this century", "3171010110260001", time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), GENDER_MALE},
+		{"year in the future is the last century", "3171010110270001", time.Date(1927, time.October, 1, 0, 0, 0, 0, time.UTC), GENDER_MALE},
+		{"leap day", "3171012902000001", time.Date(2000, time.February, 29, 0, 0, 0, 0, time.UTC), GENDER_MALE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := parse(tt.value, now, testRegions(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !n.DateOfBirth.Equal(tt.dateOfBirth) {
+				t.Errorf("date of birth = %s, want %s", n.DateOfBirth.Format("2006-01-02"), tt.dateOfBirth.Format("2006-01-02"))
+			}
+
+			if n.Gender != tt.gender {
+				t.Errorf("gender = %s, want %s", n.Gender, tt.gender)
+			}
+
+			if n.Province != "DKI JAKARTA" || n.Regency != "KOTA JAKARTA SELATAN" || n.District != "JAGAKARSA" {
+				t.Errorf("regions = %s, %s, %s", n.Province, n.Regency, n.District)
+			}
+		})
+	}
+}
+
+func TestParseRejectsInvalidNIK(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		err   string
+	}{
+		{"too short", "317101150590001", constants.INVALID_NIK},
+		{"not a number", "31710115059O0001", constants.INVALID_NIK},
+		{"zero serial", "3171011505900000", constants.INVALID_NIK},
+		{"unknown province", "9971011505900001", constants.INVALID_NIK_REGION},
+		{"unknown regency", "3174011505900001", constants.INVALID_NIK_REGION},
+		{"unknown district", "3171021505900001", constants.INVALID_NIK_REGION},
+		{"day zero", "3171010005900001", constants.INVALID_NIK_DATE_OF_BIRTH},
+		{"day 40 is neither a man nor a woman", "3171014005900001", constants.INVALID_NIK_DATE_OF_BIRTH},
+		{"day past the offset range", "3171017205900001", constants.INVALID_NIK_DATE_OF_BIRTH},
+		{"31 February of a woman", "3171017102900001", constants.INVALID_NIK_DATE_OF_BIRTH},
+		{"month 13", "3171011513900001", constants.INVALID_NIK_DATE_OF_BIRTH},
+		{"29 February of a common year", "3171012902010001", constants.INVALID_NIK_DATE_OF_BIRTH},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parse(tt.value, now, testRegions(t)); err == nil || err.Error() != tt.err {
+				t.Errorf("error = %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestMatchesDateOfBirth(t *testing.T) {
+	n, err := parse("3171015505900001", time.Now(), testRegions(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !n.MatchesDateOfBirth(time.Date(1990, time.May, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Error("date of birth encoded in the NIK does not match")
+	}
+
+	if n.MatchesDateOfBirth(time.Date(1990, time.May, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Error("another day of birth matches")
+	}
+}
+
+func TestLoadRegionsRejectsMalformedTables(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{"missing name", "31\n"},
+		{"code of five digits", "31,DKI JAKARTA\n31710,JAGAKARSA\n"},
+		{"code listed twice", "31,DKI JAKARTA\n31,DKI JAKARTA\n"},
+		{"district without regency", "31,DKI JAKARTA\n317101,JAGAKARSA\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadRegions(tt.csv); err == nil {
+				t.Error("malformed table was loaded")
+			}
+		})
+	}
+}
+
+func TestCompleteRequiresDistricts(t *testing.T) {
+	if err := testRegions(t).complete(); err != nil {
+		t.Errorf("complete table reported as incomplete: %v", err)
+	}
+
+	table, err := loadRegions(testRegionsCSV + "3172,KOTA JAKARTA TIMUR\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := table.complete(); err == nil || !strings.Contains(err.Error(), "3172") {
+		t.Errorf("error = %v, want the regency without districts named", err)
+	}
+}
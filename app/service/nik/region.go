@@ -0,0 +1,118 @@
+package nik
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// regions.csv holds "code,name" rows of the Kemendagri region codes: two digit
+// provinces, four digit regencies and six digit districts. Only codes in the table
+// are valid, so it has to hold the full list down to the districts.
+//
+//go:embed regions.csv
+var regionsCSV string
+
+var regions, regionsErr = loadRegions(regionsCSV)
+
+// CheckRegions reports an error when the embedded region table is malformed or
+// incomplete. It is checked at startup, an incomplete table would reject valid NIKs.
+func CheckRegions() error {
+	if regionsErr != nil {
+		return regionsErr
+	}
+	return regions.complete()
+}
+
+type regionTable struct {
+	names map[string]string
+	// children counts the codes one level below each code
+	children map[string]int
+}
+
+func loadRegions(csv string) (regionTable, error) {
+	table := regionTable{
+		names:    map[string]string{},
+		children: map[string]int{},
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(csv))
+	for line := 1; scanner.Scan(); line++ {
+		row := strings.TrimSpace(scanner.Text())
+		if row == "" {
+			continue
+		}
+
+		code, name, ok := strings.Cut(row, ",")
+		code, name = strings.TrimSpace(code), strings.TrimSpace(name)
+		if !ok || name == "" || !isRegionCode(code) {
+			return regionTable{}, fmt.Errorf("regions.csv line %d: %q is not a code,name row", line, row)
+		}
+
+		if _, exist := table.names[code]; exist {
+			return regionTable{}, fmt.Errorf("regions.csv line %d: %s is listed twice", line, code)
+		}
+
+		table.names[code] = name
+
+		if len(code) > 2 {
+			table.children[code[:len(code)-2]]++
+		}
+	}
+
+	for code := range table.names {
+		if len(code) > 2 {
+			if _, exist := table.names[code[:len(code)-2]]; !exist {
+				return regionTable{}, fmt.Errorf("regions.csv: %s has no parent %s", code, code[:len(code)-2])
+			}
+		}
+	}
+
+	return table, nil
+}
+
+func isRegionCode(code string) bool {
+	if len(code) != 2 && len(code) != 4 && len(code) != 6 {
+		return false
+	}
+
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// complete reports the provinces without regencies and the regencies without districts.
+func (t regionTable) complete() error {
+	missing := []string{}
+	for code := range t.names {
+		if len(code) < 6 && t.children[code] == 0 {
+			missing = append(missing, code)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	sort.Strings(missing)
+
+	return fmt.Errorf("regions.csv is incomplete, %d provinces or regencies have no regions below them (%s), embed the full Kemendagri region list", len(missing), strings.Join(first(missing, 5), ", "))
+}
+
+func first(codes []string, n int) []string {
+	if len(codes) > n {
+		return append(codes[:n:n], "...")
+	}
+	return codes
+}
+
+// lookup returns the name of the region and whether the code is in the table.
+func (t regionTable) lookup(code string) (string, bool) {
+	name, ok := t.names[code]
+	return name, ok
+}
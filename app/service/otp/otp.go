@@ -0,0 +1,149 @@
+package otp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"kredit-plus/app/constants"
+	customerOtpDBModels "kredit-plus/app/db/dto/customer_otp"
+	customerOtpDB "kredit-plus/app/db/repository/customer_otp"
+	"kredit-plus/app/service/encryption"
+	"kredit-plus/config"
+)
+
+var (
+	ErrResendTooSoon   = errors.New(constants.OTP_RESEND_TOO_SOON)
+	ErrInvalidCode     = errors.New(constants.INVALID_OTP)
+	ErrTooManyAttempts = errors.New(constants.OTP_TOO_MANY_ATTEMPTS)
+)
+
+// IOTPService issues one time codes and checks the codes customers enter.
+type IOTPService interface {
+	Issue(ctx context.Context, customerID int, purpose string, target string) (string, error)
+	Check(ctx context.Context, customerID int, purpose string, target string, code string) error
+}
+
+type OTPService struct {
+	CustomerOtpDBClient customerOtpDB.ICustomerOtpRepository
+	Config              config.OtpConfig
+}
+
+func NewOTPService(CustomerOtpClient customerOtpDB.ICustomerOtpRepository, Config config.OtpConfig) (*OTPService, error) {
+	if err := encryption.CheckSecret(Config.OTP_SECRET); err != nil {
+		return nil, fmt.Errorf("OTP_SECRET: %w", err)
+	}
+
+	return &OTPService{
+		CustomerOtpDBClient: CustomerOtpClient,
+		Config:              Config,
+	}, nil
+}
+
+// Issue creates a new code for the target, unless the previous one was issued less than
+// OTP_RESEND_COOLDOWN_SECONDS ago. Only the latest code of a purpose can be used.
+func (s *OTPService) Issue(ctx context.Context, customerID int, purpose string, target string) (string, error) {
+	now := time.Now()
+
+	latest, err := s.CustomerOtpDBClient.Latest(ctx, customerID, purpose)
+	if err != nil {
+		return "", err
+	}
+
+	cooldown := time.Duration(s.Config.OTP_RESEND_COOLDOWN_SECONDS) * time.Second
+	if latest.ID != 0 && now.Sub(latest.CreatedAt) < cooldown {
+		return "", ErrResendTooSoon
+	}
+
+	code, err := s.generate()
+	if err != nil {
+		return "", err
+	}
+
+	record := customerOtpDBModels.CustomerOtp{
+		CustomerID: customerID,
+		Purpose:    purpose,
+		Target:     target,
+		CodeHash:   s.hash(code),
+		ExpiresAt:  now.Add(time.Duration(s.Config.OTP_TTL_SECONDS) * time.Second),
+		CreatedAt:  now,
+	}
+
+	if err := record.Validate(); err != nil {
+		return "", err
+	}
+
+	if err := s.CustomerOtpDBClient.Create(ctx, &record); err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// Check consumes the latest code of the purpose when it matches. Every code entered counts
+// as an attempt and the code stops working after OTP_MAX_ATTEMPTS attempts.
+func (s *OTPService) Check(ctx context.Context, customerID int, purpose string, target string, code string) error {
+	now := time.Now()
+
+	latest, err := s.CustomerOtpDBClient.Latest(ctx, customerID, purpose)
+	if err != nil {
+		return err
+	}
+
+	if latest.ID == 0 || latest.UsedAt != nil || !now.Before(latest.ExpiresAt) || latest.Target != target {
+		return ErrInvalidCode
+	}
+
+	// The attempt is counted before the code is compared, concurrent guesses beyond
+	// OTP_MAX_ATTEMPTS are refused without being compared
+	claimed, err := s.CustomerOtpDBClient.ClaimAttempt(ctx, latest.ID, s.Config.OTP_MAX_ATTEMPTS)
+	if err != nil {
+		return err
+	}
+
+	if !claimed {
+		return ErrTooManyAttempts
+	}
+
+	if !hmac.Equal([]byte(s.hash(code)), []byte(latest.CodeHash)) {
+		return ErrInvalidCode
+	}
+
+	used, err := s.CustomerOtpDBClient.Use(ctx, latest.ID, now, s.Config.OTP_MAX_ATTEMPTS)
+	if err != nil {
+		return err
+	}
+
+	if !used {
+		return ErrInvalidCode
+	}
+
+	return nil
+}
+
+func (s *OTPService) generate() (string, error) {
+	code := make([]byte, s.Config.OTP_LENGTH)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + n.Int64())
+	}
+
+	return string(code), nil
+}
+
+// hash keys the code with OTP_SECRET, as a plain hash of a short numeric code is
+// trivially reversed by trying every code.
+func (s *OTPService) hash(code string) string {
+	mac := hmac.New(sha256.New, []byte(s.Config.OTP_SECRET))
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,105 @@
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"kredit-plus/app/constants"
+	"kredit-plus/config"
+)
+
+// breached.txt holds one commonly used or leaked password per line, compared
+// case insensitively. Extend it from a published breach corpus as needed.
+//
+//go:embed breached.txt
+var breachedTXT string
+
+var breached = loadBreached(breachedTXT)
+
+var ErrBreached = errors.New(constants.PASSWORD_BREACHED)
+
+// Policy describes the passwords customers may choose.
+type Policy struct {
+	MinLength      int
+	MaxLength      int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSymbol  bool
+	RejectBreached bool
+}
+
+func NewPolicy(cfg config.PasswordPolicyConfig) Policy {
+	return Policy{
+		MinLength:      cfg.PASSWORD_MIN_LENGTH,
+		MaxLength:      cfg.PASSWORD_MAX_LENGTH,
+		RequireUpper:   cfg.PASSWORD_REQUIRE_UPPER,
+		RequireLower:   cfg.PASSWORD_REQUIRE_LOWER,
+		RequireDigit:   cfg.PASSWORD_REQUIRE_DIGIT,
+		RequireSymbol:  cfg.PASSWORD_REQUIRE_SYMBOL,
+		RejectBreached: cfg.PASSWORD_REJECT_BREACHED,
+	}
+}
+
+// Validate returns the first rule of the policy the password breaks.
+func (p Policy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return fmt.Errorf("password must be at least %d characters long", p.MinLength)
+	}
+
+	// bcrypt only uses the first 72 bytes of a password, so the maximum is in bytes
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		return fmt.Errorf("password must be at most %d bytes long", p.MaxLength)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	if p.RequireUpper && !upper {
+		return errors.New("password must contain an uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		return errors.New("password must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		return errors.New("password must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		return errors.New("password must contain a symbol")
+	}
+
+	if p.RejectBreached && breached[strings.ToLower(password)] {
+		return ErrBreached
+	}
+
+	return nil
+}
+
+func loadBreached(txt string) map[string]bool {
+	passwords := map[string]bool{}
+
+	scanner := bufio.NewScanner(strings.NewReader(txt))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			passwords[strings.ToLower(line)] = true
+		}
+	}
+
+	return passwords
+}
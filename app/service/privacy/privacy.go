@@ -0,0 +1,440 @@
+package privacy
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"path"
+	"time"
+
+	"kredit-plus/app/constants"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerAddressDBModels "kredit-plus/app/db/dto/customer_address"
+	customerEmergencyContactDBModels "kredit-plus/app/db/dto/customer_emergency_contact"
+	customerEmploymentDBModels "kredit-plus/app/db/dto/customer_employment"
+	customerLimitDBModels "kredit-plus/app/db/dto/customer_limit"
+	customerOtpDBModels "kredit-plus/app/db/dto/customer_otp"
+	customerProfileDBModels "kredit-plus/app/db/dto/customer_profile"
+	customerTokenDBModels "kredit-plus/app/db/dto/customer_token"
+	customerVerificationTokenDBModels "kredit-plus/app/db/dto/customer_verification_token"
+	kycVerificationDBModels "kredit-plus/app/db/dto/kyc_verification"
+	transactionDBModels "kredit-plus/app/db/dto/transaction"
+	customerDB "kredit-plus/app/db/repository/customer"
+	customerAddressDB "kredit-plus/app/db/repository/customer_address"
+	customerEmergencyContactDB "kredit-plus/app/db/repository/customer_emergency_contact"
+	customerEmploymentDB "kredit-plus/app/db/repository/customer_employment"
+	customerLimitDB "kredit-plus/app/db/repository/customer_limit"
+	customerOtpDB "kredit-plus/app/db/repository/customer_otp"
+	customerProfileDB "kredit-plus/app/db/repository/customer_profile"
+	customerTokenDB "kredit-plus/app/db/repository/customer_token"
+	customerVerificationTokenDB "kredit-plus/app/db/repository/customer_verification_token"
+	kycVerificationDB "kredit-plus/app/db/repository/kyc_verification"
+	transactionDB "kredit-plus/app/db/repository/transaction"
+	"kredit-plus/app/service/account"
+	"kredit-plus/app/service/dto/request"
+	customerResponse "kredit-plus/app/service/dto/response/customer"
+	"kredit-plus/app/service/profile"
+	"kredit-plus/app/service/storage"
+)
+
+const (
+	FORMAT_JSON = "json"
+	FORMAT_ZIP  = "zip"
+
+	REASON_CUSTOMER_REQUEST = "personal data erasure requested by the customer"
+)
+
+var (
+	ErrAlreadyErased     = errors.New(constants.CUSTOMER_ALREADY_ERASED)
+	ErrOutstandingLoans  = errors.New(constants.ERASURE_OUTSTANDING_LOANS)
+	ErrErasureNotAllowed = errors.New(constants.ERASURE_NOT_ALLOWED)
+)
+
+// outstanding lists the transaction statuses of loans that have not been settled
+var outstanding = []string{transactionDBModels.STATUS_PENDING, transactionDBModels.STATUS_ACTIVE}
+
+// IPrivacyService gives customers their personal data and erases it on request,
+// as required by the personal data protection law (UU PDP).
+type IPrivacyService interface {
+	Export(ctx context.Context, customer customerDBModels.Customer) (customerResponse.DataExport, error)
+	Archive(ctx context.Context, export customerResponse.DataExport, w io.Writer) error
+	Erase(ctx context.Context, customer customerDBModels.Customer, reason string, actor string) (customerDBModels.Customer, error)
+}
+
+type PrivacyService struct {
+	CustomerDBClient                  customerDB.ICustomerRepository
+	CustomerProfileDBClient           customerProfileDB.ICustomerProfileRepository
+	CustomerLimitDBClient             customerLimitDB.ICustomerLimitRepository
+	CustomerTokenDBClient             customerTokenDB.ICustomerTokenRepository
+	TransactionDBClient               transactionDB.ITransactionRepository
+	KycVerificationDBClient           kycVerificationDB.IKycVerificationRepository
+	CustomerVerificationTokenDBClient customerVerificationTokenDB.ICustomerVerificationTokenRepository
+	CustomerOtpDBClient               customerOtpDB.ICustomerOtpRepository
+	BlobStore                         storage.BlobStore
+	AccountService                    account.IAccountService
+	ProfileService                    profile.IProfileService
+	CustomerAddressDBClient           customerAddressDB.ICustomerAddressRepository
+	CustomerEmploymentDBClient        customerEmploymentDB.ICustomerEmploymentRepository
+	CustomerEmergencyContactDBClient  customerEmergencyContactDB.ICustomerEmergencyContactRepository
+}
+
+func NewPrivacyService(CustomerClient customerDB.ICustomerRepository, CustomerProfileClient customerProfileDB.ICustomerProfileRepository, CustomerLimitClient customerLimitDB.ICustomerLimitRepository, CustomerTokenClient customerTokenDB.ICustomerTokenRepository, TransactionClient transactionDB.ITransactionRepository, KycVerificationClient kycVerificationDB.IKycVerificationRepository, CustomerVerificationTokenClient customerVerificationTokenDB.ICustomerVerificationTokenRepository, CustomerOtpClient customerOtpDB.ICustomerOtpRepository, BlobStore storage.BlobStore, AccountService account.IAccountService, ProfileService profile.IProfileService, CustomerAddressClient customerAddressDB.ICustomerAddressRepository, CustomerEmploymentClient customerEmploymentDB.ICustomerEmploymentRepository, CustomerEmergencyContactClient customerEmergencyContactDB.ICustomerEmergencyContactRepository) *PrivacyService {
+	return &PrivacyService{
+		CustomerDBClient:                  CustomerClient,
+		CustomerProfileDBClient:           CustomerProfileClient,
+		CustomerLimitDBClient:             CustomerLimitClient,
+		CustomerTokenDBClient:             CustomerTokenClient,
+		TransactionDBClient:               TransactionClient,
+		KycVerificationDBClient:           KycVerificationClient,
+		CustomerVerificationTokenDBClient: CustomerVerificationTokenClient,
+		CustomerOtpDBClient:               CustomerOtpClient,
+		BlobStore:                         BlobStore,
+		AccountService:                    AccountService,
+		ProfileService:                    ProfileService,
+		CustomerAddressDBClient:           CustomerAddressClient,
+		CustomerEmploymentDBClient:        CustomerEmploymentClient,
+		CustomerEmergencyContactDBClient:  CustomerEmergencyContactClient,
+	}
+}
+
+// Export collects the customer, profile and its versions, addresses, employment, emergency contacts,
+// limits, sessions, transactions and KYC verifications.
+// Secrets such as the password hash and token values are left out.
+func (s *PrivacyService) Export(ctx context.Context, customer customerDBModels.Customer) (customerResponse.DataExport, error) {
+	customer.Password = ""
+
+	export := customerResponse.DataExport{
+		Customer:   customer,
+		ExportedAt: time.Now(),
+	}
+
+	profile, err := s.CustomerProfileDBClient.Get(ctx, map[string]interface{}{customerProfileDBModels.COLUMN_CUSTOMER_ID: customer.ID})
+	if err != nil {
+		return export, err
+	}
+
+	if profile.ID != 0 {
+		export.Profile = &profile
+	}
+
+	p := all()
+
+	if export.ProfileVersions, _, err = s.ProfileService.Versions(ctx, customer.ID, p); err != nil {
+		return export, err
+	}
+
+	if export.Addresses, _, err = s.CustomerAddressDBClient.List(ctx, p, map[string]interface{}{customerAddressDBModels.COLUMN_CUSTOMER_ID: customer.ID}); err != nil {
+		return export, err
+	}
+
+	employment, err := s.CustomerEmploymentDBClient.Get(ctx, map[string]interface{}{customerEmploymentDBModels.COLUMN_CUSTOMER_ID: customer.ID})
+	if err != nil {
+		return export, err
+	}
+
+	if employment.ID != 0 {
+		export.Employment = &employment
+	}
+
+	if export.EmergencyContacts, _, err = s.CustomerEmergencyContactDBClient.List(ctx, p, map[string]interface{}{customerEmergencyContactDBModels.COLUMN_CUSTOMER_ID: customer.ID}); err != nil {
+		return export, err
+	}
+
+	if export.Limits, _, err = s.CustomerLimitDBClient.List(ctx, p, map[string]interface{}{customerLimitDBModels.COLUMN_CUSTOMER_ID: customer.ID}); err != nil {
+		return export, err
+	}
+
+	tokens, _, err := s.CustomerTokenDBClient.List(ctx, p, map[string]interface{}{customerTokenDBModels.COLUMN_CUSTOMER_ID: customer.ID})
+	if err != nil {
+		return export, err
+	}
+
+	export.Sessions = make([]customerResponse.Session, 0, len(tokens))
+	for _, token := range tokens {
+		export.Sessions = append(export.Sessions, customerResponse.Session{
+			ID:                    token.ID,
+			UserAgent:             token.UserAgent,
+			IPAddress:             token.IPAddress,
+			AccessTokenExpiredAt:  token.AccessTokenExpiredAt,
+			RefreshTokenExpiredAt: token.RefreshTokenExpiredAt,
+			CreatedAt:             token.CreatedAt,
+		})
+	}
+
+	if export.Transactions, _, err = s.TransactionDBClient.List(ctx, p, map[string]interface{}{transactionDBModels.COLUMN_CUSTOMER_ID: customer.ID}); err != nil {
+		return export, err
+	}
+
+	if export.KycVerifications, _, err = s.KycVerificationDBClient.List(ctx, p, map[string]interface{}{kycVerificationDBModels.COLUMN_CUSTOMER_ID: customer.ID}); err != nil {
+		return export, err
+	}
+
+	return export, nil
+}
+
+// Archive writes the export as a ZIP archive with one JSON file per section, the KYC
+// images of the profile and the income proof.
+func (s *PrivacyService) Archive(ctx context.Context, export customerResponse.DataExport, w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	sections := []struct {
+		name string
+		data interface{}
+	}{
+		{"customer.json", export.Customer},
+		{"profile.json", export.Profile},
+		{"profile_versions.json", export.ProfileVersions},
+		{"addresses.json", export.Addresses},
+		{"employment.json", export.Employment},
+		{"emergency_contacts.json", export.EmergencyContacts},
+		{"limits.json", export.Limits},
+		{"sessions.json", export.Sessions},
+		{"transactions.json", export.Transactions},
+		{"kyc_verifications.json", export.KycVerifications},
+	}
+
+	for _, section := range sections {
+		file, err := archive.Create(section.name)
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(section.data); err != nil {
+			return err
+		}
+	}
+
+	if export.Profile != nil {
+		for _, key := range []string{export.Profile.KtpImage, export.Profile.SelfieImage} {
+			if key == "" {
+				continue
+			}
+
+			if err := s.copyBlob(ctx, archive, key); err != nil {
+				return err
+			}
+		}
+	}
+
+	if export.Employment != nil && export.Employment.IncomeProof != "" {
+		if err := s.copyBlob(ctx, archive, export.Employment.IncomeProof); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// Erase anonymizes the personal data of the customer and closes the account. Transactions,
+// limits, credit scores and their histories are kept for regulatory retention; only the
+// data identifying the customer is removed. Erasure is refused while loans are outstanding
+// and for blacklisted accounts, which are kept for fraud prevention.
+func (s *PrivacyService) Erase(ctx context.Context, customer customerDBModels.Customer, reason string, actor string) (customerDBModels.Customer, error) {
+	if customer.IsErased() {
+		return customer, ErrAlreadyErased
+	}
+
+	if customer.Status == customerDBModels.STATUS_BLACKLISTED {
+		return customer, ErrErasureNotAllowed
+	}
+
+	transactions, _, err := s.TransactionDBClient.List(ctx, all(), map[string]interface{}{transactionDBModels.COLUMN_CUSTOMER_ID: customer.ID})
+	if err != nil {
+		return customer, err
+	}
+
+	for _, transaction := range transactions {
+		for _, status := range outstanding {
+			if transaction.Status == status {
+				return customer, ErrOutstandingLoans
+			}
+		}
+	}
+
+	if customer.Status != customerDBModels.STATUS_CLOSED {
+		if customer, err = s.AccountService.ChangeStatus(ctx, customer, customerDBModels.STATUS_CLOSED, reason, actor); err != nil {
+			return customer, err
+		}
+	}
+
+	if err := s.eraseKyc(ctx, customer.ID); err != nil {
+		return customer, err
+	}
+
+	if err := s.eraseContactDetails(ctx, customer.ID); err != nil {
+		return customer, err
+	}
+
+	if err := s.CustomerTokenDBClient.Delete(ctx, map[string]interface{}{customerTokenDBModels.COLUMN_CUSTOMER_ID: customer.ID}); err != nil {
+		return customer, err
+	}
+
+	// Verification tokens and codes hold the email address and phone number they were sent to
+	if err := s.CustomerVerificationTokenDBClient.Delete(ctx, map[string]interface{}{customerVerificationTokenDBModels.COLUMN_CUSTOMER_ID: customer.ID}); err != nil {
+		return customer, err
+	}
+
+	if err := s.CustomerOtpDBClient.Delete(ctx, map[string]interface{}{customerOtpDBModels.COLUMN_CUSTOMER_ID: customer.ID}); err != nil {
+		return customer, err
+	}
+
+	now := time.Now()
+
+	filter := map[string]interface{}{
+		customerDBModels.COLUMN_ID: customer.ID,
+	}
+
+	patcher := map[string]interface{}{
+		customerDBModels.COLUMN_EMAIL:             nil,
+		customerDBModels.COLUMN_PHONE:             nil,
+		customerDBModels.COLUMN_PASSWORD:          "",
+		customerDBModels.COLUMN_EMAIL_VERIFIED_AT: nil,
+		customerDBModels.COLUMN_PHONE_VERIFIED_AT: nil,
+		customerDBModels.COLUMN_ERASED_AT:         now,
+		customerDBModels.COLUMN_UPDATED_AT:        now,
+	}
+
+	if err := s.CustomerDBClient.Update(ctx, filter, patcher); err != nil {
+		return customer, err
+	}
+
+	return s.CustomerDBClient.Get(ctx, filter)
+}
+
+// eraseKyc removes the identity data of the profile, its versions and every KYC verification,
+// and deletes the KYC images. Salary is kept as an input of the credit decisions.
+func (s *PrivacyService) eraseKyc(ctx context.Context, customerID int) error {
+	profileFilter := map[string]interface{}{
+		customerProfileDBModels.COLUMN_CUSTOMER_ID: customerID,
+	}
+
+	profile, err := s.CustomerProfileDBClient.Get(ctx, profileFilter)
+	if err != nil {
+		return err
+	}
+
+	verificationFilter := map[string]interface{}{
+		kycVerificationDBModels.COLUMN_CUSTOMER_ID: customerID,
+	}
+
+	verifications, _, err := s.KycVerificationDBClient.List(ctx, all(), verificationFilter)
+	if err != nil {
+		return err
+	}
+
+	keys := []string{profile.KtpImage, profile.SelfieImage}
+	for _, verification := range verifications {
+		keys = append(keys, verification.KtpImage, verification.SelfieImage)
+	}
+
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		if err := s.BlobStore.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+
+	if profile.ID != 0 {
+		patcher := map[string]interface{}{
+			customerProfileDBModels.COLUMN_NIK:            nil,
+			customerProfileDBModels.COLUMN_FULL_NAME:      nil,
+			customerProfileDBModels.COLUMN_LEGAL_NAME:     nil,
+			customerProfileDBModels.COLUMN_PLACE_OF_BIRTH: nil,
+			customerProfileDBModels.COLUMN_DATE_OF_BIRTH:  nil,
+			customerProfileDBModels.COLUMN_KTP_IMAGE:      nil,
+			customerProfileDBModels.COLUMN_SELFIE_IMAGE:   nil,
+			customerProfileDBModels.COLUMN_SELFIE_HASH:    nil,
+			customerProfileDBModels.COLUMN_UPDATED_AT:     now,
+		}
+
+		if err := s.CustomerProfileDBClient.Update(ctx, profileFilter, patcher); err != nil {
+			return err
+		}
+	}
+
+	if err := s.ProfileService.Erase(ctx, customerID); err != nil {
+		return err
+	}
+
+	if len(verifications) > 0 {
+		patcher := map[string]interface{}{
+			kycVerificationDBModels.COLUMN_KTP_IMAGE:         "",
+			kycVerificationDBModels.COLUMN_SELFIE_IMAGE:      "",
+			kycVerificationDBModels.COLUMN_OCR_NIK:           nil,
+			kycVerificationDBModels.COLUMN_OCR_NAME:          nil,
+			kycVerificationDBModels.COLUMN_OCR_DATE_OF_BIRTH: nil,
+			kycVerificationDBModels.COLUMN_REVIEW_NOTES:      nil,
+			kycVerificationDBModels.COLUMN_UPDATED_AT:        now,
+		}
+
+		if err := s.KycVerificationDBClient.Update(ctx, verificationFilter, patcher); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// eraseContactDetails deletes the addresses, employment with its income proof and the
+// emergency contacts, none of which is needed once the account is closed.
+func (s *PrivacyService) eraseContactDetails(ctx context.Context, customerID int) error {
+	employmentFilter := map[string]interface{}{
+		customerEmploymentDBModels.COLUMN_CUSTOMER_ID: customerID,
+	}
+
+	employment, err := s.CustomerEmploymentDBClient.Get(ctx, employmentFilter)
+	if err != nil {
+		return err
+	}
+
+	if employment.IncomeProof != "" {
+		if err := s.BlobStore.Delete(ctx, employment.IncomeProof); err != nil {
+			return err
+		}
+	}
+
+	if err := s.CustomerEmploymentDBClient.Delete(ctx, employmentFilter); err != nil {
+		return err
+	}
+
+	if err := s.CustomerAddressDBClient.Delete(ctx, map[string]interface{}{customerAddressDBModels.COLUMN_CUSTOMER_ID: customerID}); err != nil {
+		return err
+	}
+
+	return s.CustomerEmergencyContactDBClient.Delete(ctx, map[string]interface{}{customerEmergencyContactDBModels.COLUMN_CUSTOMER_ID: customerID})
+}
+
+func (s *PrivacyService) copyBlob(ctx context.Context, archive *zip.Writer, key string) error {
+	blob, err := s.BlobStore.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+
+	file, err := archive.Create(path.Join("kyc", path.Base(key)))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, blob)
+	return err
+}
+
+func all() request.Pagination {
+	p := request.Pagination{
+		GetAllData: true,
+	}
+
+	p.Validate()
+
+	return p
+}
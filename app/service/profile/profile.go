@@ -0,0 +1,280 @@
+package profile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"kredit-plus/app/constants"
+	customerProfileDBModels "kredit-plus/app/db/dto/customer_profile"
+	customerProfileVersionDBModels "kredit-plus/app/db/dto/customer_profile_version"
+	customerProfileDB "kredit-plus/app/db/repository/customer_profile"
+	customerProfileVersionDB "kredit-plus/app/db/repository/customer_profile_version"
+	"kredit-plus/app/service/correlation"
+	"kredit-plus/app/service/dto/request"
+	"kredit-plus/app/service/dto/response"
+	customerResponse "kredit-plus/app/service/dto/response/customer"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ErrVersionNotFound is returned when a requested profile version does not exist.
+var ErrVersionNotFound = errors.New(constants.PROFILE_VERSION_NOT_FOUND)
+
+// untracked are the profile fields that are not part of the profile data itself.
+var untracked = map[string]bool{
+	customerProfileDBModels.COLUMN_ID:          true,
+	customerProfileDBModels.COLUMN_CUSTOMER_ID: true,
+	customerProfileDBModels.COLUMN_VERSION:     true,
+	customerProfileDBModels.COLUMN_CREATED_AT:  true,
+	customerProfileDBModels.COLUMN_UPDATED_AT:  true,
+}
+
+// IProfileService changes customer profiles and keeps every change as an immutable version.
+type IProfileService interface {
+	Create(ctx context.Context, customerProfile *customerProfileDBModels.CustomerProfile, actor string) error
+	Update(ctx context.Context, customerID int, patch map[string]interface{}, actor string) (customerProfileDBModels.CustomerProfile, error)
+	Delete(ctx context.Context, customerID int, actor string) error
+	Versions(ctx context.Context, customerID int, pagination request.Pagination) ([]customerResponse.ProfileVersion, response.Pagination, error)
+	Diff(ctx context.Context, customerID int, from int, to int) (customerResponse.ProfileDiff, error)
+	Erase(ctx context.Context, customerID int) error
+}
+
+type ProfileService struct {
+	CustomerProfileDBClient        customerProfileDB.ICustomerProfileRepository
+	CustomerProfileVersionDBClient customerProfileVersionDB.ICustomerProfileVersionRepository
+}
+
+func NewProfileService(CustomerProfileClient customerProfileDB.ICustomerProfileRepository, CustomerProfileVersionClient customerProfileVersionDB.ICustomerProfileVersionRepository) *ProfileService {
+	return &ProfileService{
+		CustomerProfileDBClient:        CustomerProfileClient,
+		CustomerProfileVersionDBClient: CustomerProfileVersionClient,
+	}
+}
+
+// Create stores the profile, replacing the current profile of the customer if there is one.
+func (s *ProfileService) Create(ctx context.Context, customerProfile *customerProfileDBModels.CustomerProfile, actor string) error {
+	return s.CustomerProfileDBClient.CreateVersioned(ctx, customerProfile, s.version(ctx, actor))
+}
+
+// Update patches the profile of the customer and returns the updated profile.
+func (s *ProfileService) Update(ctx context.Context, customerID int, patch map[string]interface{}, actor string) (customerProfileDBModels.CustomerProfile, error) {
+	customerProfile, err := s.CustomerProfileDBClient.UpdateVersioned(ctx, customerID, patch, s.version(ctx, actor))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return customerProfile, errors.New(constants.RESOURCE_NOT_FOUND)
+	}
+
+	return customerProfile, err
+}
+
+// Delete removes the profile of the customer. Its earlier versions are kept.
+func (s *ProfileService) Delete(ctx context.Context, customerID int, actor string) error {
+	return s.CustomerProfileDBClient.DeleteVersioned(ctx, customerID, s.version(ctx, actor))
+}
+
+// Versions lists the recorded versions of the profile of the customer.
+func (s *ProfileService) Versions(ctx context.Context, customerID int, pagination request.Pagination) ([]customerResponse.ProfileVersion, response.Pagination, error) {
+	filter := map[string]interface{}{
+		customerProfileVersionDBModels.COLUMN_CUSTOMER_ID: customerID,
+	}
+
+	versions, paginationResponse, err := s.CustomerProfileVersionDBClient.List(ctx, pagination, filter)
+	if err != nil {
+		return nil, paginationResponse, err
+	}
+
+	result := make([]customerResponse.ProfileVersion, 0, len(versions))
+	for _, version := range versions {
+		customerProfile, err := snapshot(version)
+		if err != nil {
+			return nil, paginationResponse, err
+		}
+
+		result = append(result, customerResponse.ProfileVersion{
+			Version:       version.Version,
+			Action:        version.Action,
+			ChangedFields: version.Fields(),
+			Actor:         version.Actor,
+			CreatedAt:     version.CreatedAt,
+			Profile:       customerProfile,
+		})
+	}
+
+	return result, paginationResponse, nil
+}
+
+// Diff compares two versions of the profile of the customer field by field.
+func (s *ProfileService) Diff(ctx context.Context, customerID int, from int, to int) (customerResponse.ProfileDiff, error) {
+	diff := customerResponse.ProfileDiff{
+		From: from,
+		To:   to,
+	}
+
+	profiles := make([]*customerProfileDBModels.CustomerProfile, 2)
+
+	for i, number := range []int{from, to} {
+		version, err := s.CustomerProfileVersionDBClient.Get(ctx, map[string]interface{}{
+			customerProfileVersionDBModels.COLUMN_CUSTOMER_ID: customerID,
+			customerProfileVersionDBModels.COLUMN_VERSION:     number,
+		})
+		if err != nil {
+			return diff, err
+		}
+
+		if version.ID == 0 {
+			return diff, ErrVersionNotFound
+		}
+
+		if profiles[i], err = snapshot(version); err != nil {
+			return diff, err
+		}
+	}
+
+	changes, err := compare(profiles[0], profiles[1])
+	if err != nil {
+		return diff, err
+	}
+
+	diff.Changes = changes
+
+	return diff, nil
+}
+
+// Erase removes the profile data kept in the versions of the customer.
+func (s *ProfileService) Erase(ctx context.Context, customerID int) error {
+	return s.CustomerProfileVersionDBClient.ClearSnapshots(ctx, customerID)
+}
+
+// version returns the VersionFunc recording a change made by the actor. The profile
+// repository writes the version in the same transaction as the change.
+func (s *ProfileService) version(ctx context.Context, actor string) customerProfileDB.VersionFunc {
+	return func(previous customerProfileDBModels.CustomerProfile, customerProfile *customerProfileDBModels.CustomerProfile) (customerProfileVersionDBModels.CustomerProfileVersion, error) {
+		var before *customerProfileDBModels.CustomerProfile
+		if previous.ID != 0 {
+			before = &previous
+		}
+
+		action := customerProfileVersionDBModels.ACTION_UPDATE
+		switch {
+		case customerProfile == nil:
+			action = customerProfileVersionDBModels.ACTION_DELETE
+		case before == nil:
+			action = customerProfileVersionDBModels.ACTION_CREATE
+		}
+
+		changes, err := compare(before, customerProfile)
+		if err != nil {
+			return customerProfileVersionDBModels.CustomerProfileVersion{}, err
+		}
+
+		fields := make([]string, 0, len(changes))
+		for _, change := range changes {
+			fields = append(fields, change.Field)
+		}
+
+		customerProfileVersion := customerProfileVersionDBModels.CustomerProfileVersion{
+			CustomerID:    previous.CustomerID,
+			Action:        action,
+			ChangedFields: strings.Join(fields, ","),
+			Actor:         actor,
+			CorrelationID: correlation.ContextCorrelationId(ctx),
+			CreatedAt:     time.Now(),
+		}
+
+		if customerProfile != nil {
+			data, err := json.Marshal(customerProfile)
+			if err != nil {
+				return customerProfileVersion, err
+			}
+
+			customerProfileVersion.CustomerID = customerProfile.CustomerID
+			customerProfileVersion.Snapshot = string(data)
+		}
+
+		return customerProfileVersion, nil
+	}
+}
+
+// snapshot returns the profile stored in the version, or nil when it holds none.
+func snapshot(version customerProfileVersionDBModels.CustomerProfileVersion) (*customerProfileDBModels.CustomerProfile, error) {
+	if version.Snapshot == "" {
+		return nil, nil
+	}
+
+	var customerProfile customerProfileDBModels.CustomerProfile
+	if err := json.Unmarshal([]byte(version.Snapshot), &customerProfile); err != nil {
+		return nil, err
+	}
+
+	return &customerProfile, nil
+}
+
+// compare returns the tracked fields that differ between two profiles, sorted by name.
+// A nil profile has no values.
+func compare(from *customerProfileDBModels.CustomerProfile, to *customerProfileDBModels.CustomerProfile) ([]customerResponse.FieldChange, error) {
+	before, err := fields(from)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := fields(to)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+
+	changes := []customerResponse.FieldChange{}
+	for name := range names {
+		if untracked[name] || reflect.DeepEqual(before[name], after[name]) {
+			continue
+		}
+
+		changes = append(changes, customerResponse.FieldChange{
+			Field: name,
+			From:  before[name],
+			To:    after[name],
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+
+	return changes, nil
+}
+
+// fields returns the profile as its JSON fields, leaving out empty ones.
+func fields(customerProfile *customerProfileDBModels.CustomerProfile) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	if customerProfile == nil {
+		return result, nil
+	}
+
+	data, err := json.Marshal(customerProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	for name, value := range result {
+		if value == nil || value == "" || value == float64(0) {
+			delete(result, name)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,151 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"kredit-plus/app/constants"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	roleDBModels "kredit-plus/app/db/dto/role"
+	rolePermissionDBModels "kredit-plus/app/db/dto/role_permission"
+	customerDB "kredit-plus/app/db/repository/customer"
+	roleDB "kredit-plus/app/db/repository/role"
+	rolePermissionDB "kredit-plus/app/db/repository/role_permission"
+	"kredit-plus/app/service/dto/request"
+)
+
+// Permissions checked by the API. The roles holding them are kept in role_permissions.
+const (
+	PERMISSION_CUSTOMERS_READ         = "customers:read"
+	PERMISSION_CUSTOMERS_WRITE        = "customers:write"
+	PERMISSION_CUSTOMERS_DELETE       = "customers:delete"
+	PERMISSION_CUSTOMERS_STATUS       = "customers:status"
+	PERMISSION_CUSTOMERS_ERASE        = "customers:erase"
+	PERMISSION_CUSTOMERS_ROLE         = "customers:role"
+	PERMISSION_PROFILES_READ          = "profiles:read"
+	PERMISSION_LIMIT_INCREASES_REVIEW = "limit_increases:review"
+	PERMISSION_KYC_REVIEW             = "kyc:review"
+	PERMISSION_DUPLICATES_REVIEW      = "duplicates:review"
+
+	PERMISSION_TRANSACTIONS_READ_ALL      = "transactions:read_all"
+	PERMISSION_TRANSACTIONS_WRITE_ALL     = "transactions:write_all"
+	PERMISSION_TRANSACTIONS_READ_CHANNEL  = "transactions:read_channel"
+	PERMISSION_TRANSACTIONS_WRITE_CHANNEL = "transactions:write_channel"
+)
+
+var (
+	ErrInvalidRole          = errors.New(constants.INVALID_ROLE)
+	ErrSalesChannelRequired = errors.New(constants.SALES_CHANNEL_REQUIRED)
+)
+
+// IRBACService decides what the role of an account allows it to do.
+type IRBACService interface {
+	HasPermission(ctx context.Context, role string, permission string) (bool, error)
+	Permissions(ctx context.Context, role string) ([]string, error)
+	Roles(ctx context.Context) ([]roleDBModels.Role, error)
+	AssignRole(ctx context.Context, customer customerDBModels.Customer, role string, salesChannel string) (customerDBModels.Customer, error)
+}
+
+type RBACService struct {
+	CustomerDBClient       customerDB.ICustomerRepository
+	RoleDBClient           roleDB.IRoleRepository
+	RolePermissionDBClient rolePermissionDB.IRolePermissionRepository
+}
+
+func NewRBACService(CustomerClient customerDB.ICustomerRepository, RoleClient roleDB.IRoleRepository, RolePermissionClient rolePermissionDB.IRolePermissionRepository) *RBACService {
+	return &RBACService{
+		CustomerDBClient:       CustomerClient,
+		RoleDBClient:           RoleClient,
+		RolePermissionDBClient: RolePermissionClient,
+	}
+}
+
+// HasPermission reports whether the role has been granted the permission.
+func (s *RBACService) HasPermission(ctx context.Context, role string, permission string) (bool, error) {
+	if role == "" {
+		return false, nil
+	}
+
+	grant, err := s.RolePermissionDBClient.Get(ctx, map[string]interface{}{
+		rolePermissionDBModels.COLUMN_ROLE:       role,
+		rolePermissionDBModels.COLUMN_PERMISSION: permission,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return grant.ID != 0, nil
+}
+
+// Permissions lists the permissions granted to the role.
+func (s *RBACService) Permissions(ctx context.Context, role string) ([]string, error) {
+	grants, _, err := s.RolePermissionDBClient.List(ctx, all(), map[string]interface{}{rolePermissionDBModels.COLUMN_ROLE: role})
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		permissions = append(permissions, grant.Permission)
+	}
+
+	return permissions, nil
+}
+
+func (s *RBACService) Roles(ctx context.Context) ([]roleDBModels.Role, error) {
+	roles, _, err := s.RoleDBClient.List(ctx, all(), map[string]interface{}{})
+	return roles, err
+}
+
+// AssignRole gives the customer another role. Access tokens carrying the previous role
+// stop working, so the customer has to sign in again. Partners also need the sales
+// channel they act for; every other role drops it.
+func (s *RBACService) AssignRole(ctx context.Context, customer customerDBModels.Customer, role string, salesChannel string) (customerDBModels.Customer, error) {
+	r, err := s.RoleDBClient.Get(ctx, map[string]interface{}{roleDBModels.COLUMN_NAME: role})
+	if err != nil {
+		return customer, err
+	}
+
+	if r.ID == 0 {
+		return customer, ErrInvalidRole
+	}
+
+	if role == constants.PARTNER.String() && salesChannel == "" {
+		return customer, ErrSalesChannelRequired
+	}
+
+	if role != constants.PARTNER.String() {
+		salesChannel = ""
+	}
+
+	if customer.Role == role && customer.SalesChannel == salesChannel {
+		return customer, nil
+	}
+
+	filter := map[string]interface{}{
+		customerDBModels.COLUMN_ID: customer.ID,
+	}
+
+	patcher := map[string]interface{}{
+		customerDBModels.COLUMN_ROLE:          role,
+		customerDBModels.COLUMN_SALES_CHANNEL: salesChannel,
+		customerDBModels.COLUMN_UPDATED_AT:    time.Now(),
+	}
+
+	if err := s.CustomerDBClient.Update(ctx, filter, patcher); err != nil {
+		return customer, err
+	}
+
+	return s.CustomerDBClient.Get(ctx, filter)
+}
+
+func all() request.Pagination {
+	p := request.Pagination{
+		GetAllData: true,
+	}
+
+	p.Validate()
+
+	return p
+}
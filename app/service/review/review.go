@@ -0,0 +1,247 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerLimitDBModels "kredit-plus/app/db/dto/customer_limit"
+	customerLimitReviewDBModels "kredit-plus/app/db/dto/customer_limit_review"
+	customerProfileDBModels "kredit-plus/app/db/dto/customer_profile"
+	customerDB "kredit-plus/app/db/repository/customer"
+	customerLimitDB "kredit-plus/app/db/repository/customer_limit"
+	customerLimitReviewDB "kredit-plus/app/db/repository/customer_limit_review"
+	customerProfileDB "kredit-plus/app/db/repository/customer_profile"
+	"kredit-plus/app/service/correlation"
+	"kredit-plus/app/service/credit"
+	limitService "kredit-plus/app/service/limit"
+	"kredit-plus/app/service/logger"
+	"kredit-plus/app/service/scoring"
+	"kredit-plus/config"
+
+	"github.com/jinzhu/gorm/dialects/postgres"
+)
+
+// LimitChange is the outcome of a review for a single tenor.
+type LimitChange struct {
+	Tenor          int     `json:"tenor"`
+	OldLimitAmount float32 `json:"old_limit_amount"`
+	NewLimitAmount float32 `json:"new_limit_amount"`
+}
+
+// LOCK_NAME is the advisory lock held while a review runs.
+const LOCK_NAME = "limit-review"
+
+// ILimitReviewJob re-scores customers whose limits are about to expire.
+type ILimitReviewJob interface {
+	Start(ctx context.Context)
+	Run(ctx context.Context) error
+}
+
+type LimitReviewJob struct {
+	CustomerDBClient            customerDB.ICustomerRepository
+	CustomerLimitDBClient       customerLimitDB.ICustomerLimitRepository
+	CustomerProfileDBClient     customerProfileDB.ICustomerProfileRepository
+	CustomerLimitReviewDBClient customerLimitReviewDB.ICustomerLimitReviewRepository
+	CreditService               credit.ICreditService
+	LimitService                limitService.ILimitService
+	Locker                      db.ILocker
+	Config                      config.LimitConfig
+}
+
+func NewLimitReviewJob(CustomerClient customerDB.ICustomerRepository, CustomerLimitClient customerLimitDB.ICustomerLimitRepository, CustomerProfileClient customerProfileDB.ICustomerProfileRepository, CustomerLimitReviewClient customerLimitReviewDB.ICustomerLimitReviewRepository, CreditService credit.ICreditService, LimitService limitService.ILimitService, Locker db.ILocker) *LimitReviewJob {
+	return &LimitReviewJob{
+		CustomerDBClient:            CustomerClient,
+		CustomerLimitDBClient:       CustomerLimitClient,
+		CustomerProfileDBClient:     CustomerProfileClient,
+		CustomerLimitReviewDBClient: CustomerLimitReviewClient,
+		CreditService:               CreditService,
+		LimitService:                LimitService,
+		Locker:                      Locker,
+		Config:                      constants.Config.LimitConfig,
+	}
+}
+
+// Start runs the review every LIMIT_REVIEW_INTERVAL_MINUTES until the context is done.
+func (j *LimitReviewJob) Start(ctx context.Context) {
+	log := logger.Logger(ctx)
+
+	if j.Config.LIMIT_REVIEW_INTERVAL_MINUTES <= 0 {
+		log.Info("limit review job is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(j.Config.LIMIT_REVIEW_INTERVAL_MINUTES) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runCtx := correlation.ContextFromCorrelation("")
+			if err := j.Run(runCtx); err != nil {
+				logger.Logger(runCtx).Errorf("limit review failed: %v", err)
+			}
+		}
+	}
+}
+
+// Run reviews every customer with an active limit expiring within the review window.
+// Every instance of the service starts the job, the review runs in whichever takes the lock.
+func (j *LimitReviewJob) Run(ctx context.Context) error {
+	log := logger.Logger(ctx)
+
+	unlock, locked, err := j.Locker.TryLock(ctx, LOCK_NAME)
+	if err != nil {
+		return err
+	}
+
+	if !locked {
+		log.Info("limit review is running in another instance")
+		return nil
+	}
+	defer unlock()
+
+	before := time.Now().AddDate(0, 0, j.Config.LIMIT_REVIEW_WINDOW_DAYS)
+
+	dueLimits, err := j.CustomerLimitDBClient.ListDueForReview(ctx, before)
+	if err != nil {
+		return err
+	}
+
+	byCustomer := map[int][]customerLimitDBModels.CustomerLimit{}
+	for _, customerLimit := range dueLimits {
+		byCustomer[customerLimit.CustomerID] = append(byCustomer[customerLimit.CustomerID], customerLimit)
+	}
+
+	for customerID, customerLimits := range byCustomer {
+		// A failing customer must not block the review of the others
+		if err := j.reviewCustomer(ctx, customerID, customerLimits); err != nil {
+			log.Errorf("limit review of customer %d failed: %v", customerID, err)
+		}
+	}
+
+	return nil
+}
+
+// reviewCustomer re-scores the customer and renews, reduces or suspends the given limits.
+// Limits are never raised by a review; the new limit of a tenor is the lower of the granted and
+// the re-scored one. The granted limit includes what is in use, so using credit does not reduce it.
+func (j *LimitReviewJob) reviewCustomer(ctx context.Context, customerID int, customerLimits []customerLimitDBModels.CustomerLimit) error {
+	review := customerLimitReviewDBModels.CustomerLimitReview{
+		CustomerID: customerID,
+		Outcome:    customerLimitReviewDBModels.OUTCOME_SUSPENDED,
+		CreatedAt:  time.Now(),
+	}
+
+	customer, err := j.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_ID: customerID})
+	if err != nil {
+		return err
+	}
+
+	profile, err := j.CustomerProfileDBClient.Get(ctx, map[string]interface{}{customerProfileDBModels.COLUMN_CUSTOMER_ID: customerID})
+	if err != nil {
+		return err
+	}
+
+	// Limits of a customer without a verified identity are not renewed and get suspended
+	decision := scoring.Decision{}
+	if profile.ID != 0 && customer.IsKycVerified() {
+		d, score, err := j.CreditService.Score(ctx, profile)
+		if err != nil {
+			return err
+		}
+
+		decision = d
+		review.CreditScoreID = &score.ID
+		review.Grade = score.Grade
+		review.Score = score.Score
+	}
+
+	utilizations, err := j.CustomerLimitDBClient.Utilization(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	granted := map[int]float32{}
+	for _, utilization := range utilizations {
+		granted[utilization.Tenor] = utilization.Granted()
+	}
+
+	changes := []LimitChange{}
+	limits := map[int]float32{}
+	suspended := true
+
+	for _, customerLimit := range customerLimits {
+		oldAmount := granted[customerLimit.Tenor]
+
+		newAmount := decision.Limits[customerLimit.Tenor]
+		if newAmount > oldAmount {
+			newAmount = oldAmount
+		}
+
+		if newAmount > 0 {
+			suspended = false
+		}
+
+		if newAmount < oldAmount {
+			review.Outcome = customerLimitReviewDBModels.OUTCOME_REDUCED
+		}
+
+		limits[customerLimit.Tenor] = newAmount
+		changes = append(changes, LimitChange{
+			Tenor:          customerLimit.Tenor,
+			OldLimitAmount: oldAmount,
+			NewLimitAmount: newAmount,
+		})
+	}
+
+	reason := limitService.REASON_LIMIT_REVIEW + ": customer profile not found"
+	if profile.ID != 0 {
+		reason = credit.Reason(limitService.REASON_LIMIT_REVIEW, decision)
+	}
+
+	switch {
+	case suspended:
+		review.Outcome = customerLimitReviewDBModels.OUTCOME_SUSPENDED
+
+		for _, customerLimit := range customerLimits {
+			filter := map[string]interface{}{
+				customerLimitDBModels.COLUMN_ID: customerLimit.ID,
+			}
+
+			patcher := map[string]interface{}{
+				customerLimitDBModels.COLUMN_STATUS:     customerLimitDBModels.STATUS_SUSPENDED,
+				customerLimitDBModels.COLUMN_UPDATED_AT: time.Now(),
+			}
+
+			if err := j.LimitService.Update(ctx, filter, patcher, limitService.ACTOR_SYSTEM, reason); err != nil {
+				return err
+			}
+		}
+	default:
+		if review.Outcome != customerLimitReviewDBModels.OUTCOME_REDUCED {
+			review.Outcome = customerLimitReviewDBModels.OUTCOME_RENEWED
+		}
+
+		if err := j.CreditService.ApplyLimits(ctx, customerID, limits, limitService.ACTOR_SYSTEM, reason); err != nil {
+			return err
+		}
+	}
+
+	details, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	review.Details = postgres.Jsonb{RawMessage: details}
+
+	if err := review.Validate(); err != nil {
+		return err
+	}
+
+	return j.CustomerLimitReviewDBClient.Create(ctx, &review)
+}
@@ -0,0 +1,127 @@
+package scope
+
+import (
+	"context"
+	"errors"
+
+	"kredit-plus/app/constants"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerDB "kredit-plus/app/db/repository/customer"
+	"kredit-plus/app/service/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Scope limits the records a caller can reach. The zero Scope reaches no record.
+type Scope struct {
+	// All reaches every record, it is only set for callers holding a permission for all customers
+	All bool
+	// CustomerID limits the scope to the records of one customer
+	CustomerID int
+	// SalesChannel limits the scope to the records sold through one channel
+	SalesChannel string
+}
+
+// Permissions names the permissions that widen the scope of a caller on one kind of record.
+type Permissions struct {
+	ReadAll      string
+	WriteAll     string
+	ReadChannel  string
+	WriteChannel string
+}
+
+var TRANSACTIONS = Permissions{
+	ReadAll:      rbac.PERMISSION_TRANSACTIONS_READ_ALL,
+	WriteAll:     rbac.PERMISSION_TRANSACTIONS_WRITE_ALL,
+	ReadChannel:  rbac.PERMISSION_TRANSACTIONS_READ_CHANNEL,
+	WriteChannel: rbac.PERMISSION_TRANSACTIONS_WRITE_CHANNEL,
+}
+
+// ALL reaches every record, e.g. for the service acting on its own behalf.
+var ALL = Scope{All: true}
+
+var (
+	ErrUnknownCaller = errors.New(constants.UNAUTHORIZED_ACCESS)
+	// ErrEmptyScope is returned when a record is written within a scope that reaches no record.
+	ErrEmptyScope = errors.New("the scope reaches no record")
+)
+
+// IScopeService decides which records a caller can reach.
+type IScopeService interface {
+	Resolve(ctx context.Context, customerUUID string, permissions Permissions, write bool) (Scope, error)
+}
+
+type ScopeService struct {
+	CustomerDBClient customerDB.ICustomerRepository
+	RBACService      rbac.IRBACService
+}
+
+func NewScopeService(CustomerClient customerDB.ICustomerRepository, RBACService rbac.IRBACService) *ScopeService {
+	return &ScopeService{
+		CustomerDBClient: CustomerClient,
+		RBACService:      RBACService,
+	}
+}
+
+// Resolve returns the scope of the customer with the given UUID. Customers reach their own
+// records. Roles holding the permission for all customers reach every record, and partners
+// holding the channel permission reach the records of their sales channel.
+func (s *ScopeService) Resolve(ctx context.Context, customerUUID string, permissions Permissions, write bool) (Scope, error) {
+	customer, err := s.CustomerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_UUID: customerUUID})
+	if err != nil {
+		return Scope{}, err
+	}
+
+	if customer.ID == 0 {
+		return Scope{}, ErrUnknownCaller
+	}
+
+	all, channel := permissions.ReadAll, permissions.ReadChannel
+	if write {
+		all, channel = permissions.WriteAll, permissions.WriteChannel
+	}
+
+	allowed, err := s.RBACService.HasPermission(ctx, customer.Role, all)
+	if err != nil {
+		return Scope{}, err
+	}
+
+	if allowed {
+		return ALL, nil
+	}
+
+	if customer.SalesChannel != "" {
+		allowed, err := s.RBACService.HasPermission(ctx, customer.Role, channel)
+		if err != nil {
+			return Scope{}, err
+		}
+
+		if allowed {
+			return Scope{SalesChannel: customer.SalesChannel}, nil
+		}
+	}
+
+	return Scope{CustomerID: customer.ID}, nil
+}
+
+// FromContext returns the scope the Scoped middleware put on the request. Handlers must not
+// fall back to an unscoped query when there is none.
+func FromContext(ctx *gin.Context) (Scope, bool) {
+	value, exist := ctx.Get(constants.CTK_SCOPE_KEY.String())
+	if !exist {
+		return Scope{}, false
+	}
+
+	s, ok := value.(Scope)
+	return s, ok
+}
+
+// IsCustomer reports whether the scope is limited to the records of the caller.
+func (s Scope) IsCustomer() bool {
+	return s.CustomerID != 0
+}
+
+// IsEmpty reports whether the scope reaches no record.
+func (s Scope) IsEmpty() bool {
+	return !s.All && s.CustomerID == 0 && s.SalesChannel == ""
+}
@@ -0,0 +1,115 @@
+package scoring
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"kredit-plus/config"
+)
+
+// NewScoringEngineFromConfig builds the weighted scorecard and limit matrix from the scoring config.
+func NewScoringEngineFromConfig(cfg config.ScoringConfig) (*ScoringEngine, error) {
+	salaryBands, err := parseBands(cfg.SCORING_SALARY_BANDS)
+	if err != nil {
+		return nil, fmt.Errorf("SCORING_SALARY_BANDS: %w", err)
+	}
+
+	ageBands, err := parseBands(cfg.SCORING_AGE_BANDS)
+	if err != nil {
+		return nil, fmt.Errorf("SCORING_AGE_BANDS: %w", err)
+	}
+
+	grades, err := parseNamedValues(cfg.SCORING_GRADE_BANDS)
+	if err != nil {
+		return nil, fmt.Errorf("SCORING_GRADE_BANDS: %w", err)
+	}
+
+	gradeBands := []GradeBand{}
+	for grade, minScore := range grades {
+		gradeBands = append(gradeBands, GradeBand{Grade: grade, MinScore: minScore})
+	}
+
+	gradeMultipliers, err := parseNamedValues(cfg.SCORING_GRADE_MULTIPLIERS)
+	if err != nil {
+		return nil, fmt.Errorf("SCORING_GRADE_MULTIPLIERS: %w", err)
+	}
+
+	tenorFactors, err := ParseTenorFactors(cfg.SCORING_TENOR_FACTORS)
+	if err != nil {
+		return nil, fmt.Errorf("SCORING_TENOR_FACTORS: %w", err)
+	}
+
+	scorecard := NewWeightedScorecard(cfg.SCORING_SALARY_WEIGHT, cfg.SCORING_AGE_WEIGHT, cfg.SCORING_KYC_WEIGHT, salaryBands, ageBands, gradeBands)
+	matrix := NewLimitMatrix(gradeMultipliers, tenorFactors, cfg.SCORING_MAX_LIMIT)
+
+	return NewScoringEngine(scorecard, matrix), nil
+}
+
+// ParseTenorFactors parses "tenor:factor" pairs, the tenors are in months.
+func ParseTenorFactors(values []string) (map[int]float64, error) {
+	factors, err := parseNamedValues(values)
+	if err != nil {
+		return nil, err
+	}
+
+	tenorFactors := map[int]float64{}
+	for tenor, factor := range factors {
+		t, err := strconv.Atoi(tenor)
+		if err != nil || t <= 0 {
+			return nil, fmt.Errorf("invalid tenor %q", tenor)
+		}
+		tenorFactors[t] = factor
+	}
+	return tenorFactors, nil
+}
+
+// parseBands parses "threshold:points" pairs.
+func parseBands(values []string) ([]Band, error) {
+	named, err := parseNamedValues(values)
+	if err != nil {
+		return nil, err
+	}
+
+	bands := []Band{}
+	for name, points := range named {
+		threshold, err := strconv.ParseFloat(name, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold %q", name)
+		}
+		bands = append(bands, Band{Threshold: threshold, Points: points})
+	}
+	return bands, nil
+}
+
+// parseNamedValues parses "name:value" pairs. A malformed entry is an error rather than
+// being skipped, a typo would otherwise silently zero a weight or drop a tenor.
+func parseNamedValues(values []string) (map[string]float64, error) {
+	result := map[string]float64{}
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+
+		name, number, ok := strings.Cut(strings.TrimSpace(value), ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("entry %q must have the form name:value", value)
+		}
+
+		v, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+		if err != nil {
+			return nil, fmt.Errorf("entry %q has an invalid value", value)
+		}
+
+		if _, exist := result[name]; exist {
+			return nil, fmt.Errorf("%q is listed twice", name)
+		}
+		result[name] = v
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("at least one name:value entry is required")
+	}
+	return result, nil
+}
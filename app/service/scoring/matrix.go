@@ -0,0 +1,57 @@
+package scoring
+
+import (
+	"math"
+	"sort"
+)
+
+// LimitMatrix maps a grade and a monthly salary to a limit for every tenor.
+// The limit of a tenor is salary * grade multiplier * tenor factor, capped at MaxLimit.
+type LimitMatrix struct {
+	GradeMultipliers map[string]float64
+	TenorFactors     map[int]float64
+	MaxLimit         float64
+}
+
+func NewLimitMatrix(gradeMultipliers map[string]float64, tenorFactors map[int]float64, maxLimit float64) LimitMatrix {
+	return LimitMatrix{
+		GradeMultipliers: gradeMultipliers,
+		TenorFactors:     tenorFactors,
+		MaxLimit:         maxLimit,
+	}
+}
+
+// Limits returns the limit for every configured tenor. Grades without a multiplier get a zero limit.
+func (m LimitMatrix) Limits(grade string, salary float32) map[int]float32 {
+	limits := make(map[int]float32, len(m.TenorFactors))
+	multiplier := m.GradeMultipliers[grade]
+
+	for tenor, factor := range m.TenorFactors {
+		limit := float64(salary) * multiplier * factor
+		if m.MaxLimit > 0 && limit > m.MaxLimit {
+			limit = m.MaxLimit
+		}
+		limits[tenor] = float32(math.Round(limit*100) / 100)
+	}
+
+	return limits
+}
+
+// Tenors returns the configured tenors in ascending order.
+func (m LimitMatrix) Tenors() []int {
+	tenors := make([]int, 0, len(m.TenorFactors))
+	for tenor := range m.TenorFactors {
+		tenors = append(tenors, tenor)
+	}
+	sort.Ints(tenors)
+
+	return tenors
+}
+
+// Cap lowers the limit to MaxLimit. A zero MaxLimit does not cap.
+func (m LimitMatrix) Cap(limit float32) float32 {
+	if m.MaxLimit > 0 && float64(limit) > m.MaxLimit {
+		return float32(m.MaxLimit)
+	}
+	return limit
+}
@@ -0,0 +1,176 @@
+package scoring
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	customerProfileDBModels "kredit-plus/app/db/dto/customer_profile"
+	"kredit-plus/app/service/util"
+)
+
+const (
+	FACTOR_SALARY = "salary"
+	FACTOR_AGE    = "age"
+	FACTOR_KYC    = "kyc"
+
+	WEIGHTED_SCORECARD = "weighted-v1"
+)
+
+// Band awards Points to any value greater than or equal to Threshold.
+type Band struct {
+	Threshold float64
+	Points    float64
+}
+
+// GradeBand assigns Grade to any score greater than or equal to MinScore.
+type GradeBand struct {
+	Grade    string
+	MinScore float64
+}
+
+// WeightedScorecard scores salary, age and KYC completeness against configurable
+// bands and combines them as a weighted average on a 0-100 scale.
+type WeightedScorecard struct {
+	SalaryWeight float64
+	AgeWeight    float64
+	KycWeight    float64
+	SalaryBands  []Band
+	AgeBands     []Band
+	GradeBands   []GradeBand
+	Now          func() time.Time
+}
+
+func NewWeightedScorecard(salaryWeight, ageWeight, kycWeight float64, salaryBands, ageBands []Band, gradeBands []GradeBand) *WeightedScorecard {
+	sort.Slice(salaryBands, func(i, j int) bool { return salaryBands[i].Threshold < salaryBands[j].Threshold })
+	sort.Slice(ageBands, func(i, j int) bool { return ageBands[i].Threshold < ageBands[j].Threshold })
+	sort.Slice(gradeBands, func(i, j int) bool { return gradeBands[i].MinScore > gradeBands[j].MinScore })
+
+	return &WeightedScorecard{
+		SalaryWeight: salaryWeight,
+		AgeWeight:    ageWeight,
+		KycWeight:    kycWeight,
+		SalaryBands:  salaryBands,
+		AgeBands:     ageBands,
+		GradeBands:   gradeBands,
+		Now:          time.Now,
+	}
+}
+
+func (s *WeightedScorecard) Name() string {
+	return WEIGHTED_SCORECARD
+}
+
+func (s *WeightedScorecard) Score(ctx context.Context, profile customerProfileDBModels.CustomerProfile) (Result, error) {
+	factors := []Factor{
+		s.salaryFactor(profile),
+		s.ageFactor(profile),
+		s.kycFactor(profile),
+	}
+
+	totalWeight := 0.0
+	score := 0.0
+	for _, factor := range factors {
+		totalWeight += factor.Weight
+		score += factor.Score
+	}
+
+	if totalWeight > 0 {
+		score = score / totalWeight
+	}
+	score = math.Round(score*100) / 100
+
+	return Result{
+		Scorecard: s.Name(),
+		Score:     score,
+		Grade:     s.grade(score),
+		Factors:   factors,
+	}, nil
+}
+
+func (s *WeightedScorecard) salaryFactor(profile customerProfileDBModels.CustomerProfile) Factor {
+	points := bandPoints(s.SalaryBands, float64(profile.Salary))
+
+	return Factor{
+		Name:   FACTOR_SALARY,
+		Value:  fmt.Sprintf("%.2f", profile.Salary),
+		Points: points,
+		Weight: s.SalaryWeight,
+		Score:  points * s.SalaryWeight,
+		Reason: fmt.Sprintf("monthly salary of %.2f falls in the %.0f point band", profile.Salary, points),
+	}
+}
+
+func (s *WeightedScorecard) ageFactor(profile customerProfileDBModels.CustomerProfile) Factor {
+	factor := Factor{
+		Name:   FACTOR_AGE,
+		Value:  profile.DateOfBirth.String(),
+		Weight: s.AgeWeight,
+	}
+
+	if profile.DateOfBirth.IsZero() {
+		factor.Reason = "date of birth is missing"
+		return factor
+	}
+
+	age := util.Age(profile.DateOfBirth.Time, s.Now())
+	factor.Points = bandPoints(s.AgeBands, float64(age))
+	factor.Score = factor.Points * s.AgeWeight
+	factor.Reason = fmt.Sprintf("age of %d falls in the %.0f point band", age, factor.Points)
+
+	return factor
+}
+
+func (s *WeightedScorecard) kycFactor(profile customerProfileDBModels.CustomerProfile) Factor {
+	points := 0.0
+	reason := "no KYC images provided"
+
+	switch {
+	case profile.KtpImage != "" && profile.SelfieImage != "":
+		points = 100
+		reason = "KTP and selfie images provided"
+	case profile.KtpImage != "":
+		points = 50
+		reason = "only the KTP image was provided"
+	case profile.SelfieImage != "":
+		points = 50
+		reason = "only the selfie image was provided"
+	}
+
+	return Factor{
+		Name:   FACTOR_KYC,
+		Value:  fmt.Sprintf("ktp=%t selfie=%t", profile.KtpImage != "", profile.SelfieImage != ""),
+		Points: points,
+		Weight: s.KycWeight,
+		Score:  points * s.KycWeight,
+		Reason: reason,
+	}
+}
+
+func (s *WeightedScorecard) grade(score float64) string {
+	for _, band := range s.GradeBands {
+		if score >= band.MinScore {
+			return band.Grade
+		}
+	}
+
+	if len(s.GradeBands) > 0 {
+		return s.GradeBands[len(s.GradeBands)-1].Grade
+	}
+
+	return ""
+}
+
+// bandPoints returns the points of the highest band whose threshold the value reaches.
+func bandPoints(bands []Band, value float64) float64 {
+	points := 0.0
+	for _, band := range bands {
+		if value < band.Threshold {
+			break
+		}
+		points = band.Points
+	}
+	return points
+}
@@ -0,0 +1,93 @@
+package scoring
+
+import (
+	"context"
+	"errors"
+
+	customerProfileDBModels "kredit-plus/app/db/dto/customer_profile"
+)
+
+// Scorecard turns a customer profile into a score and a risk grade.
+type Scorecard interface {
+	Name() string
+	Score(ctx context.Context, profile customerProfileDBModels.CustomerProfile) (Result, error)
+}
+
+// Factor explains how a single profile attribute contributed to a score.
+type Factor struct {
+	Name   string  `json:"name"`
+	Value  string  `json:"value"`
+	Points float64 `json:"points"`
+	Weight float64 `json:"weight"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+// Result is the outcome of a scorecard run.
+type Result struct {
+	Scorecard string   `json:"scorecard"`
+	Score     float64  `json:"score"`
+	Grade     string   `json:"grade"`
+	Factors   []Factor `json:"factors"`
+}
+
+// Decision is a scored result together with the limits granted per tenor.
+type Decision struct {
+	Result
+	Salary float32         `json:"salary"`
+	Limits map[int]float32 `json:"limits"`
+}
+
+// InputsChanged reports whether the profiles differ in an attribute the scorecard reads.
+func InputsChanged(previous customerProfileDBModels.CustomerProfile, profile customerProfileDBModels.CustomerProfile) bool {
+	return previous.Salary != profile.Salary ||
+		!previous.DateOfBirth.Equal(profile.DateOfBirth.Time) ||
+		previous.KtpImage != profile.KtpImage ||
+		previous.SelfieImage != profile.SelfieImage
+}
+
+type IScoringEngine interface {
+	Evaluate(ctx context.Context, profile customerProfileDBModels.CustomerProfile) (Decision, error)
+	Tenors() []int
+	CapLimit(limit float32) float32
+}
+
+type ScoringEngine struct {
+	Scorecard Scorecard
+	Matrix    LimitMatrix
+}
+
+func NewScoringEngine(scorecard Scorecard, matrix LimitMatrix) *ScoringEngine {
+	return &ScoringEngine{
+		Scorecard: scorecard,
+		Matrix:    matrix,
+	}
+}
+
+// Tenors returns the tenors limits are granted for.
+func (e *ScoringEngine) Tenors() []int {
+	return e.Matrix.Tenors()
+}
+
+// CapLimit lowers the limit to the maximum limit of the matrix.
+func (e *ScoringEngine) CapLimit(limit float32) float32 {
+	return e.Matrix.Cap(limit)
+}
+
+// Evaluate scores the profile and maps the resulting grade to per-tenor limits.
+func (e *ScoringEngine) Evaluate(ctx context.Context, profile customerProfileDBModels.CustomerProfile) (Decision, error) {
+	if profile.CustomerID == 0 {
+		return Decision{}, errors.New("profile is required")
+	}
+
+	result, err := e.Scorecard.Score(ctx, profile)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	return Decision{
+		Result: result,
+		Salary: profile.Salary,
+		Limits: e.Matrix.Limits(result.Grade, profile.Salary),
+	}, nil
+}
@@ -0,0 +1,38 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+
+	"kredit-plus/app/service/logger"
+	"kredit-plus/config"
+)
+
+const DRIVER_LOG = "log"
+
+// SMSSender delivers text messages to phone numbers in E.164 form.
+type SMSSender interface {
+	Send(ctx context.Context, to string, body string) error
+}
+
+// NewSenderFromConfig returns the sender named by SMS_DRIVER.
+func NewSenderFromConfig(cfg config.SmsConfig) (SMSSender, error) {
+	switch cfg.SMS_DRIVER {
+	case DRIVER_LOG:
+		return NewLogSender(), nil
+	default:
+		return nil, fmt.Errorf("unknown SMS driver %q", cfg.SMS_DRIVER)
+	}
+}
+
+// LogSender writes every message to the application log instead of sending it.
+type LogSender struct{}
+
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+func (s *LogSender) Send(ctx context.Context, to string, body string) error {
+	logger.Logger(ctx).Infof("sms to %s: %s", to, body)
+	return nil
+}
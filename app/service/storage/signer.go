@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"kredit-plus/app/service/encryption"
+)
+
+const (
+	QUERY_KEY       = "key"
+	QUERY_EXPIRES   = "expires"
+	QUERY_SIGNATURE = "signature"
+)
+
+// URLSigner issues and verifies download URLs that are only valid until they expire.
+type URLSigner struct {
+	Secret  []byte
+	BaseURL string
+	TTL     time.Duration
+}
+
+// NewURLSigner returns a signer for the secret, which must not be the placeholder of the
+// example configuration: whoever knows it can sign URLs to every stored file.
+func NewURLSigner(secret string, baseURL string, ttl time.Duration) (*URLSigner, error) {
+	if err := encryption.CheckSecret(secret); err != nil {
+		return nil, fmt.Errorf("STORAGE_SIGNING_SECRET: %w", err)
+	}
+
+	return &URLSigner{
+		Secret:  []byte(secret),
+		BaseURL: baseURL,
+		TTL:     ttl,
+	}, nil
+}
+
+// Sign returns a download URL for the key and the time it expires.
+func (s *URLSigner) Sign(key string, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(s.TTL)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set(QUERY_KEY, key)
+	query.Set(QUERY_EXPIRES, expires)
+	query.Set(QUERY_SIGNATURE, s.signature(key, expires))
+
+	return s.BaseURL + "?" + query.Encode(), expiresAt
+}
+
+// Verify reports whether the signature was issued for the key and has not expired.
+func (s *URLSigner) Verify(key string, expires string, signature string, now time.Time) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > expiresAt {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(s.signature(key, expires)))
+}
+
+func (s *URLSigner) signature(key string, expires string) string {
+	mac := hmac.New(sha256.New, s.Secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -34,6 +34,12 @@ func ApplyFilterCondition(query *gorm.DB, filter map[string]interface{}) (*gorm.
 			}
 		case int:
 			query = query.Where(fmt.Sprintf("%s = ?", condition), v)
+		case time.Time:
+			if isValidOperatorCondition(condition) {
+				query = query.Where(fmt.Sprintf("%s ?", condition), v)
+			} else {
+				query = query.Where(fmt.Sprintf("%s = ?", condition), v)
+			}
 		default:
 			return nil, fmt.Errorf("unsupported filter type for %s: %T", condition, value)
 		}
@@ -191,3 +191,12 @@ findLastSaturday:
 	day += 1
 	goto findLastSaturday
 }
+
+// Age : func to get the age in full years at the given time
+func Age(dateOfBirth time.Time, at time.Time) int {
+	age := at.Year() - dateOfBirth.Year()
+	if at.Month() < dateOfBirth.Month() || (at.Month() == dateOfBirth.Month() && at.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	return age
+}
@@ -1,9 +1,9 @@
 package util
 
 import (
+	"errors"
 	"log"
 	"regexp"
-	"strconv"
 	"strings"
 
 	"golang.org/x/crypto/bcrypt"
@@ -27,31 +27,48 @@ func IsValidEmail(email string) bool {
 	return match
 }
 
-func IsValidPhone(phone string) bool {
-	// Remove any non-digit characters from the phone number string
-	phone = strings.Map(func(r rune) rune {
-		if r >= '0' && r <= '9' {
-			return r
+const PHONE_COUNTRY_CODE = "+62"
+
+// NormalizePhone converts an Indonesian mobile number written as 08xx, 628xx or +628xx,
+// optionally with spaces, dots, dashes or parentheses, to the E.164 form +628xx.
+func NormalizePhone(phone string) (string, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
 		}
-		return -1
+		return r
 	}, phone)
 
-	// Check the length of the cleaned phone number
-	if len(phone) >= 10 && len(phone) <= 13 {
-		// If the phone number starts with "+62," replace it with "08"
-		if strings.HasPrefix(phone, "+62") {
-			phone = "08" + phone[3:]
-		}
+	var national string
+	switch {
+	case strings.HasPrefix(cleaned, PHONE_COUNTRY_CODE):
+		national = cleaned[len(PHONE_COUNTRY_CODE):]
+	case strings.HasPrefix(cleaned, "62"):
+		national = cleaned[2:]
+	case strings.HasPrefix(cleaned, "0"):
+		national = cleaned[1:]
+	default:
+		return "", errors.New("phone must start with 08, 62 or +62")
+	}
+
+	// Mobile numbers start with 8 and have 9 to 12 digits after the country code
+	if len(national) < 9 || len(national) > 12 || !strings.HasPrefix(national, "8") {
+		return "", errors.New("phone is not a valid Indonesian mobile number")
+	}
 
-		// Ensure the phone number starts with "08"
-		if strings.HasPrefix(phone, "08") {
-			// Check if the resulting phone number contains only digits
-			if _, err := strconv.Atoi(phone); err == nil {
-				return true
-			}
+	for _, r := range national {
+		if r < '0' || r > '9' {
+			return "", errors.New("phone may only contain digits")
 		}
 	}
-	return false
+
+	return PHONE_COUNTRY_CODE + national, nil
+}
+
+func IsValidPhone(phone string) bool {
+	_, err := NormalizePhone(phone)
+	return err == nil
 }
 
 func GenerateHash(password string) (string, error) {
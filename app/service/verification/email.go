@@ -0,0 +1,159 @@
+package verification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"kredit-plus/app/constants"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerVerificationTokenDBModels "kredit-plus/app/db/dto/customer_verification_token"
+	customerDB "kredit-plus/app/db/repository/customer"
+	customerVerificationTokenDB "kredit-plus/app/db/repository/customer_verification_token"
+	"kredit-plus/app/service/mailer"
+	"kredit-plus/config"
+)
+
+var (
+	ErrAlreadyVerified = errors.New(constants.EMAIL_ALREADY_VERIFIED)
+	ErrResendTooSoon   = errors.New(constants.EMAIL_VERIFICATION_TOO_SOON)
+	ErrResendLimit     = errors.New(constants.EMAIL_VERIFICATION_LIMIT_REACHED)
+	ErrInvalidToken    = errors.New(constants.INVALID_VERIFICATION_TOKEN)
+)
+
+// IEmailVerificationService proves that customers own the email address they signed up with.
+type IEmailVerificationService interface {
+	Send(ctx context.Context, customer customerDBModels.Customer) error
+	Verify(ctx context.Context, token string) (customerDBModels.Customer, error)
+}
+
+type EmailVerificationService struct {
+	Mailer                            mailer.Mailer
+	CustomerDBClient                  customerDB.ICustomerRepository
+	CustomerVerificationTokenDBClient customerVerificationTokenDB.ICustomerVerificationTokenRepository
+	Config                            config.VerificationConfig
+}
+
+func NewEmailVerificationService(Mailer mailer.Mailer, CustomerClient customerDB.ICustomerRepository, CustomerVerificationTokenClient customerVerificationTokenDB.ICustomerVerificationTokenRepository, Config config.VerificationConfig) *EmailVerificationService {
+	return &EmailVerificationService{
+		Mailer:                            Mailer,
+		CustomerDBClient:                  CustomerClient,
+		CustomerVerificationTokenDBClient: CustomerVerificationTokenClient,
+		Config:                            Config,
+	}
+}
+
+// Send issues a new verification token for the current email of the customer and mails it.
+// Requests are throttled by a cooldown between emails and a daily maximum.
+func (s *EmailVerificationService) Send(ctx context.Context, customer customerDBModels.Customer) error {
+	if customer.IsEmailVerified() {
+		return ErrAlreadyVerified
+	}
+
+	now := time.Now()
+	purpose := customerVerificationTokenDBModels.PURPOSE_EMAIL_VERIFICATION
+
+	latest, err := s.CustomerVerificationTokenDBClient.Latest(ctx, customer.ID, purpose)
+	if err != nil {
+		return err
+	}
+
+	cooldown := time.Duration(s.Config.EMAIL_VERIFICATION_RESEND_COOLDOWN_SECONDS) * time.Second
+	if latest.ID != 0 && now.Sub(latest.CreatedAt) < cooldown {
+		return ErrResendTooSoon
+	}
+
+	count, err := s.CustomerVerificationTokenDBClient.CountSince(ctx, customer.ID, purpose, now.Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+
+	if count >= s.Config.EMAIL_VERIFICATION_MAX_PER_DAY {
+		return ErrResendLimit
+	}
+
+	token, err := NewToken()
+	if err != nil {
+		return err
+	}
+
+	record := customerVerificationTokenDBModels.CustomerVerificationToken{
+		CustomerID: customer.ID,
+		Purpose:    purpose,
+		Target:     customer.Email,
+		TokenHash:  HashToken(token),
+		ExpiresAt:  now.Add(time.Duration(s.Config.EMAIL_VERIFICATION_TTL_MINUTES) * time.Minute),
+		CreatedAt:  now,
+	}
+
+	if err := record.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.CustomerVerificationTokenDBClient.Create(ctx, &record); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s?token=%s", s.Config.EMAIL_VERIFICATION_URL, url.QueryEscape(token))
+
+	return s.Mailer.Send(ctx, mailer.Message{
+		To:      customer.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Please confirm that this is your email address by opening the link below.\n\n%s\n\n"+
+			"Or enter this verification token in the app:\n\n%s\n\nThe link expires on %s.\n",
+			link, token, record.ExpiresAt.Format(time.RFC1123)),
+	})
+}
+
+// Verify consumes the token and marks the email address it was issued for as verified.
+// A token issued for an address the customer has since changed is rejected.
+func (s *EmailVerificationService) Verify(ctx context.Context, token string) (customerDBModels.Customer, error) {
+	now := time.Now()
+
+	record, err := s.CustomerVerificationTokenDBClient.Get(ctx, map[string]interface{}{
+		customerVerificationTokenDBModels.COLUMN_TOKEN_HASH: HashToken(token),
+		customerVerificationTokenDBModels.COLUMN_PURPOSE:    customerVerificationTokenDBModels.PURPOSE_EMAIL_VERIFICATION,
+	})
+	if err != nil {
+		return customerDBModels.Customer{}, err
+	}
+
+	if record.ID == 0 || !record.IsUsable(now) {
+		return customerDBModels.Customer{}, ErrInvalidToken
+	}
+
+	filter := map[string]interface{}{
+		customerDBModels.COLUMN_ID: record.CustomerID,
+	}
+
+	customer, err := s.CustomerDBClient.Get(ctx, filter)
+	if err != nil {
+		return customer, err
+	}
+
+	if customer.ID == 0 || customer.Email != record.Target {
+		return customerDBModels.Customer{}, ErrInvalidToken
+	}
+
+	used, err := s.CustomerVerificationTokenDBClient.Use(ctx, record.ID, now)
+	if err != nil {
+		return customer, err
+	}
+
+	if !used {
+		return customerDBModels.Customer{}, ErrInvalidToken
+	}
+
+	patcher := map[string]interface{}{
+		customerDBModels.COLUMN_EMAIL_VERIFIED_AT: now,
+		customerDBModels.COLUMN_UPDATED_AT:        now,
+	}
+
+	if err := s.CustomerDBClient.Update(ctx, filter, patcher); err != nil {
+		return customer, err
+	}
+
+	return s.CustomerDBClient.Get(ctx, filter)
+}
@@ -0,0 +1,187 @@
+package verification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"kredit-plus/app/constants"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerTokenDBModels "kredit-plus/app/db/dto/customer_token"
+	customerVerificationTokenDBModels "kredit-plus/app/db/dto/customer_verification_token"
+	customerDB "kredit-plus/app/db/repository/customer"
+	customerTokenDB "kredit-plus/app/db/repository/customer_token"
+	customerVerificationTokenDB "kredit-plus/app/db/repository/customer_verification_token"
+	"kredit-plus/app/service/encryption"
+	"kredit-plus/app/service/mailer"
+	"kredit-plus/app/service/sms"
+	"kredit-plus/app/service/util"
+	"kredit-plus/config"
+)
+
+const (
+	CHANNEL_EMAIL = "email"
+	CHANNEL_SMS   = "sms"
+)
+
+var (
+	ErrResetTooSoon      = errors.New(constants.TOO_MANY_REQUESTS)
+	ErrInvalidResetToken = errors.New(constants.INVALID_RESET_TOKEN)
+)
+
+// IPasswordResetService lets customers who forgot their password choose a new one.
+type IPasswordResetService interface {
+	Request(ctx context.Context, customer customerDBModels.Customer, channel string) error
+	Reset(ctx context.Context, token string, password string) (customerDBModels.Customer, error)
+}
+
+type PasswordResetService struct {
+	Mailer                            mailer.Mailer
+	SMSSender                         sms.SMSSender
+	CustomerDBClient                  customerDB.ICustomerRepository
+	CustomerTokenDBClient             customerTokenDB.ICustomerTokenRepository
+	CustomerVerificationTokenDBClient customerVerificationTokenDB.ICustomerVerificationTokenRepository
+	Config                            config.PasswordResetConfig
+}
+
+func NewPasswordResetService(Mailer mailer.Mailer, SMSSender sms.SMSSender, CustomerClient customerDB.ICustomerRepository, CustomerTokenClient customerTokenDB.ICustomerTokenRepository, CustomerVerificationTokenClient customerVerificationTokenDB.ICustomerVerificationTokenRepository, Config config.PasswordResetConfig) (*PasswordResetService, error) {
+	if err := encryption.CheckSecret(Config.PASSWORD_RESET_SECRET); err != nil {
+		return nil, fmt.Errorf("PASSWORD_RESET_SECRET: %w", err)
+	}
+
+	return &PasswordResetService{
+		Mailer:                            Mailer,
+		SMSSender:                         SMSSender,
+		CustomerDBClient:                  CustomerClient,
+		CustomerTokenDBClient:             CustomerTokenClient,
+		CustomerVerificationTokenDBClient: CustomerVerificationTokenClient,
+		Config:                            Config,
+	}, nil
+}
+
+// Request issues a signed reset token and sends it to the email address or phone number
+// of the customer. Only one token is issued per PASSWORD_RESET_COOLDOWN_SECONDS.
+func (s *PasswordResetService) Request(ctx context.Context, customer customerDBModels.Customer, channel string) error {
+	now := time.Now()
+	purpose := customerVerificationTokenDBModels.PURPOSE_PASSWORD_RESET
+
+	latest, err := s.CustomerVerificationTokenDBClient.Latest(ctx, customer.ID, purpose)
+	if err != nil {
+		return err
+	}
+
+	cooldown := time.Duration(s.Config.PASSWORD_RESET_COOLDOWN_SECONDS) * time.Second
+	if latest.ID != 0 && now.Sub(latest.CreatedAt) < cooldown {
+		return ErrResetTooSoon
+	}
+
+	token, err := NewToken()
+	if err != nil {
+		return err
+	}
+
+	token = SignToken(token, s.Config.PASSWORD_RESET_SECRET)
+
+	target := customer.Email
+	if channel == CHANNEL_SMS {
+		target = customer.Phone
+	}
+
+	record := customerVerificationTokenDBModels.CustomerVerificationToken{
+		CustomerID: customer.ID,
+		Purpose:    purpose,
+		Target:     target,
+		TokenHash:  HashToken(token),
+		ExpiresAt:  now.Add(time.Duration(s.Config.PASSWORD_RESET_TTL_MINUTES) * time.Minute),
+		CreatedAt:  now,
+	}
+
+	if err := record.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.CustomerVerificationTokenDBClient.Create(ctx, &record); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s?token=%s", s.Config.PASSWORD_RESET_URL, url.QueryEscape(token))
+
+	if channel == CHANNEL_SMS {
+		return s.SMSSender.Send(ctx, target, fmt.Sprintf("Reset your Kredit Plus password within %d minutes: %s", s.Config.PASSWORD_RESET_TTL_MINUTES, link))
+	}
+
+	return s.Mailer.Send(ctx, mailer.Message{
+		To:      target,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Someone asked to reset the password of your account. Open the link below to choose a new password.\n\n%s\n\n"+
+			"The link expires on %s. If you did not ask for this, you can ignore this email.\n",
+			link, record.ExpiresAt.Format(time.RFC1123)),
+	})
+}
+
+// Reset consumes the token, stores the new password and revokes every token of the
+// customer so all existing sessions end. A token sent to an email address or phone
+// number the customer has since changed is rejected.
+func (s *PasswordResetService) Reset(ctx context.Context, token string, password string) (customerDBModels.Customer, error) {
+	if !VerifySignedToken(token, s.Config.PASSWORD_RESET_SECRET) {
+		return customerDBModels.Customer{}, ErrInvalidResetToken
+	}
+
+	now := time.Now()
+
+	record, err := s.CustomerVerificationTokenDBClient.Get(ctx, map[string]interface{}{
+		customerVerificationTokenDBModels.COLUMN_TOKEN_HASH: HashToken(token),
+		customerVerificationTokenDBModels.COLUMN_PURPOSE:    customerVerificationTokenDBModels.PURPOSE_PASSWORD_RESET,
+	})
+	if err != nil {
+		return customerDBModels.Customer{}, err
+	}
+
+	if record.ID == 0 || !record.IsUsable(now) {
+		return customerDBModels.Customer{}, ErrInvalidResetToken
+	}
+
+	filter := map[string]interface{}{
+		customerDBModels.COLUMN_ID: record.CustomerID,
+	}
+
+	customer, err := s.CustomerDBClient.Get(ctx, filter)
+	if err != nil {
+		return customer, err
+	}
+
+	if customer.ID == 0 || (customer.Email != record.Target && customer.Phone != record.Target) {
+		return customerDBModels.Customer{}, ErrInvalidResetToken
+	}
+
+	hashedPassword, err := util.GenerateHash(password)
+	if err != nil {
+		return customer, err
+	}
+
+	used, err := s.CustomerVerificationTokenDBClient.Use(ctx, record.ID, now)
+	if err != nil {
+		return customer, err
+	}
+
+	if !used {
+		return customerDBModels.Customer{}, ErrInvalidResetToken
+	}
+
+	patcher := map[string]interface{}{
+		customerDBModels.COLUMN_PASSWORD:   hashedPassword,
+		customerDBModels.COLUMN_UPDATED_AT: now,
+	}
+
+	if err := s.CustomerDBClient.Update(ctx, filter, patcher); err != nil {
+		return customer, err
+	}
+
+	if err := s.CustomerTokenDBClient.Delete(ctx, map[string]interface{}{customerTokenDBModels.COLUMN_CUSTOMER_ID: customer.ID}); err != nil {
+		return customer, err
+	}
+
+	return s.CustomerDBClient.Get(ctx, filter)
+}
@@ -0,0 +1,80 @@
+package verification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"kredit-plus/app/constants"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerOtpDBModels "kredit-plus/app/db/dto/customer_otp"
+	customerDB "kredit-plus/app/db/repository/customer"
+	"kredit-plus/app/service/otp"
+	"kredit-plus/app/service/sms"
+)
+
+var ErrPhoneAlreadyVerified = errors.New(constants.PHONE_ALREADY_VERIFIED)
+
+// IPhoneVerificationService proves that customers own the phone number they signed up with.
+type IPhoneVerificationService interface {
+	Send(ctx context.Context, customer customerDBModels.Customer) error
+	Verify(ctx context.Context, customer customerDBModels.Customer, code string) (customerDBModels.Customer, error)
+}
+
+type PhoneVerificationService struct {
+	SMSSender        sms.SMSSender
+	OTPService       otp.IOTPService
+	CustomerDBClient customerDB.ICustomerRepository
+}
+
+func NewPhoneVerificationService(SMSSender sms.SMSSender, OTPService otp.IOTPService, CustomerClient customerDB.ICustomerRepository) *PhoneVerificationService {
+	return &PhoneVerificationService{
+		SMSSender:        SMSSender,
+		OTPService:       OTPService,
+		CustomerDBClient: CustomerClient,
+	}
+}
+
+// Send texts a one time code to the current phone number of the customer.
+func (s *PhoneVerificationService) Send(ctx context.Context, customer customerDBModels.Customer) error {
+	if customer.IsPhoneVerified() {
+		return ErrPhoneAlreadyVerified
+	}
+
+	code, err := s.OTPService.Issue(ctx, customer.ID, customerOtpDBModels.PURPOSE_PHONE_VERIFICATION, customer.Phone)
+	if err != nil {
+		return err
+	}
+
+	return s.SMSSender.Send(ctx, customer.Phone, fmt.Sprintf("Your Kredit Plus verification code is %s. Never share this code with anyone.", code))
+}
+
+// Verify checks the code and marks the phone number it was sent to as verified.
+// A code sent to a number the customer has since changed is rejected.
+func (s *PhoneVerificationService) Verify(ctx context.Context, customer customerDBModels.Customer, code string) (customerDBModels.Customer, error) {
+	if customer.IsPhoneVerified() {
+		return customer, ErrPhoneAlreadyVerified
+	}
+
+	if err := s.OTPService.Check(ctx, customer.ID, customerOtpDBModels.PURPOSE_PHONE_VERIFICATION, customer.Phone, code); err != nil {
+		return customer, err
+	}
+
+	now := time.Now()
+
+	filter := map[string]interface{}{
+		customerDBModels.COLUMN_ID: customer.ID,
+	}
+
+	patcher := map[string]interface{}{
+		customerDBModels.COLUMN_PHONE_VERIFIED_AT: now,
+		customerDBModels.COLUMN_UPDATED_AT:        now,
+	}
+
+	if err := s.CustomerDBClient.Update(ctx, filter, patcher); err != nil {
+		return customer, err
+	}
+
+	return s.CustomerDBClient.Get(ctx, filter)
+}
@@ -0,0 +1,50 @@
+package verification
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+const TOKEN_BYTES = 32
+
+// NewToken returns a random URL safe token.
+func NewToken() (string, error) {
+	b := make([]byte, TOKEN_BYTES)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex encoded SHA-256 hash under which a token is stored.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// SignToken appends an HMAC of the token keyed with the secret, so forged tokens
+// can be rejected without a database lookup.
+func SignToken(token string, secret string) string {
+	return token + "." + sign(token, secret)
+}
+
+// VerifySignedToken reports whether the signature of a token from SignToken is valid.
+func VerifySignedToken(signed string, secret string) bool {
+	i := strings.LastIndex(signed, ".")
+	if i <= 0 {
+		return false
+	}
+
+	return hmac.Equal([]byte(signed[i+1:]), []byte(sign(signed[:i], secret)))
+}
+
+func sign(token string, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,65 @@
+// Command assignrole gives the customer account with the given email address another role.
+// Use it to promote the first admin, who can then assign roles through the admin API.
+package main
+
+import (
+	"context"
+	"flag"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	customerDBModels "kredit-plus/app/db/dto/customer"
+	customerDBClient "kredit-plus/app/db/repository/customer"
+	roleDBClient "kredit-plus/app/db/repository/role"
+	rolePermissionDBClient "kredit-plus/app/db/repository/role_permission"
+	"kredit-plus/app/service/logger"
+	"kredit-plus/app/service/rbac"
+	"kredit-plus/config"
+	"time"
+)
+
+func main() {
+	email := flag.String("email", "", "email address of the customer account")
+	role := flag.String("role", constants.ADMIN.String(), "role to assign")
+	salesChannel := flag.String("sales-channel", "", "sales channel of a partner account")
+	flag.Parse()
+
+	time.Local = time.UTC
+
+	var err error
+	constants.Config, err = config.LoadConfig()
+	if err != nil {
+		panic(err.Error())
+	}
+	ctx := context.Background()
+
+	logger.InitLogger()
+	log := logger.Logger(ctx)
+
+	if *email == "" {
+		log.Fatal("-email is required")
+	}
+
+	dbConn, err := db.Init(ctx)
+	if err != nil {
+		log.Fatalf("DB connection failed with error: %v", err)
+	}
+	dbConnection := db.New(dbConn)
+
+	customerDBClient := customerDBClient.NewCustomerRepository(dbConnection)
+	RBACService := rbac.NewRBACService(customerDBClient, roleDBClient.NewRoleRepository(dbConnection), rolePermissionDBClient.NewRolePermissionRepository(dbConnection))
+
+	customer, err := customerDBClient.Get(ctx, map[string]interface{}{customerDBModels.COLUMN_EMAIL: *email})
+	if err != nil {
+		log.Fatalf("Loading the customer failed: %v", err)
+	}
+
+	if customer.ID == 0 {
+		log.Fatalf("No customer with email address %s", *email)
+	}
+
+	if _, err := RBACService.AssignRole(ctx, customer, *role, *salesChannel); err != nil {
+		log.Fatalf("Assigning role %s failed: %v", *role, err)
+	}
+
+	log.Infof("customer %s now has role %s", customer.UUID, *role)
+}
@@ -0,0 +1,79 @@
+// Command reencrypt rewrites the encrypted customer profile, profile version and KYC
+// verification columns with the current encryption key. Run it after adding a new key and
+// switching ENCRYPTION_CURRENT_KEY_ID to it, and keep the old key configured until
+// it finishes. Plaintext rows left from before encryption are encrypted as well, and
+// encrypted dates of birth are moved to the DATE column. The blind indexes of the profiles
+// are refreshed on the way.
+package main
+
+import (
+	"context"
+	"flag"
+	"kredit-plus/app/constants"
+	"kredit-plus/app/db"
+	customerProfileDBClient "kredit-plus/app/db/repository/customer_profile"
+	customerProfileVersionDBClient "kredit-plus/app/db/repository/customer_profile_version"
+	kycVerificationDBClient "kredit-plus/app/db/repository/kyc_verification"
+	"kredit-plus/app/service/encryption"
+	"kredit-plus/app/service/logger"
+	"kredit-plus/config"
+	"time"
+)
+
+// reencrypter is implemented by the repositories that hold encrypted columns.
+type reencrypter interface {
+	Reencrypt(ctx context.Context, afterID int, limit int) (int, int, error)
+}
+
+func main() {
+	batch := flag.Int("batch", 500, "number of rows read per batch")
+	flag.Parse()
+
+	time.Local = time.UTC
+
+	var err error
+	constants.Config, err = config.LoadConfig()
+	if err != nil {
+		panic(err.Error())
+	}
+	ctx := context.Background()
+
+	logger.InitLogger()
+	log := logger.Logger(ctx)
+
+	cipher, err := encryption.NewCipherFromConfig(constants.Config.EncryptionConfig)
+	if err != nil {
+		log.Fatalf("Encryption could not be configured: %v", err)
+	}
+
+	dbConn, err := db.Init(ctx)
+	if err != nil {
+		log.Fatalf("DB connection failed with error: %v", err)
+	}
+	dbConnection := db.New(dbConn)
+
+	tables := []struct {
+		name string
+		repo reencrypter
+	}{
+		{"customer_profiles", customerProfileDBClient.NewCustomerProfileRepository(dbConnection, cipher)},
+		{"customer_profile_versions", customerProfileVersionDBClient.NewCustomerProfileVersionRepository(dbConnection, cipher)},
+		{"kyc_verifications", kycVerificationDBClient.NewKycVerificationRepository(dbConnection, cipher)},
+	}
+
+	for _, table := range tables {
+		afterID, total := 0, 0
+		for {
+			lastID, rewritten, err := table.repo.Reencrypt(ctx, afterID, *batch)
+			if err != nil {
+				log.Fatalf("Re-encrypting %s failed after id %d: %v", table.name, afterID, err)
+			}
+			total += rewritten
+			if lastID == afterID {
+				break
+			}
+			afterID = lastID
+		}
+		log.Infof("re-encrypted %d rows of %s", total, table.name)
+	}
+}
@@ -16,6 +16,10 @@ type JwtConfig struct {
 	JWT_REFRESH_SECRET string `env:"JWT_REFRESH_SECRET"`
 	JWT_ACCESS_EXP     int    `env:"JWT_ACCESS_EXP"`
 	JWT_REFRESH_EXP    int    `env:"JWT_REFRESH_EXP"`
+
+	JWT_SIGNING_METHOD string   `env:"JWT_SIGNING_METHOD"`
+	JWT_CURRENT_KEY_ID string   `env:"JWT_CURRENT_KEY_ID"`
+	JWT_KEYS           []string `env:"JWT_KEYS" envSeparator:","`
 }
 
 type DatabaseConfig struct {
@@ -56,13 +60,134 @@ type LogConfig struct {
 	ACCESS_LOG_FILE_MAXAGE    int    `env:"ACCESS_LOG_FILE_MAXAGE"`
 }
 
+type ScoringConfig struct {
+	SCORING_SALARY_WEIGHT     float64  `env:"SCORING_SALARY_WEIGHT"`
+	SCORING_AGE_WEIGHT        float64  `env:"SCORING_AGE_WEIGHT"`
+	SCORING_KYC_WEIGHT        float64  `env:"SCORING_KYC_WEIGHT"`
+	SCORING_SALARY_BANDS      []string `env:"SCORING_SALARY_BANDS" envSeparator:","`
+	SCORING_AGE_BANDS         []string `env:"SCORING_AGE_BANDS" envSeparator:","`
+	SCORING_GRADE_BANDS       []string `env:"SCORING_GRADE_BANDS" envSeparator:","`
+	SCORING_GRADE_MULTIPLIERS []string `env:"SCORING_GRADE_MULTIPLIERS" envSeparator:","`
+	SCORING_TENOR_FACTORS     []string `env:"SCORING_TENOR_FACTORS" envSeparator:","`
+	SCORING_MAX_LIMIT         float64  `env:"SCORING_MAX_LIMIT"`
+}
+
+type LimitConfig struct {
+	LIMIT_VALIDITY_MONTHS         int `env:"LIMIT_VALIDITY_MONTHS"`
+	LIMIT_REVIEW_WINDOW_DAYS      int `env:"LIMIT_REVIEW_WINDOW_DAYS"`
+	LIMIT_REVIEW_INTERVAL_MINUTES int `env:"LIMIT_REVIEW_INTERVAL_MINUTES"`
+}
+
+type StorageConfig struct {
+	STORAGE_LOCAL_ROOT      string `env:"STORAGE_LOCAL_ROOT"`
+	STORAGE_SIGNING_SECRET  string `env:"STORAGE_SIGNING_SECRET"`
+	STORAGE_DOWNLOAD_URL    string `env:"STORAGE_DOWNLOAD_URL"`
+	STORAGE_URL_TTL_SECONDS int    `env:"STORAGE_URL_TTL_SECONDS"`
+}
+
+type KycConfig struct {
+	KYC_MAX_IMAGE_SIZE   int64 `env:"KYC_MAX_IMAGE_SIZE"`
+	KYC_MIN_IMAGE_WIDTH  int   `env:"KYC_MIN_IMAGE_WIDTH"`
+	KYC_MIN_IMAGE_HEIGHT int   `env:"KYC_MIN_IMAGE_HEIGHT"`
+	KYC_MAX_IMAGE_WIDTH  int   `env:"KYC_MAX_IMAGE_WIDTH"`
+	KYC_MAX_IMAGE_HEIGHT int   `env:"KYC_MAX_IMAGE_HEIGHT"`
+
+	KYC_PROVIDER             string  `env:"KYC_PROVIDER"`
+	KYC_FACE_MATCH_THRESHOLD float64 `env:"KYC_FACE_MATCH_THRESHOLD"`
+}
+
+type MailConfig struct {
+	MAIL_DRIVER   string `env:"MAIL_DRIVER"`
+	MAIL_FROM     string `env:"MAIL_FROM"`
+	MAIL_FILE_DIR string `env:"MAIL_FILE_DIR"`
+	SMTP_HOST     string `env:"SMTP_HOST"`
+	SMTP_PORT     string `env:"SMTP_PORT"`
+	SMTP_USERNAME string `env:"SMTP_USERNAME"`
+	SMTP_PASSWORD string `env:"SMTP_PASSWORD"`
+}
+
+type VerificationConfig struct {
+	EMAIL_VERIFICATION_URL                     string `env:"EMAIL_VERIFICATION_URL"`
+	EMAIL_VERIFICATION_TTL_MINUTES             int    `env:"EMAIL_VERIFICATION_TTL_MINUTES"`
+	EMAIL_VERIFICATION_RESEND_COOLDOWN_SECONDS int    `env:"EMAIL_VERIFICATION_RESEND_COOLDOWN_SECONDS"`
+	EMAIL_VERIFICATION_MAX_PER_DAY             int    `env:"EMAIL_VERIFICATION_MAX_PER_DAY"`
+}
+
+type PasswordResetConfig struct {
+	PASSWORD_RESET_SECRET           string `env:"PASSWORD_RESET_SECRET"`
+	PASSWORD_RESET_URL              string `env:"PASSWORD_RESET_URL"`
+	PASSWORD_RESET_TTL_MINUTES      int    `env:"PASSWORD_RESET_TTL_MINUTES"`
+	PASSWORD_RESET_COOLDOWN_SECONDS int    `env:"PASSWORD_RESET_COOLDOWN_SECONDS"`
+}
+
+type PasswordPolicyConfig struct {
+	PASSWORD_MIN_LENGTH      int  `env:"PASSWORD_MIN_LENGTH"`
+	PASSWORD_MAX_LENGTH      int  `env:"PASSWORD_MAX_LENGTH"`
+	PASSWORD_REQUIRE_UPPER   bool `env:"PASSWORD_REQUIRE_UPPER"`
+	PASSWORD_REQUIRE_LOWER   bool `env:"PASSWORD_REQUIRE_LOWER"`
+	PASSWORD_REQUIRE_DIGIT   bool `env:"PASSWORD_REQUIRE_DIGIT"`
+	PASSWORD_REQUIRE_SYMBOL  bool `env:"PASSWORD_REQUIRE_SYMBOL"`
+	PASSWORD_REJECT_BREACHED bool `env:"PASSWORD_REJECT_BREACHED"`
+}
+
+type EncryptionConfig struct {
+	ENCRYPTION_KEY_PROVIDER    string   `env:"ENCRYPTION_KEY_PROVIDER"`
+	ENCRYPTION_CURRENT_KEY_ID  string   `env:"ENCRYPTION_CURRENT_KEY_ID"`
+	ENCRYPTION_KEYS            []string `env:"ENCRYPTION_KEYS" envSeparator:","`
+	ENCRYPTION_KEY_FILE        string   `env:"ENCRYPTION_KEY_FILE"`
+	ENCRYPTION_BLIND_INDEX_KEY string   `env:"ENCRYPTION_BLIND_INDEX_KEY"`
+}
+
+type EligibilityConfig struct {
+	ELIGIBILITY_MIN_AGE int `env:"ELIGIBILITY_MIN_AGE"`
+	ELIGIBILITY_MAX_AGE int `env:"ELIGIBILITY_MAX_AGE"`
+}
+
+type SessionConfig struct {
+	SESSION_MAX_PER_CUSTOMER int `env:"SESSION_MAX_PER_CUSTOMER"`
+}
+
+type DuplicateConfig struct {
+	DUPLICATE_SCAN_INTERVAL_MINUTES int     `env:"DUPLICATE_SCAN_INTERVAL_MINUTES"`
+	DUPLICATE_NAME_SIMILARITY       float64 `env:"DUPLICATE_NAME_SIMILARITY"`
+	DUPLICATE_SELFIE_HASH_DISTANCE  int     `env:"DUPLICATE_SELFIE_HASH_DISTANCE"`
+}
+
+type SmsConfig struct {
+	SMS_DRIVER string `env:"SMS_DRIVER"`
+}
+
+type OtpConfig struct {
+	OTP_SECRET                  string `env:"OTP_SECRET"`
+	OTP_LENGTH                  int    `env:"OTP_LENGTH"`
+	OTP_TTL_SECONDS             int    `env:"OTP_TTL_SECONDS"`
+	OTP_MAX_ATTEMPTS            int    `env:"OTP_MAX_ATTEMPTS"`
+	OTP_RESEND_COOLDOWN_SECONDS int    `env:"OTP_RESEND_COOLDOWN_SECONDS"`
+}
+
 type ServiceConfig struct {
 	ProjectVersion   string `env:"VERSION"`
 	JwtConfig        JwtConfig
 	DatabaseConfig   DatabaseConfig
 	HTTPServerConfig HTTPServerConfig
 	LogConfig        LogConfig
-	Environment      string `env:"ENVIRONMENT"`
+	ScoringConfig    ScoringConfig
+	LimitConfig      LimitConfig
+	StorageConfig    StorageConfig
+	KycConfig        KycConfig
+	MailConfig       MailConfig
+	SmsConfig        SmsConfig
+	OtpConfig        OtpConfig
+
+	VerificationConfig VerificationConfig
+
+	PasswordResetConfig  PasswordResetConfig
+	PasswordPolicyConfig PasswordPolicyConfig
+	EncryptionConfig     EncryptionConfig
+	DuplicateConfig      DuplicateConfig
+	EligibilityConfig    EligibilityConfig
+	SessionConfig        SessionConfig
+	Environment          string `env:"ENVIRONMENT"`
 }
 
 var Config *ServiceConfig